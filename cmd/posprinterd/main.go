@@ -0,0 +1,99 @@
+// Package main implements posprinterd, an HTTP print server for the printers of a registry
+// configuration file (see pkg/server for the API).
+//
+// Usage:
+//
+//	posprinterd -config printers.yaml -addr :8080 -spool ./spool
+//
+// API keys are read from -api-keys or from the POS_PRINTER_API_KEYS environment variable,
+// separated by commas. Without keys the API is not authenticated.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/adcondev/pos-printer/pkg/registry"
+	"github.com/adcondev/pos-printer/pkg/server"
+)
+
+// shutdownTimeout bounds the wait for open requests on shutdown
+const shutdownTimeout = 10 * time.Second
+
+func main() {
+	configPath := flag.String("config", "printers.yaml", "printer registry file (JSON or YAML)")
+	addr := flag.String("addr", ":8080", "listen address")
+	spool := flag.String("spool", "spool", "job spool directory")
+	apiKeys := flag.String("api-keys", os.Getenv("POS_PRINTER_API_KEYS"), "comma-separated API keys")
+	maxBody := flag.Int64("max-body", server.DefaultMaxBodyBytes, "maximum request body size in bytes")
+	flag.Parse()
+
+	reg, err := registry.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load printers: %v", err)
+	}
+	defer func() {
+		if err := reg.Close(); err != nil {
+			log.Printf("Failed to close printers: %v", err)
+		}
+	}()
+
+	srv, err := server.New(&server.Config{
+		Registry:     reg,
+		SpoolDir:     *spool,
+		APIKeys:      splitKeys(*apiKeys),
+		MaxBodyBytes: *maxBody,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create server: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := srv.Start(ctx); err != nil {
+		log.Fatalf("Failed to start job queue: %v", err)
+	}
+	defer srv.Stop()
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           srv,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Shutdown: %v", err)
+		}
+	}()
+
+	log.Printf("Serving %d printers on %s", len(reg.Names()), *addr)
+	if len(splitKeys(*apiKeys)) == 0 {
+		log.Printf("Warning: no API keys configured, the API is not authenticated")
+	}
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("Server failed: %v", err)
+	}
+}
+
+// splitKeys parses a comma-separated list of API keys
+func splitKeys(s string) []string {
+	var keys []string
+	for _, key := range strings.Split(s, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
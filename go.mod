@@ -3,10 +3,13 @@ module github.com/adcondev/pos-printer
 go 1.24.6
 
 require (
+	github.com/boombuler/barcode v1.1.0
 	github.com/yeqown/go-qrcode/v2 v2.2.5
 	github.com/yeqown/go-qrcode/writer/standard v1.3.0
 	golang.org/x/image v0.33.0
+	golang.org/x/sys v0.38.0
 	golang.org/x/text v0.31.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
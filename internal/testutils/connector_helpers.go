@@ -0,0 +1,207 @@
+package testutils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrFakeClosed is returned by FakeConnector after Close
+var ErrFakeClosed = errors.New("fake connector closed")
+
+// FakeConnector is an in-memory bidirectional connector. It records every write and
+// serves bytes queued with Feed or produced by Responder to readers.
+type FakeConnector struct {
+	// Responder, when set, is called with every write; its result is queued for reading
+	Responder func(written []byte) []byte
+	// WriteErr, when set, is returned by every write
+	WriteErr error
+	// ReadTimeout bounds reads without a context deadline (default 1s)
+	ReadTimeout time.Duration
+
+	mu       sync.Mutex
+	written  bytes.Buffer
+	writes   [][]byte
+	inbox    []byte
+	notify   chan struct{}
+	closed   bool
+	deadline time.Time
+}
+
+// NewFakeConnector creates an empty FakeConnector
+func NewFakeConnector() *FakeConnector {
+	return &FakeConnector{notify: make(chan struct{}, 1)}
+}
+
+// Write records data
+func (f *FakeConnector) Write(data []byte) (int, error) {
+	return f.WriteContext(context.Background(), data)
+}
+
+// WriteContext records data unless ctx is done
+func (f *FakeConnector) WriteContext(ctx context.Context, data []byte) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return 0, ErrFakeClosed
+	}
+	if f.WriteErr != nil {
+		f.mu.Unlock()
+		return 0, f.WriteErr
+	}
+	f.written.Write(data)
+	f.writes = append(f.writes, append([]byte(nil), data...))
+	responder := f.Responder
+	f.mu.Unlock()
+
+	if responder != nil {
+		if reply := responder(data); len(reply) > 0 {
+			f.Feed(reply)
+		}
+	}
+	return len(data), nil
+}
+
+// Read returns queued bytes, waiting up to ReadTimeout for them
+func (f *FakeConnector) Read(buf []byte) (int, error) {
+	return f.ReadContext(context.Background(), buf)
+}
+
+// ReadContext returns queued bytes, waiting until data arrives, ctx is done or the deadline passes
+func (f *FakeConnector) ReadContext(ctx context.Context, buf []byte) (int, error) {
+	f.mu.Lock()
+	until := f.deadline
+	if until.IsZero() {
+		timeout := f.ReadTimeout
+		if timeout == 0 {
+			timeout = time.Second
+		}
+		until = time.Now().Add(timeout)
+	}
+	f.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(until))
+	defer timer.Stop()
+
+	for {
+		f.mu.Lock()
+		if len(f.inbox) > 0 {
+			n := copy(buf, f.inbox)
+			f.inbox = f.inbox[n:]
+			f.mu.Unlock()
+			return n, nil
+		}
+		if f.closed {
+			f.mu.Unlock()
+			return 0, ErrFakeClosed
+		}
+		f.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-timer.C:
+			return 0, os.ErrDeadlineExceeded
+		case <-f.notify:
+		}
+	}
+}
+
+// SetReadDeadline sets the absolute read deadline (zero restores ReadTimeout)
+func (f *FakeConnector) SetReadDeadline(t time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deadline = t
+	return nil
+}
+
+// SetWriteDeadline is a no-op; writes never block
+func (f *FakeConnector) SetWriteDeadline(_ time.Time) error {
+	return nil
+}
+
+// Close marks the connector closed and wakes up pending readers
+func (f *FakeConnector) Close() error {
+	f.mu.Lock()
+	f.closed = true
+	f.mu.Unlock()
+	f.wake()
+	return nil
+}
+
+// Feed queues data as if the printer had sent it
+func (f *FakeConnector) Feed(data []byte) {
+	f.mu.Lock()
+	f.inbox = append(f.inbox, data...)
+	f.mu.Unlock()
+	f.wake()
+}
+
+// Written returns a copy of all bytes written so far
+func (f *FakeConnector) Written() []byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]byte(nil), f.written.Bytes()...)
+}
+
+// Writes returns a copy of each individual write call
+func (f *FakeConnector) Writes() [][]byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([][]byte, len(f.writes))
+	copy(out, f.writes)
+	return out
+}
+
+// IsClosed reports whether Close was called
+func (f *FakeConnector) IsClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+func (f *FakeConnector) wake() {
+	select {
+	case f.notify <- struct{}{}:
+	default:
+	}
+}
+
+// WriteOnlyConnector is an in-memory connector without read support, like a spooler
+type WriteOnlyConnector struct {
+	mu      sync.Mutex
+	written bytes.Buffer
+	closed  bool
+}
+
+// Write records data
+func (w *WriteOnlyConnector) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrFakeClosed
+	}
+	return w.written.Write(data)
+}
+
+// Close marks the connector closed
+func (w *WriteOnlyConnector) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	return nil
+}
+
+// Written returns a copy of all bytes written so far
+func (w *WriteOnlyConnector) Written() []byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]byte(nil), w.written.Bytes()...)
+}
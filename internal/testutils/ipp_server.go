@@ -0,0 +1,223 @@
+package testutils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// IPP codes used by IPPServer
+const (
+	ippPrintJob         = 0x0002
+	ippGetJobAttributes = 0x0009
+	ippOperationGroup   = 0x01
+	ippJobGroup         = 0x02
+	ippEndTag           = 0x03
+	ippInteger          = 0x21
+	ippEnum             = 0x23
+	ippText             = 0x41
+	ippKeyword          = 0x44
+	ippCharset          = 0x47
+	ippLanguage         = 0x48
+)
+
+// IPPRequest is an IPP request received by IPPServer
+type IPPRequest struct {
+	Operation   int
+	ContentType string
+	// Attributes holds the operation attributes; integers are formatted in decimal
+	Attributes map[string][]string
+	Data       []byte
+}
+
+// Attr returns the first value of an operation attribute
+func (r IPPRequest) Attr(name string) string {
+	if v := r.Attributes[name]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// IPPServer is a minimal in-process IPP server that accepts Print-Job and answers
+// Get-Job-Attributes with a scripted sequence of job states
+type IPPServer struct {
+	server *httptest.Server
+
+	mu        sync.Mutex
+	requests  []IPPRequest
+	states    []int
+	polls     int
+	status    uint16
+	statusMsg string
+	reasons   []string
+}
+
+// NewIPPServer starts an IPP server on a local port. Jobs are reported as completed
+// unless SetJobStates says otherwise. It is stopped when the test ends.
+func NewIPPServer(t *testing.T) *IPPServer {
+	t.Helper()
+
+	s := &IPPServer{states: []int{9}, reasons: []string{"none"}}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// URL returns the ipp:// URI of the server's only printer
+func (s *IPPServer) URL() string {
+	return "ipp://" + strings.TrimPrefix(s.server.URL, "http://") + "/printers/receipt"
+}
+
+// SetJobStates sets the job-state values returned by successive Get-Job-Attributes
+// requests; the last one is repeated. Print-Job always reports the job as pending.
+func (s *IPPServer) SetJobStates(states ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states, s.polls = states, 0
+}
+
+// SetJobReasons sets the job-state-reasons returned with the job state
+func (s *IPPServer) SetJobReasons(reasons ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reasons = reasons
+}
+
+// FailWith makes every following request fail with an IPP status code and message
+func (s *IPPServer) FailWith(status uint16, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status, s.statusMsg = status, message
+}
+
+// Requests returns the requests received so far
+func (s *IPPServer) Requests() []IPPRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]IPPRequest(nil), s.requests...)
+}
+
+// Jobs returns the Print-Job requests received so far
+func (s *IPPServer) Jobs() []IPPRequest {
+	return printJobs(s.Requests())
+}
+
+// serve decodes one request and writes its response
+func (s *IPPServer) serve(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil || r.Method != http.MethodPost || len(body) < 9 {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	req := IPPRequest{
+		Operation:   int(binary.BigEndian.Uint16(body[2:4])),
+		ContentType: r.Header.Get("Content-Type"),
+		Attributes:  make(map[string][]string),
+	}
+	requestID := binary.BigEndian.Uint32(body[4:8])
+
+	pos, name, group := 8, "", byte(0)
+	for pos < len(body) {
+		tag := body[pos]
+		pos++
+		if tag == ippEndTag {
+			req.Data = body[pos:]
+			break
+		}
+		if tag < 0x10 {
+			group = tag
+			continue
+		}
+		n := int(binary.BigEndian.Uint16(body[pos:]))
+		attrName := string(body[pos+2 : pos+2+n])
+		pos += 2 + n
+		n = int(binary.BigEndian.Uint16(body[pos:]))
+		value := body[pos+2 : pos+2+n]
+		pos += 2 + n
+
+		if attrName != "" {
+			name = attrName
+		}
+		if group != ippOperationGroup {
+			continue
+		}
+		text := string(value)
+		if (tag == ippInteger || tag == ippEnum) && len(value) == 4 {
+			text = strconv.Itoa(int(int32(binary.BigEndian.Uint32(value))))
+		}
+		req.Attributes[name] = append(req.Attributes[name], text)
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	jobID := len(printJobs(s.requests))
+	status, statusMsg := s.status, s.statusMsg
+	state := 3
+	if req.Operation == ippGetJobAttributes {
+		jobID, _ = strconv.Atoi(req.Attr("job-id"))
+		state = s.states[min(s.polls, len(s.states)-1)]
+		s.polls++
+	}
+	reasons := s.reasons
+	s.mu.Unlock()
+
+	var resp bytes.Buffer
+	resp.Write([]byte{1, 1})
+	_ = binary.Write(&resp, binary.BigEndian, status)
+	_ = binary.Write(&resp, binary.BigEndian, requestID)
+	resp.WriteByte(ippOperationGroup)
+	writeIPPAttr(&resp, ippCharset, "attributes-charset", []byte("utf-8"))
+	writeIPPAttr(&resp, ippLanguage, "attributes-natural-language", []byte("en"))
+	if statusMsg != "" {
+		writeIPPAttr(&resp, ippText, "status-message", []byte(statusMsg))
+	}
+	if status == 0 {
+		resp.WriteByte(ippJobGroup)
+		writeIPPAttr(&resp, ippInteger, "job-id", ippInt(jobID))
+		writeIPPAttr(&resp, ippEnum, "job-state", ippInt(state))
+		for i, reason := range reasons {
+			attrName := "job-state-reasons"
+			if i > 0 {
+				attrName = ""
+			}
+			writeIPPAttr(&resp, ippKeyword, attrName, []byte(reason))
+		}
+	}
+	resp.WriteByte(ippEndTag)
+
+	w.Header().Set("Content-Type", "application/ipp")
+	_, _ = w.Write(resp.Bytes())
+}
+
+// printJobs filters the Print-Job requests
+func printJobs(requests []IPPRequest) []IPPRequest {
+	var jobs []IPPRequest
+	for _, r := range requests {
+		if r.Operation == ippPrintJob {
+			jobs = append(jobs, r)
+		}
+	}
+	return jobs
+}
+
+// writeIPPAttr encodes one attribute value
+func writeIPPAttr(b *bytes.Buffer, tag byte, name string, value []byte) {
+	b.WriteByte(tag)
+	_ = binary.Write(b, binary.BigEndian, uint16(len(name)))
+	b.WriteString(name)
+	_ = binary.Write(b, binary.BigEndian, uint16(len(value)))
+	b.Write(value)
+}
+
+// ippInt encodes an IPP integer value
+func ippInt(v int) []byte {
+	out := make([]byte, 4)
+	binary.BigEndian.PutUint32(out, uint32(int32(v)))
+	return out
+}
@@ -0,0 +1,144 @@
+package testutils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// LPDJob is a print job received by LPDServer
+type LPDJob struct {
+	Queue       string
+	ControlName string
+	Control     string
+	DataName    string
+	Data        []byte
+}
+
+// ControlLines returns the control file lines keyed by their command character
+func (j LPDJob) ControlLines() map[byte]string {
+	lines := make(map[byte]string)
+	for _, line := range strings.Split(strings.TrimRight(j.Control, "\n"), "\n") {
+		if line != "" {
+			lines[line[0]] = line[1:]
+		}
+	}
+	return lines
+}
+
+// LPDServer is a minimal in-process LPD (RFC 1179) server that records the jobs it receives
+type LPDServer struct {
+	ln     net.Listener
+	queues map[string]bool // accepted queues; empty accepts any
+
+	mu     sync.Mutex
+	jobs   []LPDJob
+	reject bool // answer the data file of complete jobs with a negative acknowledgement
+}
+
+// NewLPDServer starts an LPD server on a local port that accepts jobs for queues
+// (any queue when none is given). It is stopped when the test ends.
+func NewLPDServer(t *testing.T, queues ...string) *LPDServer {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &LPDServer{ln: ln, queues: make(map[string]bool)}
+	for _, q := range queues {
+		s.queues[q] = true
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// Addr returns the host:port the server listens on
+func (s *LPDServer) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// Jobs returns the complete jobs received so far
+func (s *LPDServer) Jobs() []LPDJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]LPDJob(nil), s.jobs...)
+}
+
+// RejectJobs makes the server record complete jobs but answer their last step with a
+// negative acknowledgement, like a daemon that fails after receiving the data
+func (s *LPDServer) RejectJobs(reject bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reject = reject
+}
+
+// serve handles one "receive a printer job" connection
+func (s *LPDServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	line, err := r.ReadString('\n')
+	if err != nil || len(line) < 2 || line[0] != 0x02 {
+		return
+	}
+	job := LPDJob{Queue: strings.TrimSuffix(line[1:], "\n")}
+	if len(s.queues) > 0 && !s.queues[job.Queue] {
+		_, _ = conn.Write([]byte{1})
+		return
+	}
+	_, _ = conn.Write([]byte{0})
+
+	haveControl, haveData := false, false
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil || len(line) < 2 {
+			return
+		}
+		sub := line[0]
+		var size int
+		var name string
+		if _, err := fmt.Sscanf(line[1:], "%d %s", &size, &name); err != nil || (sub != 0x02 && sub != 0x03) {
+			_, _ = conn.Write([]byte{1})
+			return
+		}
+		_, _ = conn.Write([]byte{0})
+
+		content := make([]byte, size+1)
+		if _, err := io.ReadFull(r, content); err != nil || content[size] != 0 {
+			return
+		}
+		if sub == 0x02 {
+			job.ControlName, job.Control, haveControl = name, string(content[:size]), true
+		} else {
+			job.DataName, job.Data, haveData = name, content[:size], true
+		}
+
+		// The job is recorded before the last acknowledgement so clients see it on return
+		if haveControl && haveData {
+			s.mu.Lock()
+			s.jobs = append(s.jobs, job)
+			reject := s.reject
+			s.mu.Unlock()
+			if reject {
+				_, _ = conn.Write([]byte{1})
+				return
+			}
+		}
+		_, _ = conn.Write([]byte{0})
+	}
+}
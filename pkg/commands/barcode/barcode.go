@@ -221,6 +221,7 @@ type Capability interface {
 	// Printing
 	PrintBarcode(symbology Symbology, data []byte) ([]byte, error)
 	PrintBarcodeWithCodeSet(symbology Symbology, codeSet Code128Set, data []byte) ([]byte, error)
+	PrintCode128(symbology Symbology, data []byte) ([]byte, error)
 }
 
 // ============================================================================
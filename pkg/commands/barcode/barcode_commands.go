@@ -297,3 +297,52 @@ func (c *Commands) PrintBarcodeWithCodeSet(symbology Symbology, codeSet Code128S
 
 	return c.buildFunctionB(symbology, prefixedData)
 }
+
+// PrintCode128 prints a CODE128 or GS1-128 barcode from plain data, choosing the code sets.
+//
+// Format:
+//
+//	GS k m n '{' codeSet data...
+//
+// Range:
+//
+//	m = 73 (CODE128) or 74 (GS1-128)
+//	n = 2–255 (total payload length)
+//
+// Default:
+//
+//	None
+//
+// Parameters:
+//
+//	symbology: Must be CODE128 (m=73) or GS1-128 (m=74)
+//	data: Plain barcode data, without code set selectors or '{' escapes.
+//	  For GS1-128, GS (0x1D) marks the FNC1 separators of the element string
+//
+// Notes:
+//   - The payload is built by EncodeCode128 or EncodeGS1128: set C for runs of
+//     digits, shift ({S) for single characters of the other set, FNC4 ({4) for
+//     bytes 128–255 and set changes only where they make the barcode narrower
+//   - Unlike CODE128 Auto (m=79), the encoding does not depend on the printer
+//
+// Errors:
+//
+//	Returns an error if symbology is not CODE128 or GS1-128.
+//	Returns ErrDataTooShort if data is empty.
+//	Returns ErrDataTooLong if the payload exceeds 255 bytes.
+func (c *Commands) PrintCode128(symbology Symbology, data []byte) ([]byte, error) {
+	var payload []byte
+	var err error
+	switch symbology {
+	case CODE128:
+		payload, err = EncodeCode128(data)
+	case GS1128:
+		payload, err = EncodeGS1128(data)
+	default:
+		return nil, fmt.Errorf("%w: symbology %d does not support code sets", ErrSymbology, symbology)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c.buildFunctionB(symbology, payload)
+}
@@ -0,0 +1,195 @@
+package barcode
+
+import (
+	"math"
+
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+)
+
+// ============================================================================
+// CODE128 Code Set Optimization
+// ============================================================================
+// CODE128 (m = 73) and GS1-128 (m = 74) data starts with '{' and a code set and
+// switches sets with '{' escapes:
+//
+//	{A {B {C  code set change        {S  shift (next character in set A/B)
+//	{1        FNC1                   {4  FNC4 (next character + 128)
+//	{2 {3     FNC2, FNC3             {{  the '{' character (set B)
+//
+// Set A encodes 0x00-0x5F, set B 0x20-0x7F and set C pairs of digits as one
+// byte 0-99. The encoder below picks the sets, shifts and FNC4 prefixes that
+// give the fewest symbols, and so the narrowest barcode.
+
+// code128Set indexes the code sets in the optimizer
+type code128Set int
+
+const (
+	code128A code128Set = iota
+	code128B
+	code128C
+	code128Sets
+)
+
+// code128Letters holds the code set selectors of the payload
+var code128Letters = [code128Sets]byte{'A', 'B', 'C'}
+
+// code128Preference orders the code sets on ties: B, then C, then A
+var code128Preference = [code128Sets]code128Set{code128B, code128C, code128A}
+
+// code128Cost is the size of an encoding: symbols in the barcode and bytes in the payload
+type code128Cost struct {
+	symbols int
+	bytes   int
+}
+
+func (c code128Cost) add(o code128Cost) code128Cost {
+	return code128Cost{c.symbols + o.symbols, c.bytes + o.bytes}
+}
+
+// less prefers fewer symbols and then a shorter payload
+func (c code128Cost) less(o code128Cost) bool {
+	return c.symbols < o.symbols || (c.symbols == o.symbols && c.bytes < o.bytes)
+}
+
+// code128Step encodes the next n data bytes in a code set
+type code128Step struct {
+	set     code128Set
+	n       int
+	payload []byte
+	cost    code128Cost
+}
+
+var (
+	// code128Switch is the cost of a code set change
+	code128Switch = code128Cost{symbols: 1, bytes: 2}
+	// code128None marks suffixes a code set cannot start with
+	code128None = code128Cost{symbols: math.MaxInt32, bytes: math.MaxInt32}
+)
+
+// EncodeCode128 returns the shortest ESC/POS CODE128 payload for plain data,
+// including the leading '{' and code set. Any byte can be encoded: runs of
+// digits use set C, control characters set A, and bytes 128-255 FNC4.
+//
+// Returns ErrDataTooShort if data is empty.
+func EncodeCode128(data []byte) ([]byte, error) {
+	return encodeCode128(data, false)
+}
+
+// EncodeGS1128 returns the shortest ESC/POS GS1-128 payload for a GS1 element
+// string. A GS (0x1D) separator after a variable length field is encoded as
+// FNC1 ({1); the leading FNC1 is added by the printer and must not be included.
+//
+// Returns ErrDataTooShort if data is empty.
+func EncodeGS1128(data []byte) ([]byte, error) {
+	return encodeCode128(data, true)
+}
+
+// encodeCode128 finds the cheapest encoding of each suffix of data in each code
+// set, from the end of data backwards, and then follows the cheapest steps
+func encodeCode128(data []byte, gs1 bool) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, ErrDataTooShort
+	}
+
+	n := len(data)
+	best := make([][code128Sets]code128Cost, n+1)
+	steps := make([][code128Sets]code128Step, n)
+	for i := n - 1; i >= 0; i-- {
+		// Cheapest encoding staying in each set
+		stay := [code128Sets]code128Cost{code128None, code128None, code128None}
+		for set := code128A; set < code128Sets; set++ {
+			for _, step := range code128Steps(data[i:], set, gs1) {
+				if best[i+step.n][set] == code128None {
+					continue
+				}
+				if total := step.cost.add(best[i+step.n][set]); total.less(stay[set]) {
+					stay[set], steps[i][set] = total, step
+				}
+			}
+		}
+
+		// Or changing to a cheaper set first
+		for set := code128A; set < code128Sets; set++ {
+			best[i][set] = stay[set]
+			for _, to := range code128Preference {
+				if to == set || stay[to] == code128None {
+					continue
+				}
+				if total := stay[to].add(code128Switch); total.less(best[i][set]) {
+					best[i][set], steps[i][set] = total, steps[i][to]
+				}
+			}
+		}
+	}
+
+	// Start in the cheapest set
+	start := code128Preference[0]
+	for _, set := range code128Preference[1:] {
+		if best[0][set].less(best[0][start]) {
+			start = set
+		}
+	}
+
+	payload := []byte{'{', code128Letters[start]}
+	set := start
+	for i := 0; i < n; {
+		step := steps[i][set]
+		if step.set != set {
+			set = step.set
+			payload = append(payload, '{', code128Letters[set])
+		}
+		payload = append(payload, step.payload...)
+		i += step.n
+	}
+	return payload, nil
+}
+
+// code128Steps returns the ways of encoding the start of data in a code set
+func code128Steps(data []byte, set code128Set, gs1 bool) []code128Step {
+	c := data[0]
+	if gs1 && c == common.GS {
+		return []code128Step{{set: set, n: 1, payload: []byte{'{', '1'}, cost: code128Cost{1, 2}}}
+	}
+
+	if set == code128C {
+		if len(data) >= 2 && isDigit(c) && isDigit(data[1]) {
+			value := (c-'0')*10 + data[1] - '0'
+			return []code128Step{{set: set, n: 2, payload: []byte{value}, cost: code128Cost{1, 1}}}
+		}
+		return nil
+	}
+
+	var steps []code128Step
+	other := code128A + code128B - set
+	if c < 0x80 {
+		if code128Encodable(c, set) {
+			steps = append(steps, code128Char(set, nil, c, 1))
+		} else if code128Encodable(c, other) {
+			steps = append(steps, code128Char(set, []byte{'{', 'S'}, c, 2))
+		}
+	} else if code128Encodable(c-0x80, set) {
+		steps = append(steps, code128Char(set, []byte{'{', '4'}, c-0x80, 2))
+	}
+	return steps
+}
+
+// code128Char returns the step that writes prefix and the character c in a code set
+func code128Char(set code128Set, prefix []byte, c byte, symbols int) code128Step {
+	payload := append(prefix, c)
+	if c == '{' {
+		payload = append(payload, '{')
+	}
+	return code128Step{set: set, n: 1, payload: payload, cost: code128Cost{symbols, len(payload)}}
+}
+
+// code128Encodable reports whether a set A or B encodes c
+func code128Encodable(c byte, set code128Set) bool {
+	if set == code128A {
+		return c < 0x60
+	}
+	return c >= 0x20 && c < 0x80
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,213 @@
+package barcode_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/commands/barcode"
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+)
+
+// code128Symbols counts the data symbols of an ESC/POS CODE128 payload: each byte
+// and each '{' escape is one symbol, after the leading code set selector
+func code128Symbols(payload []byte) int {
+	symbols := 0
+	for i := 2; i < len(payload); i++ {
+		if payload[i] == '{' {
+			i++
+		}
+		symbols++
+	}
+	return symbols
+}
+
+// naiveCode128 encodes data in a single code set: C for an even number of digits, B otherwise
+func naiveCode128(data []byte) []byte {
+	if len(data)%2 == 0 && barcode.ValidateNumericData(data) {
+		payload := []byte{'{', 'C'}
+		for i := 0; i < len(data); i += 2 {
+			payload = append(payload, (data[i]-'0')*10+data[i+1]-'0')
+		}
+		return payload
+	}
+	payload := []byte{'{', 'B'}
+	for _, c := range data {
+		if c == '{' {
+			payload = append(payload, '{')
+		}
+		payload = append(payload, c)
+	}
+	return payload
+}
+
+// ============================================================================
+// CODE128 Optimization Tests
+// ============================================================================
+
+func TestEncodeCode128(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []byte
+	}{
+		{"text stays in set B", "HELLO", []byte("{BHELLO")},
+		{"even digits use set C", "12345678", []byte{'{', 'C', 12, 34, 56, 78}},
+		{"odd digits start in set B", "123456789", []byte{'{', 'B', '1', '{', 'C', 23, 45, 67, 89}},
+		{"digit run after text", "ABC123456", []byte{'{', 'B', 'A', 'B', 'C', '{', 'C', 12, 34, 56}},
+		{"short digit run stays in set B", "AB12", []byte("{BAB12")},
+		{"control characters use set A", "\tTAB", []byte("{A\tTAB")},
+		{"single control character is shifted", "a\tb", []byte("{Ba{S\tb")},
+		{"control run then lowercase", "\x01\x02abc", []byte("{A\x01\x02{Babc")},
+		{"brace is escaped", "{x}", []byte("{B{{x}")},
+		{"extended byte uses FNC4", "caf\xe9", []byte("{Bcaf{4i")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := barcode.EncodeCode128([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("EncodeCode128(%q) error = %v", tt.data, err)
+			}
+			testutils.AssertBytes(t, got, tt.want, "EncodeCode128(%q)", tt.data)
+		})
+	}
+}
+
+func TestEncodeGS1128(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []byte
+	}{
+		{
+			name: "GTIN in set C",
+			data: "0109501101530003",
+			want: []byte{'{', 'C', 1, 9, 50, 11, 1, 53, 0, 3},
+		},
+		{
+			name: "FNC1 separator after variable length field",
+			data: "10ABC123\x1d3103000250",
+			want: []byte{'{', 'B', '1', '0', 'A', 'B', 'C', '1', '{', 'C', 23, '{', '1', 31, 3, 0, 2, 50},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := barcode.EncodeGS1128([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("EncodeGS1128(%q) error = %v", tt.data, err)
+			}
+			testutils.AssertBytes(t, got, tt.want, "EncodeGS1128(%q)", tt.data)
+		})
+	}
+
+	// Without GS1, GS is a set A control character
+	got, _ := barcode.EncodeCode128([]byte("1\x1d2"))
+	testutils.AssertBytes(t, got, []byte("{A1\x1d2"), "EncodeCode128 with GS")
+}
+
+func TestEncodeCode128_ShorterThanNaive(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		shorter bool // strictly fewer symbols than the single set encoding
+	}{
+		{"text", "Order No. 42", false},
+		{"even digits", "0123456789", false},
+		{"odd digits", "012345678", true},
+		{"invoice number", "INV-2024-000123456", true},
+		{"serial with digit run", "SN12345678X", true},
+		{"mixed short runs", "A1B2C3D4", false},
+		{"long digit run in text", "Batch 987654321012 ok", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := barcode.EncodeCode128([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("EncodeCode128(%q) error = %v", tt.data, err)
+			}
+			naive := naiveCode128([]byte(tt.data))
+
+			gotSymbols, naiveSymbols := code128Symbols(got), code128Symbols(naive)
+			if gotSymbols > naiveSymbols {
+				t.Errorf("EncodeCode128(%q) = %q has %d symbols, single set %q has %d",
+					tt.data, got, gotSymbols, naive, naiveSymbols)
+			}
+			if tt.shorter && gotSymbols >= naiveSymbols {
+				t.Errorf("EncodeCode128(%q) = %q is not shorter than %q", tt.data, got, naive)
+			}
+			if !tt.shorter && len(got) > len(naive) {
+				t.Errorf("EncodeCode128(%q) = %q is longer than %q", tt.data, got, naive)
+			}
+		})
+	}
+}
+
+func TestEncodeCode128_Errors(t *testing.T) {
+	if _, err := barcode.EncodeCode128(nil); !errors.Is(err, barcode.ErrDataTooShort) {
+		t.Errorf("EncodeCode128(nil) error = %v, want %v", err, barcode.ErrDataTooShort)
+	}
+	if _, err := barcode.EncodeGS1128([]byte{}); !errors.Is(err, barcode.ErrDataTooShort) {
+		t.Errorf("EncodeGS1128(empty) error = %v, want %v", err, barcode.ErrDataTooShort)
+	}
+}
+
+func TestCommands_PrintCode128(t *testing.T) {
+	cmd := barcode.NewCommands()
+
+	tests := []struct {
+		name      string
+		symbology barcode.Symbology
+		data      []byte
+		want      []byte
+		wantErr   error
+	}{
+		{
+			name:      "CODE128",
+			symbology: barcode.CODE128,
+			data:      []byte("ABC123456"),
+			want:      []byte{common.GS, 'k', 73, 10, '{', 'B', 'A', 'B', 'C', '{', 'C', 12, 34, 56},
+		},
+		{
+			name:      "GS1-128",
+			symbology: barcode.GS1128,
+			data:      []byte("0109501101530003"),
+			want:      []byte{common.GS, 'k', 74, 10, '{', 'C', 1, 9, 50, 11, 1, 53, 0, 3},
+		},
+		{
+			name:      "other symbology",
+			symbology: barcode.CODE93,
+			data:      []byte("ABC"),
+			wantErr:   barcode.ErrSymbology,
+		},
+		{
+			name:      "empty data",
+			symbology: barcode.CODE128,
+			data:      nil,
+			wantErr:   barcode.ErrDataTooShort,
+		},
+		{
+			name:      "payload too long",
+			symbology: barcode.CODE128,
+			data:      bytes.Repeat([]byte("a"), 254),
+			wantErr:   barcode.ErrDataTooLong,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmd.PrintCode128(tt.symbology, tt.data)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("PrintCode128() error = %v", err)
+			}
+			testutils.AssertBytes(t, got, tt.want, "PrintCode128")
+		})
+	}
+}
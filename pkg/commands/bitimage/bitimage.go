@@ -2,6 +2,9 @@ package bitimage
 
 import (
 	"errors"
+	"fmt"
+
+	"github.com/adcondev/pos-printer/pkg/commands/common"
 )
 
 // ============================================================================
@@ -61,6 +64,31 @@ type NVBitImageData struct {
 	Data   []byte // Bit image data in column format
 }
 
+// Response identifiers of the GS ( L query functions
+const (
+	// NVCapacityIdentifier identifies the NV graphics capacity response
+	NVCapacityIdentifier byte = 0x30
+	// NVRemainingIdentifier identifies the NV graphics remaining capacity response
+	NVRemainingIdentifier byte = 0x31
+	// DLRemainingIdentifier identifies the download graphics remaining capacity response
+	DLRemainingIdentifier byte = 0x32
+	// NVKeyCodeListIdentifier identifies the NV graphics key code list response
+	NVKeyCodeListIdentifier byte = 0x72
+	// DLKeyCodeListIdentifier identifies the download graphics key code list response
+	DLKeyCodeListIdentifier byte = 0x73
+)
+
+// KeyCode identifies a graphics definition stored in the printer
+type KeyCode struct {
+	KC1 byte
+	KC2 byte
+}
+
+// String returns the key code as its two ASCII characters
+func (k KeyCode) String() string {
+	return string([]byte{k.KC1, k.KC2})
+}
+
 // TODO: Check unused constants
 
 // Constants for image size limits
@@ -280,3 +308,44 @@ func ValidateRasterImageDimensions(width, height uint16, _ PrintMode) error {
 	}
 	return nil
 }
+
+// ============================================================================
+// Response Functions
+// ============================================================================
+
+// ParseCapacity decodes a capacity response (GS ( L <Function 48/51/52>) into a byte count
+func ParseCapacity(resp []byte, identifier byte) (int, error) {
+	payload, err := common.ParseBlock(resp, common.BlockHeader, identifier)
+	if err != nil {
+		return 0, err
+	}
+	return common.ParseDecimal(payload)
+}
+
+// ParseKeyCodeList decodes one block of a key code list response (GS ( L <Function 64/80>).
+// more reports that the printer holds further blocks, which are requested by sending ACK.
+func ParseKeyCodeList(resp []byte, identifier byte) (codes []KeyCode, more bool, err error) {
+	payload, err := common.ParseBlock(resp, common.BlockHeader, identifier)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(payload) == 0 {
+		return nil, false, fmt.Errorf("%w: missing identification status", common.ErrResponse)
+	}
+
+	status, data := payload[0], payload[1:]
+	switch status {
+	case common.BlockNoMoreData, common.BlockMoreData:
+	default:
+		return nil, false, fmt.Errorf("%w: identification status %#x", common.ErrResponse, status)
+	}
+	if len(data)%2 != 0 {
+		return nil, false, fmt.Errorf("%w: odd key code data length %d", common.ErrResponse, len(data))
+	}
+
+	codes = make([]KeyCode, 0, len(data)/2)
+	for i := 0; i < len(data); i += 2 {
+		codes = append(codes, KeyCode{KC1: data[i], KC2: data[i+1]})
+	}
+	return codes, status == common.BlockMoreData, nil
+}
@@ -424,3 +424,125 @@ func TestValidateRasterImageDimensions(t *testing.T) {
 		})
 	}
 }
+
+// ============================================================================
+// Response Parsing Tests
+// ============================================================================
+
+func TestParseCapacity(t *testing.T) {
+	tests := []struct {
+		name       string
+		resp       []byte
+		identifier byte
+		want       int
+		wantErr    error
+	}{
+		{
+			name:       "NV capacity",
+			resp:       []byte{0x37, 0x30, '1', '2', '0', '0', 0x00},
+			identifier: bitimage.NVCapacityIdentifier,
+			want:       1200,
+		},
+		{
+			name:       "download remaining",
+			resp:       []byte{0x37, 0x32, '1', '2', '0', 0x00},
+			identifier: bitimage.DLRemainingIdentifier,
+			want:       120,
+		},
+		{
+			name:       "unusable NV area",
+			resp:       []byte{0x37, 0x31, '0', 0x00},
+			identifier: bitimage.NVRemainingIdentifier,
+			want:       0,
+		},
+		{
+			name:       "identifier mismatch",
+			resp:       []byte{0x37, 0x31, '1', 0x00},
+			identifier: bitimage.NVCapacityIdentifier,
+			wantErr:    common.ErrResponse,
+		},
+		{
+			name:       "empty capacity",
+			resp:       []byte{0x37, 0x30, 0x00},
+			identifier: bitimage.NVCapacityIdentifier,
+			wantErr:    common.ErrResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bitimage.ParseCapacity(tt.resp, tt.identifier)
+			if !testutils.AssertErrorOccurred(t, err, tt.wantErr != nil, "ParseCapacity") {
+				return
+			}
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseCapacity() = %d; want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKeyCodeList(t *testing.T) {
+	tests := []struct {
+		name     string
+		resp     []byte
+		want     []bitimage.KeyCode
+		wantMore bool
+		wantErr  error
+	}{
+		{
+			name: "no key codes",
+			resp: []byte{0x37, 0x72, 0x40, 0x00},
+			want: []bitimage.KeyCode{},
+		},
+		{
+			name: "two key codes",
+			resp: []byte{0x37, 0x72, 0x40, 'N', 'V', 'L', 'G', 0x00},
+			want: []bitimage.KeyCode{{'N', 'V'}, {'L', 'G'}},
+		},
+		{
+			name:     "more data follows",
+			resp:     []byte{0x37, 0x72, 0x41, 'A', '1', 0x00},
+			want:     []bitimage.KeyCode{{'A', '1'}},
+			wantMore: true,
+		},
+		{
+			name:    "invalid status",
+			resp:    []byte{0x37, 0x72, 0x42, 0x00},
+			wantErr: common.ErrResponse,
+		},
+		{
+			name:    "odd data length",
+			resp:    []byte{0x37, 0x72, 0x40, 'A', 0x00},
+			wantErr: common.ErrResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, more, err := bitimage.ParseKeyCodeList(tt.resp, bitimage.NVKeyCodeListIdentifier)
+			if !testutils.AssertErrorOccurred(t, err, tt.wantErr != nil, "ParseKeyCodeList") {
+				return
+			}
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if more != tt.wantMore {
+				t.Errorf("ParseKeyCodeList() more = %v; want %v", more, tt.wantMore)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseKeyCodeList() = %v; want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("key code %d = %v; want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package buzzer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ============================================================================
+// Context
+// ============================================================================
+// This package implements ESC/POS commands for the printer buzzer.
+// ESC/POS is the command system used by thermal receipt printers. The common
+// ESC B form sounds the buzzer a number of times; the Epson ESC ( A form plays
+// one of the beeper patterns a number of cycles.
+
+// ============================================================================
+// Constant and Var Definitions
+// ============================================================================
+
+// Variant identifies the buzzer command understood by a printer model
+type Variant byte
+
+const (
+	// NoBuzzer means the printer has no buzzer, or its command is unknown
+	NoBuzzer Variant = iota
+	// VariantESCB selects ESC B n t (kitchen and compatible printers)
+	VariantESCB
+	// VariantEpson selects ESC ( A (Epson beeper tone control)
+	VariantEpson
+)
+
+// ESC B limits
+const (
+	// MinTimes is the fewest beeps ESC B accepts
+	MinTimes byte = 1
+	// MaxTimes is the most beeps ESC B accepts
+	MaxTimes byte = 9
+	// MinDuration is the shortest ESC B beep (1 × 50 ms)
+	MinDuration byte = 1
+	// MaxDuration is the longest ESC B beep (9 × 50 ms)
+	MaxDuration byte = 9
+)
+
+// DurationUnit is the time unit of the ESC B beep duration
+const DurationUnit = 50 * time.Millisecond
+
+// Pattern selects the ESC ( A beeper sound pattern
+type Pattern byte
+
+const (
+	// PatternOff stops the beeper
+	PatternOff Pattern = '0'
+	// PatternA is sound pattern A
+	PatternA Pattern = '1'
+	// PatternB is sound pattern B
+	PatternB Pattern = '2'
+	// PatternC is sound pattern C
+	PatternC Pattern = '3'
+	// PatternD is sound pattern D
+	PatternD Pattern = '4'
+	// PatternE is sound pattern E
+	PatternE Pattern = '5'
+	// PatternF is sound pattern F
+	PatternF Pattern = '6'
+	// PatternG is sound pattern G
+	PatternG Pattern = '7'
+)
+
+// ESC ( A limits
+const (
+	// MinCycles is the fewest cycles ESC ( A accepts
+	MinCycles byte = 1
+	// MaxCycles is the most cycles ESC ( A accepts
+	MaxCycles byte = 63
+	// MinToneTime is the shortest ESC ( A cycle (1 × 100 ms)
+	MinToneTime byte = 1
+)
+
+// ToneUnit is the time unit of the ESC ( A cycle time
+const ToneUnit = 100 * time.Millisecond
+
+// toneFunction is the ESC ( A function number for beeper tone control
+const toneFunction byte = 48
+
+// Defaults used when the caller does not specify the beep
+const (
+	// DefaultTimes is the default number of beeps
+	DefaultTimes = 1
+	// DefaultDuration is the default length of each beep
+	DefaultDuration = 200 * time.Millisecond
+)
+
+// ============================================================================
+// Error Definitions
+// ============================================================================
+
+var (
+	// ErrTimes indicates an invalid ESC B beep count
+	ErrTimes = errors.New("invalid beep count (try 1-9)")
+	// ErrDuration indicates an invalid ESC B beep duration
+	ErrDuration = errors.New("invalid beep duration (try 1-9, 50-450 ms)")
+	// ErrPattern indicates an invalid ESC ( A sound pattern
+	ErrPattern = errors.New("invalid beeper pattern (try 48-55)")
+	// ErrCycles indicates an invalid ESC ( A repeat count
+	ErrCycles = errors.New("invalid beeper cycles (try 1-63)")
+	// ErrToneTime indicates an invalid ESC ( A cycle time
+	ErrToneTime = errors.New("invalid beeper time (try 1-255, 100 ms-25.5 s)")
+)
+
+// ============================================================================
+// Interface Definitions
+// ============================================================================
+
+// Compile-time check that Commands implements Capability
+var _ Capability = (*Commands)(nil)
+
+// Capability defines the interface for buzzer commands
+type Capability interface {
+	Beep(times, duration byte) ([]byte, error)
+	BeeperTone(pattern Pattern, cycles, t byte) ([]byte, error)
+}
+
+// ============================================================================
+// Main Implementation
+// ============================================================================
+
+// Commands implements the Capability interface for buzzer control
+type Commands struct{}
+
+// NewCommands creates a new Commands instance
+func NewCommands() *Commands {
+	return &Commands{}
+}
+
+// ============================================================================
+// Validation Helper Functions
+// ============================================================================
+
+// ValidateTimes validates if the ESC B beep count is valid
+func ValidateTimes(n byte) error {
+	if n < MinTimes || n > MaxTimes {
+		return fmt.Errorf("%w: %d", ErrTimes, n)
+	}
+	return nil
+}
+
+// ValidateDuration validates if the ESC B beep duration is valid
+func ValidateDuration(t byte) error {
+	if t < MinDuration || t > MaxDuration {
+		return fmt.Errorf("%w: %d", ErrDuration, t)
+	}
+	return nil
+}
+
+// ValidatePattern validates if the ESC ( A sound pattern is valid
+func ValidatePattern(p Pattern) error {
+	if p < PatternOff || p > PatternG {
+		return fmt.Errorf("%w: %d", ErrPattern, p)
+	}
+	return nil
+}
+
+// ValidateCycles validates if the ESC ( A repeat count is valid
+func ValidateCycles(c byte) error {
+	if c < MinCycles || c > MaxCycles {
+		return fmt.Errorf("%w: %d", ErrCycles, c)
+	}
+	return nil
+}
+
+// ValidateToneTime validates if the ESC ( A cycle time is valid
+func ValidateToneTime(t byte) error {
+	if t < MinToneTime {
+		return fmt.Errorf("%w: %d", ErrToneTime, t)
+	}
+	return nil
+}
+
+// DurationUnits converts a duration into ESC B time units (50 ms), rounding up
+func DurationUnits(d time.Duration) (byte, error) {
+	if d <= 0 || d > time.Duration(MaxDuration)*DurationUnit {
+		return 0, fmt.Errorf("%w: %v", ErrDuration, d)
+	}
+	return byte((d + DurationUnit - 1) / DurationUnit), nil
+}
+
+// ToneUnits converts a duration into ESC ( A time units (100 ms), rounding up
+func ToneUnits(d time.Duration) (byte, error) {
+	if d <= 0 || d > 255*ToneUnit {
+		return 0, fmt.Errorf("%w: %v", ErrToneTime, d)
+	}
+	return byte((d + ToneUnit - 1) / ToneUnit), nil
+}
@@ -0,0 +1,105 @@
+package buzzer
+
+import (
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+)
+
+// Beep sounds the buzzer n times, each beep lasting t × 50 ms.
+//
+// Format:
+//
+//	ASCII:   ESC B n t
+//	Hex:     0x1B 0x42 n t
+//	Decimal: 27 66 n t
+//
+// Range:
+//
+//	n = 1–9
+//	t = 1–9
+//
+// Default:
+//
+//	None
+//
+// Parameters:
+//
+//	n: Number of beeps
+//	t: Duration of each beep, in units of 50 ms (t × 50 ms)
+//
+// Notes:
+//   - Supported by kitchen printers and many ESC/POS compatible models; Epson
+//     printers with a beeper use ESC ( A instead (see BeeperTone)
+//   - The command is buffered: the buzzer sounds when the command is processed,
+//     after the data received before it
+//   - Printers without a buzzer ignore the command or print its parameters;
+//     check the profile before sending it
+//
+// Errors:
+//
+//	Returns ErrTimes if n is outside 1–9.
+//	Returns ErrDuration if t is outside 1–9.
+func (c *Commands) Beep(times, duration byte) ([]byte, error) {
+	if err := ValidateTimes(times); err != nil {
+		return nil, err
+	}
+	if err := ValidateDuration(duration); err != nil {
+		return nil, err
+	}
+	return []byte{common.ESC, 'B', times, duration}, nil
+}
+
+// BeeperTone sounds the beeper with the selected pattern a number of cycles.
+//
+// Format:
+//
+//	ASCII:   ESC ( A pL pH fn n c t
+//	Hex:     0x1B 0x28 0x41 0x04 0x00 0x30 n c t
+//	Decimal: 27 40 65 4 0 48 n c t
+//
+// Range:
+//
+//	(pL + pH × 256) = 4
+//	fn = 48
+//	n  = 48–55
+//	c  = 1–63
+//	t  = 1–255
+//
+// Default:
+//
+//	None
+//
+// Parameters:
+//
+//	n: Sound pattern:
+//	   48 -> Stop the beeper
+//	   49–55 -> Patterns A to G
+//	c: Number of cycles
+//	t: Time of each cycle, in units of 100 ms (t × 100 ms)
+//
+// Notes:
+//   - Epson form of the buzzer command, available on models with a built-in
+//     or optional beeper; other printers use ESC B (see Beep)
+//   - Sending pattern 48 stops a beeper that is still sounding
+//   - The command is buffered and processed in order with the print data
+//
+// Errors:
+//
+//	Returns ErrPattern if n is outside 48–55.
+//	Returns ErrCycles if c is outside 1–63.
+//	Returns ErrToneTime if t is 0.
+func (c *Commands) BeeperTone(pattern Pattern, cycles, t byte) ([]byte, error) {
+	if err := ValidatePattern(pattern); err != nil {
+		return nil, err
+	}
+	if err := ValidateCycles(cycles); err != nil {
+		return nil, err
+	}
+	if err := ValidateToneTime(t); err != nil {
+		return nil, err
+	}
+	return []byte{
+		common.ESC, '(', 'A',
+		0x04, 0x00, // pL, pH
+		toneFunction, byte(pattern), cycles, t,
+	}, nil
+}
@@ -0,0 +1,136 @@
+package buzzer_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/commands/buzzer"
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+)
+
+// ============================================================================
+// Command Tests
+// ============================================================================
+
+func TestCommands_Beep(t *testing.T) {
+	cmd := buzzer.NewCommands()
+
+	tests := []struct {
+		name     string
+		times    byte
+		duration byte
+		want     []byte
+		wantErr  error
+	}{
+		{"single short beep", 1, 1, []byte{common.ESC, 'B', 1, 1}, nil},
+		{"maximum", 9, 9, []byte{common.ESC, 'B', 9, 9}, nil},
+		{"zero beeps", 0, 2, nil, buzzer.ErrTimes},
+		{"too many beeps", 10, 2, nil, buzzer.ErrTimes},
+		{"zero duration", 3, 0, nil, buzzer.ErrDuration},
+		{"too long", 3, 10, nil, buzzer.ErrDuration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmd.Beep(tt.times, tt.duration)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("Beep(%d, %d) error = %v", tt.times, tt.duration, err)
+			}
+			testutils.AssertBytes(t, got, tt.want, "Beep(%d, %d)", tt.times, tt.duration)
+		})
+	}
+}
+
+func TestCommands_BeeperTone(t *testing.T) {
+	cmd := buzzer.NewCommands()
+
+	tests := []struct {
+		name    string
+		pattern buzzer.Pattern
+		cycles  byte
+		t       byte
+		want    []byte
+		wantErr error
+	}{
+		{"pattern A", buzzer.PatternA, 3, 2, []byte{common.ESC, '(', 'A', 4, 0, 48, '1', 3, 2}, nil},
+		{"stop", buzzer.PatternOff, 1, 1, []byte{common.ESC, '(', 'A', 4, 0, 48, '0', 1, 1}, nil},
+		{"maximum", buzzer.PatternG, 63, 255, []byte{common.ESC, '(', 'A', 4, 0, 48, '7', 63, 255}, nil},
+		{"pattern below range", 47, 1, 1, nil, buzzer.ErrPattern},
+		{"pattern above range", 56, 1, 1, nil, buzzer.ErrPattern},
+		{"zero cycles", buzzer.PatternA, 0, 1, nil, buzzer.ErrCycles},
+		{"too many cycles", buzzer.PatternA, 64, 1, nil, buzzer.ErrCycles},
+		{"zero time", buzzer.PatternA, 1, 0, nil, buzzer.ErrToneTime},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmd.BeeperTone(tt.pattern, tt.cycles, tt.t)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("BeeperTone() error = %v", err)
+			}
+			testutils.AssertBytes(t, got, tt.want, "BeeperTone(%d, %d, %d)", tt.pattern, tt.cycles, tt.t)
+		})
+	}
+}
+
+// ============================================================================
+// Helper Function Tests
+// ============================================================================
+
+func TestDurationUnits(t *testing.T) {
+	tests := []struct {
+		d       time.Duration
+		want    byte
+		wantErr error
+	}{
+		{50 * time.Millisecond, 1, nil},
+		{120 * time.Millisecond, 3, nil},
+		{450 * time.Millisecond, 9, nil},
+		{0, 0, buzzer.ErrDuration},
+		{500 * time.Millisecond, 0, buzzer.ErrDuration},
+	}
+
+	for _, tt := range tests {
+		got, err := buzzer.DurationUnits(tt.d)
+		if tt.wantErr != nil {
+			testutils.AssertError(t, err, tt.wantErr)
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("DurationUnits(%v) = %d, %v; want %d", tt.d, got, err, tt.want)
+		}
+	}
+}
+
+func TestToneUnits(t *testing.T) {
+	tests := []struct {
+		d       time.Duration
+		want    byte
+		wantErr error
+	}{
+		{100 * time.Millisecond, 1, nil},
+		{250 * time.Millisecond, 3, nil},
+		{0, 0, buzzer.ErrToneTime},
+		{26 * time.Second, 0, buzzer.ErrToneTime},
+	}
+
+	for _, tt := range tests {
+		got, err := buzzer.ToneUnits(tt.d)
+		if tt.wantErr != nil {
+			testutils.AssertError(t, err, tt.wantErr)
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ToneUnits(%v) = %d, %v; want %d", tt.d, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+// Package buzzer implements ESC/POS commands for the printer buzzer (beeper).
+//
+// ESC/POS is the command system used by thermal receipt printers. Kitchen and
+// many compatible printers sound their buzzer with ESC B n t, while Epson
+// models with a beeper use the ESC ( A tone control function. Which form, if
+// any, a printer understands is a property of the model, so the printer
+// profile selects the Variant to use.
+package buzzer
@@ -13,4 +13,10 @@ const (
 	GS byte = 0x1D
 	// HT represents the byte de "Horizontal Tab" en ESC/POS.
 	HT byte = 0x09
+	// DLE represents the byte de "Data Link Escape" en ESC/POS.
+	DLE byte = 0x10
+	// EOT represents the byte de "End of Transmission" en ESC/POS.
+	EOT byte = 0x04
+	// DC4 represents the byte de "Device Control 4" en ESC/POS.
+	DC4 byte = 0x14
 )
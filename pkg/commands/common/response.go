@@ -0,0 +1,83 @@
+package common //nolint:revive
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ============================================================================
+// Constant and Var Definitions
+// ============================================================================
+
+// Block data responses ("Header to NUL") sent by GS ( k, GS ( L and similar functions
+const (
+	// BlockHeader is the first byte of a block data response
+	BlockHeader byte = 0x37
+	// IDBlockHeader is the first byte of a printer information block (GS I extended functions)
+	IDBlockHeader byte = 0x5F
+	// BlockMoreData is the identification status meaning more blocks follow (handshaking protocol)
+	BlockMoreData byte = 0x41
+	// BlockNoMoreData is the identification status meaning this is the last block
+	BlockNoMoreData byte = 0x40
+)
+
+// ESC/POS handshaking protocol control bytes sent by the host
+const (
+	// ACK requests the next block of a multi-block response
+	ACK byte = 0x06
+	// CAN cancels a multi-block response
+	CAN byte = 0x18
+)
+
+var (
+	// ErrResponse indicates a malformed or unexpected printer response
+	ErrResponse = errors.New("invalid printer response")
+)
+
+// ============================================================================
+// Response Helper Functions
+// ============================================================================
+
+// BlockLength returns the length of the first complete NUL-terminated block in buf,
+// including header and NUL, or 0 if more bytes are needed.
+func BlockLength(buf []byte) int {
+	for i, b := range buf {
+		if b == NUL {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// ParseBlock validates a "Header to NUL" response and returns the bytes between
+// the identifier and the NUL terminator.
+func ParseBlock(resp []byte, header, identifier byte) ([]byte, error) {
+	if len(resp) < 3 {
+		return nil, fmt.Errorf("%w: block too short (%d bytes)", ErrResponse, len(resp))
+	}
+	if resp[0] != header {
+		return nil, fmt.Errorf("%w: header %#x, want %#x", ErrResponse, resp[0], header)
+	}
+	if resp[1] != identifier {
+		return nil, fmt.Errorf("%w: identifier %#x, want %#x", ErrResponse, resp[1], identifier)
+	}
+	if resp[len(resp)-1] != NUL {
+		return nil, fmt.Errorf("%w: missing NUL terminator", ErrResponse)
+	}
+	return resp[2 : len(resp)-1], nil
+}
+
+// ParseDecimal converts the ASCII decimal digits used in block responses into an int
+func ParseDecimal(digits []byte) (int, error) {
+	if len(digits) == 0 {
+		return 0, fmt.Errorf("%w: empty number", ErrResponse)
+	}
+	value := 0
+	for _, d := range digits {
+		if d < '0' || d > '9' {
+			return 0, fmt.Errorf("%w: non-digit %#x in number", ErrResponse, d)
+		}
+		value = value*10 + int(d-'0')
+	}
+	return value, nil
+}
@@ -0,0 +1,78 @@
+package common_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+)
+
+func TestResponse_ParseBlock(t *testing.T) {
+	tests := []struct {
+		name       string
+		resp       []byte
+		identifier byte
+		want       []byte
+		wantErr    bool
+	}{
+		{"capacity", []byte{0x37, 0x30, '1', '2', '0', '0', 0x00}, 0x30, []byte("1200"), false},
+		{"empty payload", []byte{0x37, 0x72, 0x00}, 0x72, []byte{}, false},
+		{"wrong header", []byte{0x38, 0x30, '1', 0x00}, 0x30, nil, true},
+		{"wrong identifier", []byte{0x37, 0x31, '1', 0x00}, 0x30, nil, true},
+		{"missing NUL", []byte{0x37, 0x30, '1'}, 0x30, nil, true},
+		{"too short", []byte{0x37, 0x30}, 0x30, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := common.ParseBlock(tt.resp, common.BlockHeader, tt.identifier)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBlock error = %v; wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, common.ErrResponse) {
+					t.Errorf("ParseBlock error = %v; want %v", err, common.ErrResponse)
+				}
+				return
+			}
+			if string(got) != string(tt.want) {
+				t.Errorf("ParseBlock = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponse_BlockLength(t *testing.T) {
+	tests := []struct {
+		buf  []byte
+		want int
+	}{
+		{[]byte{0x37, 0x30, '1', 0x00, 0x37}, 4},
+		{[]byte{0x37, 0x30, '1'}, 0},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		if got := common.BlockLength(tt.buf); got != tt.want {
+			t.Errorf("BlockLength(%#v) = %d; want %d", tt.buf, got, tt.want)
+		}
+	}
+}
+
+func TestResponse_ParseDecimal(t *testing.T) {
+	tests := []struct {
+		digits  string
+		want    int
+		wantErr bool
+	}{
+		{"0", 0, false},
+		{"120", 120, false},
+		{"12345678", 12345678, false},
+		{"", 0, true},
+		{"12a", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := common.ParseDecimal([]byte(tt.digits))
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseDecimal(%q) = %d, %v; want %d, wantErr %v", tt.digits, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
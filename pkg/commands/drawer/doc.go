@@ -0,0 +1,7 @@
+// Package drawer implements ESC/POS commands for cash drawer control.
+//
+// ESC/POS is the command system used by thermal receipt printers. Cash drawers
+// are connected to the printer's drawer kick-out connector and are opened by
+// sending a pulse on pin 2 or pin 5 of that connector, either through the
+// buffered ESC p command or the real-time DLE DC4 command.
+package drawer
@@ -0,0 +1,160 @@
+package drawer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ============================================================================
+// Context
+// ============================================================================
+// This package implements ESC/POS commands for cash drawer control.
+// ESC/POS is the command system used by thermal receipt printers. A drawer is
+// opened by a pulse on the drawer kick-out connector; the pulse ON time must be
+// long enough for the solenoid to release the drawer.
+
+// ============================================================================
+// Constant and Var Definitions
+// ============================================================================
+
+// Pin selects the drawer kick-out connector pin that receives the pulse
+type Pin byte
+
+const (
+	// Pin2 sends the pulse to drawer kick-out connector pin 2 (first drawer)
+	Pin2 Pin = 0x00
+	// Pin5 sends the pulse to drawer kick-out connector pin 5 (second drawer)
+	Pin5 Pin = 0x01
+	// Pin2ASCII sends the pulse to pin 2 (ASCII mode)
+	Pin2ASCII Pin = '0'
+	// Pin5ASCII sends the pulse to pin 5 (ASCII mode)
+	Pin5ASCII Pin = '1'
+)
+
+// PulseUnit is the time unit of the ESC p ON and OFF times
+const PulseUnit = 2 * time.Millisecond
+
+// MaxPulseTime is the longest ON or OFF time accepted by ESC p (255 units)
+const MaxPulseTime = 255 * PulseUnit
+
+// RealtimePulseUnit is the time unit of the DLE DC4 pulse time
+const RealtimePulseUnit = 100 * time.Millisecond
+
+// RealtimePulse is the DLE DC4 pulse time in units of 100 ms (ON and OFF time are equal)
+type RealtimePulse byte
+
+const (
+	// MinRealtimePulse is the shortest real-time pulse (100 ms)
+	MinRealtimePulse RealtimePulse = 1
+	// MaxRealtimePulse is the longest real-time pulse (800 ms)
+	MaxRealtimePulse RealtimePulse = 8
+)
+
+// realtimePulseFunction is the DLE DC4 function number for "generate pulse in real-time"
+const realtimePulseFunction byte = 1
+
+// Default pulse timing used by most drawers (ESC p 0 25 250)
+const (
+	// DefaultOnTime is the default pulse ON time
+	DefaultOnTime = 50 * time.Millisecond
+	// DefaultOffTime is the default pulse OFF time
+	DefaultOffTime = 500 * time.Millisecond
+)
+
+// ============================================================================
+// Error Definitions
+// ============================================================================
+
+var (
+	// ErrPin indicates an invalid connector pin
+	ErrPin = errors.New("invalid drawer pin (try 0, 1, 48, or 49)")
+	// ErrPulseTime indicates an invalid ESC p pulse time
+	ErrPulseTime = errors.New("invalid pulse time (try 2-510 ms)")
+	// ErrRealtimePulse indicates an invalid DLE DC4 pulse time
+	ErrRealtimePulse = errors.New("invalid real-time pulse time (try 1-8)")
+)
+
+// ============================================================================
+// Interface Definitions
+// ============================================================================
+
+// Compile-time check that Commands implements Capability
+var _ Capability = (*Commands)(nil)
+
+// Capability defines the interface for cash drawer commands
+type Capability interface {
+	GeneratePulse(pin Pin, onTime, offTime byte) ([]byte, error)
+	GenerateRealtimePulse(pin Pin, t RealtimePulse) ([]byte, error)
+}
+
+// ============================================================================
+// Main Implementation
+// ============================================================================
+
+// Commands implements the Capability interface for cash drawer control
+type Commands struct{}
+
+// NewCommands creates a new Commands instance
+func NewCommands() *Commands {
+	return &Commands{}
+}
+
+// ============================================================================
+// Validation Helper Functions
+// ============================================================================
+
+// ValidatePin validates if the connector pin is valid
+func ValidatePin(pin Pin) error {
+	switch pin {
+	case Pin2, Pin5, Pin2ASCII, Pin5ASCII:
+		return nil
+	default:
+		return fmt.Errorf("%w: %d", ErrPin, pin)
+	}
+}
+
+// ValidatePulseTime validates if the ESC p ON time is valid (ON time cannot be zero)
+func ValidatePulseTime(onTime byte) error {
+	if onTime == 0 {
+		return fmt.Errorf("%w: ON time cannot be 0", ErrPulseTime)
+	}
+	return nil
+}
+
+// ValidateRealtimePulse validates if the DLE DC4 pulse time is valid
+func ValidateRealtimePulse(t RealtimePulse) error {
+	if t < MinRealtimePulse || t > MaxRealtimePulse {
+		return fmt.Errorf("%w: %d", ErrRealtimePulse, t)
+	}
+	return nil
+}
+
+// PulseUnits converts a duration into ESC p time units (2 ms), rounding up
+func PulseUnits(d time.Duration) (byte, error) {
+	if d < 0 || d > MaxPulseTime {
+		return 0, fmt.Errorf("%w: %v", ErrPulseTime, d)
+	}
+	return byte((d + PulseUnit - 1) / PulseUnit), nil
+}
+
+// RealtimePulseUnits converts a duration into DLE DC4 time units (100 ms), rounding up
+func RealtimePulseUnits(d time.Duration) (RealtimePulse, error) {
+	t := (d + RealtimePulseUnit - 1) / RealtimePulseUnit
+	if d <= 0 || t > time.Duration(MaxRealtimePulse) {
+		return 0, fmt.Errorf("%w: %v", ErrRealtimePulse, d)
+	}
+	return RealtimePulse(t), nil
+}
+
+// RealtimePin converts pin to the DLE DC4 form, which only accepts 0 and 1
+func RealtimePin(pin Pin) (Pin, error) {
+	switch pin {
+	case Pin2, Pin2ASCII:
+		return Pin2, nil
+	case Pin5, Pin5ASCII:
+		return Pin5, nil
+	default:
+		return 0, fmt.Errorf("%w: %d", ErrPin, pin)
+	}
+}
@@ -0,0 +1,98 @@
+package drawer
+
+import (
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+)
+
+// GeneratePulse outputs the pulse specified by onTime and offTime to the selected connector pin.
+//
+// Format:
+//
+//	ASCII:   ESC p m t1 t2
+//	Hex:     0x1B 0x70 m t1 t2
+//	Decimal: 27 112 m t1 t2
+//
+// Range:
+//
+//	m  = 0, 1, 48, 49
+//	t1 = 1–255
+//	t2 = 0–255
+//
+// Default:
+//
+//	None
+//
+// Parameters:
+//
+//	m: Selects the drawer kick-out connector pin:
+//	   0, 48 -> Pin 2
+//	   1, 49 -> Pin 5
+//	t1: Pulse ON time, in units of 2 ms (t1 × 2 ms)
+//	t2: Pulse OFF time, in units of 2 ms (t2 × 2 ms)
+//
+// Notes:
+//   - If t2 < t1, the OFF time is t1 × 2 ms
+//   - The command is buffered: the pulse is generated when the command is processed,
+//     after the data received before it
+//   - While a pulse is being output, other drawer kick-out commands are ignored
+//   - Use DLE DC4 to open the drawer even when the printer is offline or busy
+//
+// Errors:
+//
+//	Returns ErrPin if m is not a valid pin.
+//	Returns ErrPulseTime if t1 is 0.
+func (c *Commands) GeneratePulse(pin Pin, onTime, offTime byte) ([]byte, error) {
+	if err := ValidatePin(pin); err != nil {
+		return nil, err
+	}
+	if err := ValidatePulseTime(onTime); err != nil {
+		return nil, err
+	}
+	return []byte{common.ESC, 'p', byte(pin), onTime, offTime}, nil
+}
+
+// GenerateRealtimePulse outputs a pulse to the selected connector pin in real time.
+//
+// Format:
+//
+//	ASCII:   DLE DC4 fn m t
+//	Hex:     0x10 0x14 0x01 m t
+//	Decimal: 16 20 1 m t
+//
+// Range:
+//
+//	fn = 1
+//	m  = 0, 1
+//	t  = 1–8
+//
+// Default:
+//
+//	None
+//
+// Parameters:
+//
+//	m: Selects the drawer kick-out connector pin:
+//	   0 -> Pin 2
+//	   1 -> Pin 5
+//	t: Pulse ON and OFF time, in units of 100 ms (t × 100 ms)
+//
+// Notes:
+//   - Executed immediately, even while the printer is offline or the receive buffer is full
+//   - Ignored while the printer is in an error state or another pulse is being output
+//   - Do not insert this command inside the parameters of another command
+//   - The ASCII pin values accepted by ESC p are converted to 0 and 1
+//
+// Errors:
+//
+//	Returns ErrPin if m is not a valid pin.
+//	Returns ErrRealtimePulse if t is not between 1 and 8.
+func (c *Commands) GenerateRealtimePulse(pin Pin, t RealtimePulse) ([]byte, error) {
+	m, err := RealtimePin(pin)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateRealtimePulse(t); err != nil {
+		return nil, err
+	}
+	return []byte{common.DLE, common.DC4, realtimePulseFunction, byte(m), byte(t)}, nil
+}
@@ -0,0 +1,150 @@
+package drawer_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+	"github.com/adcondev/pos-printer/pkg/commands/drawer"
+)
+
+// ============================================================================
+// Pulse Command Tests
+// ============================================================================
+
+func TestCommands_GeneratePulse(t *testing.T) {
+	cmd := drawer.NewCommands()
+
+	tests := []struct {
+		name    string
+		pin     drawer.Pin
+		on      byte
+		off     byte
+		want    []byte
+		wantErr error
+	}{
+		{"pin 2", drawer.Pin2, 25, 250, []byte{common.ESC, 'p', 0x00, 25, 250}, nil},
+		{"pin 5", drawer.Pin5, 50, 50, []byte{common.ESC, 'p', 0x01, 50, 50}, nil},
+		{"pin 2 ASCII", drawer.Pin2ASCII, 1, 0, []byte{common.ESC, 'p', '0', 1, 0}, nil},
+		{"pin 5 ASCII", drawer.Pin5ASCII, 255, 255, []byte{common.ESC, 'p', '1', 255, 255}, nil},
+		{"invalid pin", 2, 25, 250, nil, drawer.ErrPin},
+		{"zero on time", drawer.Pin2, 0, 250, nil, drawer.ErrPulseTime},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmd.GeneratePulse(tt.pin, tt.on, tt.off)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("GeneratePulse() error = %v", err)
+			}
+			testutils.AssertBytes(t, got, tt.want, "GeneratePulse(%d, %d, %d)", tt.pin, tt.on, tt.off)
+		})
+	}
+}
+
+func TestCommands_GenerateRealtimePulse(t *testing.T) {
+	cmd := drawer.NewCommands()
+
+	tests := []struct {
+		name    string
+		pin     drawer.Pin
+		t       drawer.RealtimePulse
+		want    []byte
+		wantErr error
+	}{
+		{"pin 2 minimum", drawer.Pin2, drawer.MinRealtimePulse, []byte{common.DLE, common.DC4, 1, 0, 1}, nil},
+		{"pin 5 maximum", drawer.Pin5, drawer.MaxRealtimePulse, []byte{common.DLE, common.DC4, 1, 1, 8}, nil},
+		{"ASCII pin converted", drawer.Pin5ASCII, 2, []byte{common.DLE, common.DC4, 1, 1, 2}, nil},
+		{"invalid pin", 3, 1, nil, drawer.ErrPin},
+		{"zero time", drawer.Pin2, 0, nil, drawer.ErrRealtimePulse},
+		{"time too long", drawer.Pin2, 9, nil, drawer.ErrRealtimePulse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmd.GenerateRealtimePulse(tt.pin, tt.t)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("GenerateRealtimePulse() error = %v", err)
+			}
+			testutils.AssertBytes(t, got, tt.want, "GenerateRealtimePulse(%d, %d)", tt.pin, tt.t)
+		})
+	}
+}
+
+// ============================================================================
+// Timing Helper Tests
+// ============================================================================
+
+func TestPulseUnits(t *testing.T) {
+	tests := []struct {
+		name    string
+		d       time.Duration
+		want    byte
+		wantErr error
+	}{
+		{"zero", 0, 0, nil},
+		{"exact units", 50 * time.Millisecond, 25, nil},
+		{"rounds up", 51 * time.Millisecond, 26, nil},
+		{"maximum", drawer.MaxPulseTime, 255, nil},
+		{"too long", drawer.MaxPulseTime + time.Millisecond, 0, drawer.ErrPulseTime},
+		{"negative", -time.Millisecond, 0, drawer.ErrPulseTime},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := drawer.PulseUnits(tt.d)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("PulseUnits(%v) error = %v", tt.d, err)
+			}
+			if got != tt.want {
+				t.Errorf("PulseUnits(%v) = %d, want %d", tt.d, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRealtimePulseUnits(t *testing.T) {
+	tests := []struct {
+		name    string
+		d       time.Duration
+		want    drawer.RealtimePulse
+		wantErr error
+	}{
+		{"rounds up to one unit", 50 * time.Millisecond, 1, nil},
+		{"exact units", 300 * time.Millisecond, 3, nil},
+		{"rounds up", 301 * time.Millisecond, 4, nil},
+		{"maximum", 800 * time.Millisecond, drawer.MaxRealtimePulse, nil},
+		{"too long", 801 * time.Millisecond, 0, drawer.ErrRealtimePulse},
+		{"zero", 0, 0, drawer.ErrRealtimePulse},
+		{"negative", -time.Millisecond, 0, drawer.ErrRealtimePulse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := drawer.RealtimePulseUnits(tt.d)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("RealtimePulseUnits(%v) error = %v", tt.d, err)
+			}
+			if got != tt.want {
+				t.Errorf("RealtimePulseUnits(%v) = %d, want %d", tt.d, got, tt.want)
+			}
+		})
+	}
+}
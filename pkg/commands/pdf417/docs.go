@@ -0,0 +1,4 @@
+// Package pdf417 implements ESC/POS commands for PDF417 generation and printing.
+// ESC/POS is the command system used by thermal receipt printers to control
+// PDF417 symbol layout, encoding, storage, and printing operations.
+package pdf417
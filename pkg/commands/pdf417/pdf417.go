@@ -0,0 +1,308 @@
+package pdf417
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+)
+
+// ============================================================================
+// Context
+// ============================================================================
+// This package implements ESC/POS commands for PDF417 generation and printing.
+// ESC/POS is the command system used by thermal receipt printers to control
+// PDF417 symbol layout, encoding, storage, and printing operations.
+
+// ============================================================================
+// Constant and Var Definitions
+// ============================================================================
+
+// Columns is the number of data columns of the PDF417 symbol
+type Columns byte
+
+const (
+	// AutoColumns lets the printer calculate the number of columns
+	AutoColumns Columns = 0
+	// MaxColumns represents the maximum number of data columns
+	MaxColumns Columns = 30
+)
+
+// Rows is the number of rows of the PDF417 symbol
+type Rows byte
+
+const (
+	// AutoRows lets the printer calculate the number of rows
+	AutoRows Rows = 0
+	// MinRows represents the minimum number of rows when rows are set explicitly
+	MinRows Rows = 3
+	// MaxRows represents the maximum number of rows
+	MaxRows Rows = 90
+)
+
+// ModuleWidth is the width of one PDF417 module (dots)
+type ModuleWidth byte
+
+const (
+	// MinModuleWidth represents the minimum module width (2 dots)
+	MinModuleWidth ModuleWidth = 2
+	// DefaultModuleWidth represents the default module width (3 dots)
+	DefaultModuleWidth ModuleWidth = 3
+	// MaxModuleWidth represents the maximum module width (8 dots)
+	MaxModuleWidth ModuleWidth = 8
+)
+
+// RowHeight is the height of one PDF417 row, as a multiple of the module width
+type RowHeight byte
+
+const (
+	// MinRowHeight represents the minimum row height (2 × module width)
+	MinRowHeight RowHeight = 2
+	// DefaultRowHeight represents the default row height (3 × module width)
+	DefaultRowHeight RowHeight = 3
+	// MaxRowHeight represents the maximum row height (8 × module width)
+	MaxRowHeight RowHeight = 8
+)
+
+// CorrectionMode selects how the error correction level is specified
+type CorrectionMode byte
+
+const (
+	// ByLevel sets the error correction level directly (Level0-Level8)
+	ByLevel CorrectionMode = 48
+	// ByRatio sets the error correction level as a ratio of the data codewords
+	ByRatio CorrectionMode = 49
+)
+
+// ErrorCorrection is the error correction level when CorrectionMode is ByLevel
+type ErrorCorrection byte
+
+const (
+	// Level0 uses 2 error correction codewords
+	Level0 ErrorCorrection = 48 + iota
+	// Level1 uses 4 error correction codewords
+	Level1
+	// Level2 uses 8 error correction codewords
+	Level2
+	// Level3 uses 16 error correction codewords
+	Level3
+	// Level4 uses 32 error correction codewords
+	Level4
+	// Level5 uses 64 error correction codewords
+	Level5
+	// Level6 uses 128 error correction codewords
+	Level6
+	// Level7 uses 256 error correction codewords
+	Level7
+	// Level8 uses 512 error correction codewords
+	Level8
+)
+
+// Ratio limits when CorrectionMode is ByRatio (n × 10% of the data codewords)
+const (
+	// MinRatio represents the minimum ratio (10%)
+	MinRatio byte = 1
+	// DefaultRatio represents the default ratio (10%)
+	DefaultRatio byte = 1
+	// MaxRatio represents the maximum ratio (400%)
+	MaxRatio byte = 40
+)
+
+// Option selects the PDF417 symbol type
+type Option byte
+
+const (
+	// Standard prints the standard PDF417 symbol
+	Standard Option = 0
+	// Truncated omits the right row indicators and stop pattern (Compact PDF417)
+	Truncated Option = 1
+)
+
+// Size information response (GS ( k <Function 082>)
+const (
+	// SizeInfoIdentifier is the identifier byte of the size information response
+	SizeInfoIdentifier byte = 0x36
+	// sizeInfoSeparator separates the fields of the size information response
+	sizeInfoSeparator byte = 0x1F
+	// sizeInfoPrintable is the "other information" value when the symbol can be printed
+	sizeInfoPrintable byte = 0x30
+)
+
+// SymbolSize is the decoded size information of the symbol in the storage area
+type SymbolSize struct {
+	Width     int  // Horizontal size in dots (quiet zone excluded)
+	Height    int  // Vertical size in dots (quiet zone excluded)
+	Printable bool // Whether the symbol can be printed with current settings
+}
+
+// Data limits
+const (
+	MinDataLength = 1     // Minimum data length
+	MaxDataLength = 65532 // Maximum data length (65535 - 3 header bytes)
+)
+
+// ============================================================================
+// Error Definitions
+// ============================================================================
+
+var (
+	// ErrColumns indicates an invalid number of columns
+	ErrColumns = errors.New("invalid number of columns (try 0-30)")
+	// ErrRows indicates an invalid number of rows
+	ErrRows = errors.New("invalid number of rows (try 0 or 3-90)")
+	// ErrModuleWidth indicates an invalid module width
+	ErrModuleWidth = errors.New("invalid module width (try 2-8)")
+	// ErrRowHeight indicates an invalid row height
+	ErrRowHeight = errors.New("invalid row height (try 2-8)")
+	// ErrCorrectionMode indicates an invalid error correction mode
+	ErrCorrectionMode = errors.New("invalid error correction mode (try 48-49)")
+	// ErrErrorCorrection indicates an invalid error correction level or ratio
+	ErrErrorCorrection = errors.New("invalid error correction (try level 48-56 or ratio 1-40)")
+	// ErrOption indicates an invalid symbol option
+	ErrOption = errors.New("invalid option (try 0-1)")
+	// ErrDataTooShort indicates data is too short
+	ErrDataTooShort = errors.New("data too short (minimum 1 byte)")
+	// ErrDataTooLong indicates data is too long
+	ErrDataTooLong = errors.New("data too long (maximum 65532 bytes)")
+)
+
+// ============================================================================
+// Interface Definitions
+// ============================================================================
+
+// Interface compliance check
+var _ Capability = (*Commands)(nil)
+
+// Capability defines the PDF417 printing interface
+type Capability interface {
+	SetPDF417Columns(n Columns) ([]byte, error)
+	SetPDF417Rows(n Rows) ([]byte, error)
+	SetPDF417ModuleWidth(n ModuleWidth) ([]byte, error)
+	SetPDF417RowHeight(n RowHeight) ([]byte, error)
+	SetPDF417ErrorCorrectionLevel(m CorrectionMode, n byte) ([]byte, error)
+	SelectPDF417Options(n Option) ([]byte, error)
+	StorePDF417Data(data []byte) ([]byte, error)
+	PrintPDF417() []byte
+	GetPDF417Size() []byte
+}
+
+// ============================================================================
+// Main Implementation
+// ============================================================================
+
+// Commands implements PDF417 ESC/POS commands
+type Commands struct {
+	// No sub-modules needed for PDF417
+}
+
+// NewCommands creates a new PDF417 commands instance
+func NewCommands() *Commands {
+	return &Commands{}
+}
+
+// ============================================================================
+// Validation Functions
+// ============================================================================
+
+// ValidateColumns validates if the number of columns is valid
+func ValidateColumns(n Columns) error {
+	if n > MaxColumns {
+		return fmt.Errorf("%w: %d", ErrColumns, n)
+	}
+	return nil
+}
+
+// ValidateRows validates if the number of rows is valid
+func ValidateRows(n Rows) error {
+	if n != AutoRows && (n < MinRows || n > MaxRows) {
+		return fmt.Errorf("%w: %d", ErrRows, n)
+	}
+	return nil
+}
+
+// ValidateModuleWidth validates if the module width is valid
+func ValidateModuleWidth(n ModuleWidth) error {
+	if n < MinModuleWidth || n > MaxModuleWidth {
+		return fmt.Errorf("%w: %d", ErrModuleWidth, n)
+	}
+	return nil
+}
+
+// ValidateRowHeight validates if the row height is valid
+func ValidateRowHeight(n RowHeight) error {
+	if n < MinRowHeight || n > MaxRowHeight {
+		return fmt.Errorf("%w: %d", ErrRowHeight, n)
+	}
+	return nil
+}
+
+// ValidateErrorCorrection validates the error correction level (ByLevel) or ratio (ByRatio)
+func ValidateErrorCorrection(m CorrectionMode, n byte) error {
+	switch m {
+	case ByLevel:
+		if n < byte(Level0) || n > byte(Level8) {
+			return fmt.Errorf("%w: level %d", ErrErrorCorrection, n)
+		}
+	case ByRatio:
+		if n < MinRatio || n > MaxRatio {
+			return fmt.Errorf("%w: ratio %d", ErrErrorCorrection, n)
+		}
+	default:
+		return fmt.Errorf("%w: %d", ErrCorrectionMode, m)
+	}
+	return nil
+}
+
+// ValidateOption validates if the symbol option is valid
+func ValidateOption(n Option) error {
+	if n != Standard && n != Truncated {
+		return fmt.Errorf("%w: %d", ErrOption, n)
+	}
+	return nil
+}
+
+// ValidateDataLength validates if the data length is within bounds
+func ValidateDataLength(data []byte) error {
+	if len(data) < MinDataLength {
+		return fmt.Errorf("%w: %d bytes", ErrDataTooShort, len(data))
+	}
+	if len(data) > MaxDataLength {
+		return fmt.Errorf("%w: %d bytes", ErrDataTooLong, len(data))
+	}
+	return nil
+}
+
+// ============================================================================
+// Response Functions
+// ============================================================================
+
+// ParseSymbolSize decodes the printer response to GetPDF417Size, in the layout documented
+// for GS ( k <Function 082>; unlike the QR Code response it has no fixed '1' field
+func ParseSymbolSize(resp []byte) (SymbolSize, error) {
+	payload, err := common.ParseBlock(resp, common.BlockHeader, SizeInfoIdentifier)
+	if err != nil {
+		return SymbolSize{}, err
+	}
+
+	// horizontal 0x1F vertical 0x1F other
+	fields := bytes.Split(payload, []byte{sizeInfoSeparator})
+	if len(fields) != 3 || len(fields[2]) != 1 {
+		return SymbolSize{}, fmt.Errorf("%w: malformed size information %q", common.ErrResponse, payload)
+	}
+
+	width, err := common.ParseDecimal(fields[0])
+	if err != nil {
+		return SymbolSize{}, err
+	}
+	height, err := common.ParseDecimal(fields[1])
+	if err != nil {
+		return SymbolSize{}, err
+	}
+
+	return SymbolSize{
+		Width:     width,
+		Height:    height,
+		Printable: fields[2][0] == sizeInfoPrintable,
+	}, nil
+}
@@ -0,0 +1,497 @@
+package pdf417
+
+import (
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+)
+
+// SetPDF417Columns sets the number of data columns of the PDF417 symbol.
+//
+// Format:
+//
+//	ASCII:   GS ( k pL pH cn fn n
+//	Hex:     0x1D 0x28 0x6B 0x03 0x00 0x30 0x41 n
+//	Decimal: 29 40 107 3 0 48 65 n
+//
+// Range:
+//
+//	(pL + pH × 256) = 3
+//	cn = 48
+//	fn = 65
+//	n = 0–30
+//
+// Default:
+//
+//	n = 0
+//
+// Parameters:
+//
+//	n: Number of columns in the data area:
+//	   0    -> Automatic processing
+//	   1–30 -> Fixed number of columns
+//
+// Notes:
+//   - Settings of this function affect the processing of GS ( k <Function 081> and GS ( k <Function 082>
+//   - Settings remain effective until ESC @ is executed, the printer is reset, or the power is turned off
+//   - The row indicators, start pattern and stop pattern are not counted as columns
+//   - With automatic processing, the number of columns is calculated from the print area width
+//   - A symbol wider than the print area cannot be printed
+//
+// Errors:
+//
+//	Returns ErrColumns if n is outside the valid range (0–30)
+func (c *Commands) SetPDF417Columns(n Columns) ([]byte, error) {
+	// Validate parameter
+	if err := ValidateColumns(n); err != nil {
+		return nil, err
+	}
+
+	// Build command
+	return []byte{
+		common.GS, '(', 'k',
+		0x03, 0x00, // pL, pH
+		0x30,    // cn = 48
+		65,      // fn = 65
+		byte(n), // columns
+	}, nil
+}
+
+// SetPDF417Rows sets the number of rows of the PDF417 symbol.
+//
+// Format:
+//
+//	ASCII:   GS ( k pL pH cn fn n
+//	Hex:     0x1D 0x28 0x6B 0x03 0x00 0x30 0x42 n
+//	Decimal: 29 40 107 3 0 48 66 n
+//
+// Range:
+//
+//	(pL + pH × 256) = 3
+//	cn = 48
+//	fn = 66
+//	n = 0, 3–90
+//
+// Default:
+//
+//	n = 0
+//
+// Parameters:
+//
+//	n: Number of rows:
+//	   0    -> Automatic processing
+//	   3–90 -> Fixed number of rows
+//
+// Notes:
+//   - Settings of this function affect the processing of GS ( k <Function 081> and GS ( k <Function 082>
+//   - Settings remain effective until ESC @ is executed, the printer is reset, or the power is turned off
+//   - With automatic processing, the printer uses as few rows as the columns and data allow
+//   - When both columns and rows are fixed, the data must fit in columns × rows codewords
+//
+// Errors:
+//
+//	Returns ErrRows if n is not 0 and outside the range 3–90
+func (c *Commands) SetPDF417Rows(n Rows) ([]byte, error) {
+	// Validate parameter
+	if err := ValidateRows(n); err != nil {
+		return nil, err
+	}
+
+	// Build command
+	return []byte{
+		common.GS, '(', 'k',
+		0x03, 0x00, // pL, pH
+		0x30,    // cn = 48
+		66,      // fn = 66
+		byte(n), // rows
+	}, nil
+}
+
+// SetPDF417ModuleWidth sets the width of one module of the PDF417 symbol.
+//
+// Format:
+//
+//	ASCII:   GS ( k pL pH cn fn n
+//	Hex:     0x1D 0x28 0x6B 0x03 0x00 0x30 0x43 n
+//	Decimal: 29 40 107 3 0 48 67 n
+//
+// Range:
+//
+//	(pL + pH × 256) = 3
+//	cn = 48
+//	fn = 67
+//	n = 2–8
+//
+// Default:
+//
+//	n = 3
+//
+// Parameters:
+//
+//	n: Module width in dots (2–8)
+//
+// Notes:
+//   - Settings of this function affect the processing of GS ( k <Function 081> and GS ( k <Function 082>
+//   - Settings remain effective until ESC @ is executed, the printer is reset, or the power is turned off
+//   - The row height (GS ( k <Function 068>) is a multiple of this width
+//   - Wider modules are easier to scan but reduce the columns that fit in the print area
+//
+// Errors:
+//
+//	Returns ErrModuleWidth if n is outside the valid range (2–8)
+func (c *Commands) SetPDF417ModuleWidth(n ModuleWidth) ([]byte, error) {
+	// Validate parameter
+	if err := ValidateModuleWidth(n); err != nil {
+		return nil, err
+	}
+
+	// Build command
+	return []byte{
+		common.GS, '(', 'k',
+		0x03, 0x00, // pL, pH
+		0x30,    // cn = 48
+		67,      // fn = 67
+		byte(n), // module width
+	}, nil
+}
+
+// SetPDF417RowHeight sets the height of one row of the PDF417 symbol.
+//
+// Format:
+//
+//	ASCII:   GS ( k pL pH cn fn n
+//	Hex:     0x1D 0x28 0x6B 0x03 0x00 0x30 0x44 n
+//	Decimal: 29 40 107 3 0 48 68 n
+//
+// Range:
+//
+//	(pL + pH × 256) = 3
+//	cn = 48
+//	fn = 68
+//	n = 2–8
+//
+// Default:
+//
+//	n = 3
+//
+// Parameters:
+//
+//	n: Row height as a multiple of the module width (2–8)
+//
+// Notes:
+//   - Settings of this function affect the processing of GS ( k <Function 081> and GS ( k <Function 082>
+//   - Settings remain effective until ESC @ is executed, the printer is reset, or the power is turned off
+//   - The row height in dots is module width × n
+//   - Taller rows are more tolerant of skewed scanning at the cost of symbol height
+//
+// Errors:
+//
+//	Returns ErrRowHeight if n is outside the valid range (2–8)
+func (c *Commands) SetPDF417RowHeight(n RowHeight) ([]byte, error) {
+	// Validate parameter
+	if err := ValidateRowHeight(n); err != nil {
+		return nil, err
+	}
+
+	// Build command
+	return []byte{
+		common.GS, '(', 'k',
+		0x03, 0x00, // pL, pH
+		0x30,    // cn = 48
+		68,      // fn = 68
+		byte(n), // row height
+	}, nil
+}
+
+// SetPDF417ErrorCorrectionLevel sets the error correction level of the PDF417 symbol.
+//
+// Format:
+//
+//	ASCII:   GS ( k pL pH cn fn m n
+//	Hex:     0x1D 0x28 0x6B 0x04 0x00 0x30 0x45 m n
+//	Decimal: 29 40 107 4 0 48 69 m n
+//
+// Range:
+//
+//	(pL + pH × 256) = 4
+//	cn = 48
+//	fn = 69
+//	m = 48, 49
+//	n = 48–56 (when m = 48)
+//	n = 1–40 (when m = 49)
+//
+// Default:
+//
+//	m = 49, n = 1 (ratio 10%)
+//
+// Parameters:
+//
+//	m: How n is interpreted:
+//	   48 -> Error correction level
+//	   49 -> Error correction ratio
+//	n: Error correction level or ratio:
+//	   48–56 -> Level 0–8 (2 to 512 error correction codewords) when m = 48
+//	   1–40  -> n × 10% of the data codewords when m = 49
+//
+// Notes:
+//   - Settings of this function affect the processing of GS ( k <Function 081> and GS ( k <Function 082>
+//   - Settings remain effective until ESC @ is executed, the printer is reset, or the power is turned off
+//   - With a ratio, the level is the smallest one whose codewords reach n × 10% of the data codewords
+//   - Higher levels tolerate more damage but enlarge the symbol for the same data
+//
+// Errors:
+//
+//	Returns ErrCorrectionMode if m is not 48 or 49
+//	Returns ErrErrorCorrection if n is outside the range allowed for m
+func (c *Commands) SetPDF417ErrorCorrectionLevel(m CorrectionMode, n byte) ([]byte, error) {
+	// Validate parameters
+	if err := ValidateErrorCorrection(m, n); err != nil {
+		return nil, err
+	}
+
+	// Build command
+	return []byte{
+		common.GS, '(', 'k',
+		0x04, 0x00, // pL, pH
+		0x30,    // cn = 48
+		69,      // fn = 69
+		byte(m), // correction mode
+		n,       // level or ratio
+	}, nil
+}
+
+// SelectPDF417Options selects the standard or truncated PDF417 symbol.
+//
+// Format:
+//
+//	ASCII:   GS ( k pL pH cn fn n
+//	Hex:     0x1D 0x28 0x6B 0x03 0x00 0x30 0x46 n
+//	Decimal: 29 40 107 3 0 48 70 n
+//
+// Range:
+//
+//	(pL + pH × 256) = 3
+//	cn = 48
+//	fn = 70
+//	n = 0, 1
+//
+// Default:
+//
+//	n = 0
+//
+// Parameters:
+//
+//	n: Symbol type:
+//	   0 -> Standard PDF417
+//	   1 -> Truncated PDF417
+//
+// Notes:
+//   - Settings of this function affect the processing of GS ( k <Function 081> and GS ( k <Function 082>
+//   - Settings remain effective until ESC @ is executed, the printer is reset, or the power is turned off
+//   - Truncated PDF417 omits the right row indicators and shortens the stop pattern to one module,
+//     which saves width where the symbol is not likely to be damaged
+//
+// Errors:
+//
+//	Returns ErrOption if n is not 0 or 1
+func (c *Commands) SelectPDF417Options(n Option) ([]byte, error) {
+	// Validate parameter
+	if err := ValidateOption(n); err != nil {
+		return nil, err
+	}
+
+	// Build command
+	return []byte{
+		common.GS, '(', 'k',
+		0x03, 0x00, // pL, pH
+		0x30,    // cn = 48
+		70,      // fn = 70
+		byte(n), // option
+	}, nil
+}
+
+// StorePDF417Data stores the data in the PDF417 symbol storage area for later encoding and printing.
+//
+// Format:
+//
+//	ASCII:   GS ( k pL pH cn fn m d1...dk
+//	Hex:     0x1D 0x28 0x6B pL pH 0x30 0x50 0x30 d1...dk
+//	Decimal: 29 40 107 pL pH 48 80 48 d1...dk
+//
+// Range:
+//
+//	(pL + pH × 256) = 4–65535
+//	cn = 48
+//	fn = 80
+//	m = 48
+//	d = 0–255
+//	k = (pL + pH × 256) − 3
+//
+// Default:
+//
+//	None
+//
+// Parameters:
+//
+//	data: PDF417 symbol data to store (d1...dk)
+//
+// Notes:
+//   - Stores the PDF417 symbol data in the symbol storage area
+//   - The stored symbol data is encoded by GS ( k <Function 081> and GS ( k <Function 082>
+//   - After encoding/printing, the symbol data in the storage area is retained
+//   - k bytes of d1...dk are processed as symbol data
+//   - Settings remain effective until one of the following occurs:
+//   - GS ( k <Function 80/180/280/380/480> is executed (stores new data)
+//   - ESC @ is executed
+//   - The printer is reset or power is turned off
+//   - The command accepts up to 65532 bytes, but a symbol holds at most 1850 text characters,
+//     2710 digits or 1108 bytes; larger data is reported as not printable by GS ( k <Function 082>
+//
+// Errors:
+//
+//	Returns ErrDataTooShort if data is empty
+//	Returns ErrDataTooLong if data is longer than 65532 bytes
+func (c *Commands) StorePDF417Data(data []byte) ([]byte, error) {
+	// Validate data length
+	if err := ValidateDataLength(data); err != nil {
+		return nil, err
+	}
+
+	// Total length = 3 (cn + fn + m) + data length
+	totalLen := 3 + len(data)
+	pL := byte(totalLen & 0xFF)
+	pH := byte((totalLen >> 8) & 0xFF)
+
+	// Build command header
+	cmd := []byte{
+		common.GS, '(', 'k',
+		pL, pH, // length bytes
+		0x30, // cn = 48
+		80,   // fn = 80
+		0x30, // m = 48
+	}
+
+	// Append data
+	cmd = append(cmd, data...)
+
+	return cmd, nil
+}
+
+// PrintPDF417 encodes and prints the PDF417 symbol data stored in the symbol storage area.
+//
+// Format:
+//
+//	ASCII:   GS ( k pL pH cn fn m
+//	Hex:     0x1D 0x28 0x6B 0x03 0x00 0x30 0x51 m
+//	Decimal: 29 40 107 3 0 48 81 m
+//
+// Range:
+//
+//	(pL + pH × 256) = 3
+//	cn = 48
+//	fn = 81
+//	m = 48
+//
+// Default:
+//
+//	None
+//
+// Parameters:
+//
+//	None - All parameters are fixed for this function
+//
+// Notes:
+//   - Encodes and prints the PDF417 symbol data stored via GS ( k <Function 080>
+//   - In Standard mode, use this function when the printer is "at the beginning of a line" or
+//     "there is no data in the print buffer"
+//   - Symbol size that exceeds the print area cannot be printed
+//   - Printing fails if there are errors in the symbol storage area data:
+//   - No data exists (GS ( k <Function 080> was not executed)
+//   - Data does not fit in the selected columns, rows and error correction level
+//   - Start and stop patterns, row indicators and error correction codewords are added automatically
+//   - Printing is not affected by print modes (emphasized, double-strike, underline, white/black reverse,
+//     90° clockwise-rotated), except for upside-down print mode
+//   - In Standard mode: executes paper feeding for the symbol, moves print position to left side of
+//     printable area, and sets printer status to "Beginning of the line"
+//   - In Page mode: stores symbol data in print buffer without printing, moves print position to
+//     the next dot after the last data of the symbol
+//   - The quiet zone is NOT included in the printing data - ensure adequate quiet zone space
+//
+// Errors:
+//
+//	This function is safe and does not return errors
+func (c *Commands) PrintPDF417() []byte {
+	// Build command
+	return []byte{
+		common.GS, '(', 'k',
+		0x03, 0x00, // pL, pH
+		0x30, // cn = 48
+		81,   // fn = 81
+		0x30, // m = 48
+	}
+}
+
+// GetPDF417Size transmits the size information of the encoded PDF417 symbol data in the symbol storage area.
+//
+// Format:
+//
+//	ASCII:   GS ( k pL pH cn fn m
+//	Hex:     0x1D 0x28 0x6B 0x03 0x00 0x30 0x52 m
+//	Decimal: 29 40 107 3 0 48 82 m
+//
+// Range:
+//
+//	(pL + pH × 256) = 3
+//	cn = 48
+//	fn = 82
+//	m = 48
+//
+// Default:
+//
+//	None
+//
+// Parameters:
+//
+//	None - All parameters are fixed for this function
+//
+// Notes:
+//   - Transmits the size information for the encoded PDF417 symbol data stored via GS ( k <Function 080>
+//   - In Standard mode, use this function when the printer is "at the beginning of a line" or
+//     "there is no data in the print buffer"
+//   - The printer response follows this format:
+//   - Header: 1 byte (0x37, decimal 55)
+//   - Identifier: 1 byte (0x36, decimal 54)
+//   - Horizontal size: 1-5 bytes (ASCII digits 0-9)
+//   - Separator: 1 byte (0x1F, decimal 31)
+//   - Vertical size: 1-5 bytes (ASCII digits 0-9)
+//   - Separator: 1 byte (0x1F, decimal 31)
+//   - Other information: 1 byte
+//   - 0x30 (decimal 48): Printing is possible
+//   - 0x31 (decimal 49): Printing is impossible
+//   - NUL: 1 byte (0x00, decimal 0)
+//   - Horizontal size and vertical size indicate the number of dots of the symbol
+//   - The quiet zone is NOT included in the size information
+//   - This function does NOT print - it only transmits size information
+//   - If "Other information" indicates "Printing is impossible" (49), possible causes and solutions:
+//   - Cause: Data in print buffer (Standard mode)
+//     Solution: Execute GS T or print commands (LF, CR, ESC J) to clear buffer
+//   - Cause: Symbol is bigger than current print area
+//     Solution: Expand print area (GS W, ESC W, ESC $), reduce module width (GS ( k <Function 067>),
+//     or fix fewer columns (GS ( k <Function 065>)
+//   - Cause: Data does not fit in the symbol
+//     Solution: Use automatic columns and rows (GS ( k <Function 065>, <Function 066>)
+//     or lower the error correction level (GS ( k <Function 069>)
+//   - Cause: No data in symbol storage area
+//     Solution: Send data to storage area (GS ( k <Function 080>)
+//
+// Errors:
+//
+//	This function is safe and does not return errors
+func (c *Commands) GetPDF417Size() []byte {
+	// Build command
+	return []byte{
+		common.GS, '(', 'k',
+		0x03, 0x00, // pL, pH
+		0x30, // cn = 48
+		82,   // fn = 82
+		0x30, // m = 48
+	}
+}
@@ -0,0 +1,559 @@
+package pdf417_test
+
+import (
+	"testing"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+	"github.com/adcondev/pos-printer/pkg/commands/pdf417"
+)
+
+// ============================================================================
+// Test Data
+// ============================================================================
+
+var (
+	numericData  = []byte("123456789")
+	shippingData = []byte("1Z999AA10123456784^UPS^GROUND")
+	maxData      = testutils.RepeatByte(65532, 'A')
+	overMaxData  = testutils.RepeatByte(65533, 'B')
+)
+
+// ============================================================================
+// Symbol Layout Tests
+// ============================================================================
+
+func TestCommands_SetPDF417Columns(t *testing.T) {
+	cmd := pdf417.NewCommands()
+	prefix := []byte{0x1D, '(', 'k', 0x03, 0x00, 0x30, 0x41}
+
+	tests := []struct {
+		name    string
+		n       pdf417.Columns
+		want    []byte
+		wantErr error
+	}{
+		{"automatic (default)", pdf417.AutoColumns, append(prefix, 0), nil},
+		{"one column", 1, append(prefix, 1), nil},
+		{"medium 12", 12, append(prefix, 12), nil},
+		{"maximum 30", pdf417.MaxColumns, append(prefix, 30), nil},
+		{"invalid 31", 31, nil, pdf417.ErrColumns},
+		{"invalid 255", 255, nil, pdf417.ErrColumns},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmd.SetPDF417Columns(tt.n)
+
+			if !testutils.AssertErrorOccurred(t, err, tt.wantErr != nil, "SetPDF417Columns") {
+				return
+			}
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+
+			testutils.AssertBytes(t, got, tt.want, "SetPDF417Columns(%v)", tt.n)
+		})
+	}
+}
+
+func TestCommands_SetPDF417Rows(t *testing.T) {
+	cmd := pdf417.NewCommands()
+	prefix := []byte{0x1D, '(', 'k', 0x03, 0x00, 0x30, 0x42}
+
+	tests := []struct {
+		name    string
+		n       pdf417.Rows
+		want    []byte
+		wantErr error
+	}{
+		{"automatic (default)", pdf417.AutoRows, append(prefix, 0), nil},
+		{"minimum 3", pdf417.MinRows, append(prefix, 3), nil},
+		{"medium 40", 40, append(prefix, 40), nil},
+		{"maximum 90", pdf417.MaxRows, append(prefix, 90), nil},
+		{"invalid 1", 1, nil, pdf417.ErrRows},
+		{"invalid 2", 2, nil, pdf417.ErrRows},
+		{"invalid 91", 91, nil, pdf417.ErrRows},
+		{"invalid 255", 255, nil, pdf417.ErrRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmd.SetPDF417Rows(tt.n)
+
+			if !testutils.AssertErrorOccurred(t, err, tt.wantErr != nil, "SetPDF417Rows") {
+				return
+			}
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+
+			testutils.AssertBytes(t, got, tt.want, "SetPDF417Rows(%v)", tt.n)
+		})
+	}
+}
+
+func TestCommands_SetPDF417ModuleWidth(t *testing.T) {
+	cmd := pdf417.NewCommands()
+	prefix := []byte{0x1D, '(', 'k', 0x03, 0x00, 0x30, 0x43}
+
+	tests := []struct {
+		name    string
+		n       pdf417.ModuleWidth
+		want    []byte
+		wantErr error
+	}{
+		{"minimum 2", pdf417.MinModuleWidth, append(prefix, 2), nil},
+		{"default 3", pdf417.DefaultModuleWidth, append(prefix, 3), nil},
+		{"maximum 8", pdf417.MaxModuleWidth, append(prefix, 8), nil},
+		{"invalid 0", 0, nil, pdf417.ErrModuleWidth},
+		{"invalid 1", 1, nil, pdf417.ErrModuleWidth},
+		{"invalid 9", 9, nil, pdf417.ErrModuleWidth},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmd.SetPDF417ModuleWidth(tt.n)
+
+			if !testutils.AssertErrorOccurred(t, err, tt.wantErr != nil, "SetPDF417ModuleWidth") {
+				return
+			}
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+
+			testutils.AssertBytes(t, got, tt.want, "SetPDF417ModuleWidth(%v)", tt.n)
+		})
+	}
+}
+
+func TestCommands_SetPDF417RowHeight(t *testing.T) {
+	cmd := pdf417.NewCommands()
+	prefix := []byte{0x1D, '(', 'k', 0x03, 0x00, 0x30, 0x44}
+
+	tests := []struct {
+		name    string
+		n       pdf417.RowHeight
+		want    []byte
+		wantErr error
+	}{
+		{"minimum 2", pdf417.MinRowHeight, append(prefix, 2), nil},
+		{"default 3", pdf417.DefaultRowHeight, append(prefix, 3), nil},
+		{"maximum 8", pdf417.MaxRowHeight, append(prefix, 8), nil},
+		{"invalid 0", 0, nil, pdf417.ErrRowHeight},
+		{"invalid 1", 1, nil, pdf417.ErrRowHeight},
+		{"invalid 9", 9, nil, pdf417.ErrRowHeight},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmd.SetPDF417RowHeight(tt.n)
+
+			if !testutils.AssertErrorOccurred(t, err, tt.wantErr != nil, "SetPDF417RowHeight") {
+				return
+			}
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+
+			testutils.AssertBytes(t, got, tt.want, "SetPDF417RowHeight(%v)", tt.n)
+		})
+	}
+}
+
+// ============================================================================
+// SetPDF417ErrorCorrectionLevel Tests
+// ============================================================================
+
+func TestCommands_SetPDF417ErrorCorrectionLevel(t *testing.T) {
+	cmd := pdf417.NewCommands()
+	prefix := []byte{0x1D, '(', 'k', 0x04, 0x00, 0x30, 0x45}
+
+	tests := []struct {
+		name    string
+		m       pdf417.CorrectionMode
+		n       byte
+		want    []byte
+		wantErr error
+	}{
+		{"level 0", pdf417.ByLevel, byte(pdf417.Level0), append(prefix, 48, 48), nil},
+		{"level 4", pdf417.ByLevel, byte(pdf417.Level4), append(prefix, 48, 52), nil},
+		{"level 8", pdf417.ByLevel, byte(pdf417.Level8), append(prefix, 48, 56), nil},
+		{"ratio 10% (default)", pdf417.ByRatio, pdf417.DefaultRatio, append(prefix, 49, 1), nil},
+		{"ratio 200%", pdf417.ByRatio, 20, append(prefix, 49, 20), nil},
+		{"ratio 400%", pdf417.ByRatio, pdf417.MaxRatio, append(prefix, 49, 40), nil},
+		{"level 47", pdf417.ByLevel, 47, nil, pdf417.ErrErrorCorrection},
+		{"level 57", pdf417.ByLevel, 57, nil, pdf417.ErrErrorCorrection},
+		{"level given as ratio", pdf417.ByLevel, 1, nil, pdf417.ErrErrorCorrection},
+		{"ratio 0", pdf417.ByRatio, 0, nil, pdf417.ErrErrorCorrection},
+		{"ratio 41", pdf417.ByRatio, 41, nil, pdf417.ErrErrorCorrection},
+		{"ratio given as level", pdf417.ByRatio, byte(pdf417.Level2), nil, pdf417.ErrErrorCorrection},
+		{"mode 0", 0, 1, nil, pdf417.ErrCorrectionMode},
+		{"mode 50", 50, 1, nil, pdf417.ErrCorrectionMode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmd.SetPDF417ErrorCorrectionLevel(tt.m, tt.n)
+
+			if !testutils.AssertErrorOccurred(t, err, tt.wantErr != nil, "SetPDF417ErrorCorrectionLevel") {
+				return
+			}
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+
+			testutils.AssertBytes(t, got, tt.want, "SetPDF417ErrorCorrectionLevel(%v, %v)", tt.m, tt.n)
+		})
+	}
+}
+
+// ============================================================================
+// SelectPDF417Options Tests
+// ============================================================================
+
+func TestCommands_SelectPDF417Options(t *testing.T) {
+	cmd := pdf417.NewCommands()
+	prefix := []byte{0x1D, '(', 'k', 0x03, 0x00, 0x30, 0x46}
+
+	tests := []struct {
+		name    string
+		n       pdf417.Option
+		want    []byte
+		wantErr error
+	}{
+		{"standard (default)", pdf417.Standard, append(prefix, 0), nil},
+		{"truncated", pdf417.Truncated, append(prefix, 1), nil},
+		{"invalid 2", 2, nil, pdf417.ErrOption},
+		{"invalid ASCII 0", '0', nil, pdf417.ErrOption},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmd.SelectPDF417Options(tt.n)
+
+			if !testutils.AssertErrorOccurred(t, err, tt.wantErr != nil, "SelectPDF417Options") {
+				return
+			}
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+
+			testutils.AssertBytes(t, got, tt.want, "SelectPDF417Options(%v)", tt.n)
+		})
+	}
+}
+
+// ============================================================================
+// StorePDF417Data Tests
+// ============================================================================
+
+func TestCommands_StorePDF417Data(t *testing.T) {
+	cmd := pdf417.NewCommands()
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []byte
+		wantErr error
+	}{
+		{
+			name:    "single byte",
+			data:    []byte("A"),
+			want:    []byte{0x1D, '(', 'k', 0x04, 0x00, 0x30, 0x50, 0x30, 'A'},
+			wantErr: nil,
+		},
+		{
+			name:    "numeric data",
+			data:    numericData,
+			want:    append([]byte{0x1D, '(', 'k', 12, 0x00, 0x30, 0x50, 0x30}, numericData...),
+			wantErr: nil,
+		},
+		{
+			name:    "shipping label data",
+			data:    shippingData,
+			want:    append([]byte{0x1D, '(', 'k', 32, 0x00, 0x30, 0x50, 0x30}, shippingData...),
+			wantErr: nil,
+		},
+		{
+			name:    "binary data",
+			data:    []byte{0x00, 0xFF, 0x1D, 0x80},
+			want:    []byte{0x1D, '(', 'k', 0x07, 0x00, 0x30, 0x50, 0x30, 0x00, 0xFF, 0x1D, 0x80},
+			wantErr: nil,
+		},
+		{
+			name:    "empty data",
+			data:    []byte{},
+			wantErr: pdf417.ErrDataTooShort,
+		},
+		{
+			name:    "data too long (65533 bytes)",
+			data:    overMaxData,
+			wantErr: pdf417.ErrDataTooLong,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmd.StorePDF417Data(tt.data)
+
+			if !testutils.AssertErrorOccurred(t, err, tt.wantErr != nil, "StorePDF417Data") {
+				return
+			}
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+
+			testutils.AssertBytes(t, got, tt.want, "StorePDF417Data(%q)", tt.data)
+		})
+	}
+}
+
+func TestCommands_StorePDF417Data_LengthBytes(t *testing.T) {
+	cmd := pdf417.NewCommands()
+
+	tests := []struct {
+		name   string
+		length int
+		pL, pH byte
+	}{
+		{"253 bytes", 253, 0x00, 0x01},
+		{"1108 bytes", 1108, 0x57, 0x04},
+		{"maximum 65532 bytes", len(maxData), 0xFF, 0xFF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmd.StorePDF417Data(maxData[:tt.length])
+			if err != nil {
+				t.Fatalf("StorePDF417Data() error = %v", err)
+			}
+			testutils.AssertHasPrefix(t, got, []byte{0x1D, '(', 'k', tt.pL, tt.pH, 0x30, 0x50, 0x30}, "header")
+			if len(got) != 8+tt.length {
+				t.Errorf("len = %d, want %d", len(got), 8+tt.length)
+			}
+		})
+	}
+}
+
+// ============================================================================
+// PrintPDF417 and GetPDF417Size Tests
+// ============================================================================
+
+func TestCommands_PrintPDF417(t *testing.T) {
+	cmd := pdf417.NewCommands()
+
+	got := cmd.PrintPDF417()
+	want := []byte{0x1D, '(', 'k', 0x03, 0x00, 0x30, 0x51, 0x30}
+
+	testutils.AssertBytes(t, got, want, "PrintPDF417()")
+}
+
+func TestCommands_GetPDF417Size(t *testing.T) {
+	cmd := pdf417.NewCommands()
+
+	got := cmd.GetPDF417Size()
+	want := []byte{0x1D, '(', 'k', 0x03, 0x00, 0x30, 0x52, 0x30}
+
+	testutils.AssertBytes(t, got, want, "GetPDF417Size()")
+}
+
+// ============================================================================
+// Validation Helper Tests
+// ============================================================================
+
+func TestValidateRows(t *testing.T) {
+	for n := 0; n <= 255; n++ {
+		err := pdf417.ValidateRows(pdf417.Rows(n))
+		valid := n == 0 || (n >= 3 && n <= 90)
+		if valid {
+			testutils.AssertError(t, err, nil)
+		} else {
+			testutils.AssertError(t, err, pdf417.ErrRows)
+		}
+	}
+}
+
+func TestValidateErrorCorrection(t *testing.T) {
+	for n := 0; n <= 255; n++ {
+		levelErr := pdf417.ValidateErrorCorrection(pdf417.ByLevel, byte(n))
+		if n >= 48 && n <= 56 {
+			testutils.AssertError(t, levelErr, nil)
+		} else {
+			testutils.AssertError(t, levelErr, pdf417.ErrErrorCorrection)
+		}
+
+		ratioErr := pdf417.ValidateErrorCorrection(pdf417.ByRatio, byte(n))
+		if n >= 1 && n <= 40 {
+			testutils.AssertError(t, ratioErr, nil)
+		} else {
+			testutils.AssertError(t, ratioErr, pdf417.ErrErrorCorrection)
+		}
+	}
+}
+
+func TestValidateDataLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"valid minimum 1 byte", []byte("A"), nil},
+		{"valid maximum 65532 bytes", maxData, nil},
+		{"invalid empty", []byte{}, pdf417.ErrDataTooShort},
+		{"invalid nil", nil, pdf417.ErrDataTooShort},
+		{"invalid 65533 bytes", overMaxData, pdf417.ErrDataTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := pdf417.ValidateDataLength(tt.data)
+			testutils.AssertError(t, err, tt.wantErr)
+		})
+	}
+}
+
+// ============================================================================
+// Command Structure Tests
+// ============================================================================
+
+func TestCommands_CommandStructure(t *testing.T) {
+	cmd := pdf417.NewCommands()
+
+	columns, _ := cmd.SetPDF417Columns(pdf417.AutoColumns)
+	rows, _ := cmd.SetPDF417Rows(pdf417.AutoRows)
+	width, _ := cmd.SetPDF417ModuleWidth(pdf417.DefaultModuleWidth)
+	height, _ := cmd.SetPDF417RowHeight(pdf417.DefaultRowHeight)
+	correction, _ := cmd.SetPDF417ErrorCorrectionLevel(pdf417.ByRatio, pdf417.DefaultRatio)
+	options, _ := cmd.SelectPDF417Options(pdf417.Standard)
+	store, _ := cmd.StorePDF417Data(shippingData)
+
+	commands := map[string][]byte{
+		"SetPDF417Columns":              columns,
+		"SetPDF417Rows":                 rows,
+		"SetPDF417ModuleWidth":          width,
+		"SetPDF417RowHeight":            height,
+		"SetPDF417ErrorCorrectionLevel": correction,
+		"SelectPDF417Options":           options,
+		"StorePDF417Data":               store,
+		"PrintPDF417":                   cmd.PrintPDF417(),
+		"GetPDF417Size":                 cmd.GetPDF417Size(),
+	}
+
+	for name, got := range commands {
+		t.Run(name, func(t *testing.T) {
+			// GS ( k pL pH cn=48, with pL pH counting the bytes from cn onwards
+			testutils.AssertHasPrefix(t, got, []byte{common.GS, '(', 'k'}, name)
+			if got[5] != 0x30 {
+				t.Errorf("%s: cn = %#x, want 0x30", name, got[5])
+			}
+			if n := int(got[3]) | int(got[4])<<8; n != len(got)-5 {
+				t.Errorf("%s: pL pH = %d, want %d", name, n, len(got)-5)
+			}
+		})
+	}
+}
+
+// ============================================================================
+// Error Handling Tests
+// ============================================================================
+
+func TestCommands_ErrorMessages(t *testing.T) {
+	cmd := pdf417.NewCommands()
+
+	tests := []struct {
+		name string
+		err  func() error
+		want string
+	}{
+		{"columns", func() error { _, err := cmd.SetPDF417Columns(45); return err }, "45"},
+		{"rows", func() error { _, err := cmd.SetPDF417Rows(95); return err }, "95"},
+		{"module width", func() error { _, err := cmd.SetPDF417ModuleWidth(12); return err }, "12"},
+		{"row height", func() error { _, err := cmd.SetPDF417RowHeight(14); return err }, "14"},
+		{"correction mode", func() error { _, err := cmd.SetPDF417ErrorCorrectionLevel(77, 1); return err }, "77"},
+		{"correction level", func() error { _, err := cmd.SetPDF417ErrorCorrectionLevel(pdf417.ByLevel, 60); return err }, "60"},
+		{"correction ratio", func() error { _, err := cmd.SetPDF417ErrorCorrectionLevel(pdf417.ByRatio, 42); return err }, "42"},
+		{"option", func() error { _, err := cmd.SelectPDF417Options(7); return err }, "7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.err()
+			if err == nil || !testutils.ContainsAny([]byte(err.Error()), []byte(tt.want)) {
+				t.Errorf("error %v should contain invalid value %s", err, tt.want)
+			}
+		})
+	}
+}
+
+// ============================================================================
+// ParseSymbolSize Tests
+// ============================================================================
+
+func TestParseSymbolSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    []byte
+		want    pdf417.SymbolSize
+		wantErr error
+	}{
+		{
+			name: "printable symbol",
+			resp: []byte{0x37, 0x36, '2', '8', '8', 0x1F, '9', '0', 0x1F, 0x30, 0x00},
+			want: pdf417.SymbolSize{Width: 288, Height: 90, Printable: true},
+		},
+		{
+			name: "symbol too large",
+			resp: []byte{0x37, 0x36, '7', '2', '0', 0x1F, '3', '0', '0', 0x1F, 0x31, 0x00},
+			want: pdf417.SymbolSize{Width: 720, Height: 300, Printable: false},
+		},
+		{
+			name:    "QR Code layout",
+			resp:    []byte{0x37, 0x36, '2', '8', '8', 0x1F, '9', '0', 0x1F, 0x31, 0x1F, 0x30, 0x00},
+			wantErr: common.ErrResponse,
+		},
+		{
+			name:    "wrong identifier",
+			resp:    []byte{0x37, 0x30, '1', 0x1F, '1', 0x1F, 0x30, 0x00},
+			wantErr: common.ErrResponse,
+		},
+		{
+			name:    "missing fields",
+			resp:    []byte{0x37, 0x36, '2', '8', '8', 0x00},
+			wantErr: common.ErrResponse,
+		},
+		{
+			name:    "long other information",
+			resp:    []byte{0x37, 0x36, '1', 0x1F, '1', 0x1F, 0x30, 0x30, 0x00},
+			wantErr: common.ErrResponse,
+		},
+		{
+			name:    "non-numeric size",
+			resp:    []byte{0x37, 0x36, 'x', 0x1F, '1', 0x1F, 0x30, 0x00},
+			wantErr: common.ErrResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pdf417.ParseSymbolSize(tt.resp)
+			if !testutils.AssertErrorOccurred(t, err, tt.wantErr != nil, "ParseSymbolSize") {
+				return
+			}
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseSymbolSize() = %+v; want %+v", got, tt.want)
+			}
+		})
+	}
+}
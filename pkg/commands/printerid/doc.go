@@ -0,0 +1,10 @@
+// Package printerid implements ESC/POS commands for printer identification.
+//
+// ESC/POS is the command system used by thermal receipt printers. The GS I
+// command transmits single-byte IDs (model, type and version) and, through its
+// extended functions, "Header to NUL" blocks with the firmware version,
+// manufacturer, model name, serial number and multilingual fonts. GS ( I
+// transmits the same information in blocks tagged with the requested function,
+// so each answer can be matched to its request. Hosts use these answers to tell
+// printer models apart and select a matching profile.
+package printerid
@@ -0,0 +1,233 @@
+package printerid
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+)
+
+// ============================================================================
+// Context
+// ============================================================================
+// This package implements ESC/POS commands for printer identification.
+// ESC/POS is the command system used by thermal receipt printers. Basic IDs
+// are answered with one byte; printer information (firmware, maker, model...)
+// is answered with a "Header to NUL" block that starts with 0x5F (GS I) or
+// with 0x37 and the function number (GS ( I).
+
+// ============================================================================
+// Constant and Var Definitions
+// ============================================================================
+
+// InfoType selects the identification transmitted by GS I
+type InfoType byte
+
+const (
+	// ModelID transmits the printer model ID (1 byte)
+	ModelID InfoType = 1
+	// TypeID transmits the type ID flags (1 byte)
+	TypeID InfoType = 2
+	// VersionID transmits the ROM version ID (1 byte)
+	VersionID InfoType = 3
+	// ModelIDASCII transmits the printer model ID (ASCII mode)
+	ModelIDASCII InfoType = '1'
+	// TypeIDASCII transmits the type ID flags (ASCII mode)
+	TypeIDASCII InfoType = '2'
+	// VersionIDASCII transmits the ROM version ID (ASCII mode)
+	VersionIDASCII InfoType = '3'
+
+	// FirmwareVersion transmits the firmware version block
+	FirmwareVersion InfoType = 'A'
+	// Manufacturer transmits the maker name block
+	Manufacturer InfoType = 'B'
+	// ModelName transmits the printer name block
+	ModelName InfoType = 'C'
+	// SerialNumber transmits the serial number block
+	SerialNumber InfoType = 'D'
+	// Fonts transmits the printer font of language block (multilingual models)
+	Fonts InfoType = 'E'
+)
+
+// Type ID bits
+const (
+	bitMultiByte       byte = 0x01
+	bitCutter          byte = 0x02
+	bitCustomerDisplay byte = 0x04
+	// Bits 3, 4 and 7 of the type ID are fixed to 0
+	typeFixedMask byte = 0x98
+)
+
+// TypeFlags is the decoded type ID
+type TypeFlags struct {
+	MultiByte       bool // Two-byte (multi-byte) character codes are supported
+	Cutter          bool // An autocutter is installed
+	CustomerDisplay bool // A customer display is connected
+}
+
+// Identification collects the answers of a printer to the GS I functions.
+// Fields the printer did not answer are left empty.
+type Identification struct {
+	ModelID   byte
+	Type      TypeFlags
+	TypeKnown bool // The printer answered the type ID request
+	VersionID byte
+
+	Firmware     string
+	Manufacturer string
+	Model        string
+	SerialNumber string
+	Fonts        string
+}
+
+// ============================================================================
+// Error Definitions
+// ============================================================================
+
+var (
+	// ErrInfoType indicates an invalid GS I function
+	ErrInfoType = errors.New("invalid printer ID type (try 1-3, 49-51 or 65-69)")
+	// ErrTypeID indicates a byte that is not a valid type ID response
+	ErrTypeID = errors.New("invalid type ID response")
+	// ErrInfoFunction indicates an invalid GS ( I function
+	ErrInfoFunction = errors.New("invalid printer information function (try 65-69)")
+)
+
+// ============================================================================
+// Interface Definitions
+// ============================================================================
+
+// Compile-time check that Commands implements Capability
+var _ Capability = (*Commands)(nil)
+
+// Capability defines the interface for printer identification commands
+type Capability interface {
+	TransmitPrinterID(n InfoType) ([]byte, error)
+	TransmitPrinterInfo(fn InfoType) ([]byte, error)
+}
+
+// ============================================================================
+// Main Implementation
+// ============================================================================
+
+// Commands implements the Capability interface for printer identification
+type Commands struct{}
+
+// NewCommands creates a new Commands instance
+func NewCommands() *Commands {
+	return &Commands{}
+}
+
+// ============================================================================
+// Validation Helper Functions
+// ============================================================================
+
+// ValidateInfoType validates if the GS I function is valid
+func ValidateInfoType(n InfoType) error {
+	switch n {
+	case ModelID, TypeID, VersionID, ModelIDASCII, TypeIDASCII, VersionIDASCII,
+		FirmwareVersion, Manufacturer, ModelName, SerialNumber, Fonts:
+		return nil
+	default:
+		return fmt.Errorf("%w: %d", ErrInfoType, n)
+	}
+}
+
+// ValidateInfoFunction validates if the GS ( I function is valid
+func ValidateInfoFunction(fn InfoType) error {
+	if !IsBlockInfo(fn) {
+		return fmt.Errorf("%w: %d", ErrInfoFunction, fn)
+	}
+	return nil
+}
+
+// IsBlockInfo reports whether n is answered with a "Header to NUL" block instead of a single byte
+func IsBlockInfo(n InfoType) bool {
+	return n >= FirmwareVersion && n <= Fonts
+}
+
+// ============================================================================
+// Response Functions
+// ============================================================================
+
+// ParseTypeID decodes the response to GS I 2
+func ParseTypeID(b byte) (TypeFlags, error) {
+	if b&typeFixedMask != 0 {
+		return TypeFlags{}, fmt.Errorf("%w: %#02x", ErrTypeID, b)
+	}
+	return TypeFlags{
+		MultiByte:       b&bitMultiByte != 0,
+		Cutter:          b&bitCutter != 0,
+		CustomerDisplay: b&bitCustomerDisplay != 0,
+	}, nil
+}
+
+// ParseInfo decodes a printer information block (GS I 65-69) into its text.
+// Bytes before the block header are ignored.
+func ParseInfo(resp []byte) (string, error) {
+	if i := bytes.IndexByte(resp, common.IDBlockHeader); i > 0 {
+		resp = resp[i:]
+	}
+	if len(resp) < 2 {
+		return "", fmt.Errorf("%w: block too short (%d bytes)", common.ErrResponse, len(resp))
+	}
+	if resp[0] != common.IDBlockHeader {
+		return "", fmt.Errorf("%w: header %#x, want %#x", common.ErrResponse, resp[0], common.IDBlockHeader)
+	}
+	if resp[len(resp)-1] != common.NUL {
+		return "", fmt.Errorf("%w: missing NUL terminator", common.ErrResponse)
+	}
+	return string(resp[1 : len(resp)-1]), nil
+}
+
+// InfoFrame reports the length of the first complete information block in buf,
+// or 0 if more bytes are needed. Bytes before the block header are counted as
+// part of the frame so stale data is discarded together with the block.
+func InfoFrame(buf []byte) int {
+	start := -1
+	for i, b := range buf {
+		if start < 0 {
+			if b == common.IDBlockHeader {
+				start = i
+			}
+			continue
+		}
+		if b == common.NUL {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// ParseInfoBlock decodes the response to GS ( I fn into its text.
+// The block must carry fn as identifier, so answers to other requests are rejected.
+func ParseInfoBlock(resp []byte, fn InfoType) (string, error) {
+	payload, err := common.ParseBlock(resp, common.BlockHeader, byte(fn))
+	if err != nil {
+		return "", err
+	}
+	return string(payload), nil
+}
+
+// InfoBlockFrame returns a frame function for the response to GS ( I fn. It reports
+// the length of the first complete block identified by fn, or 0 if more bytes are
+// needed. Bytes before that block, including answers to other functions, are
+// counted as part of the frame so they are discarded together with it.
+func InfoBlockFrame(fn InfoType) func(buf []byte) int {
+	return func(buf []byte) int {
+		start := -1
+		for i, b := range buf {
+			if start < 0 {
+				if b == common.BlockHeader && i+1 < len(buf) && buf[i+1] == byte(fn) {
+					start = i
+				}
+				continue
+			}
+			if i > start+1 && b == common.NUL {
+				return i + 1
+			}
+		}
+		return 0
+	}
+}
@@ -0,0 +1,98 @@
+package printerid
+
+import (
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+)
+
+// TransmitPrinterID transmits the printer ID or printer information selected by n.
+//
+// Format:
+//
+//	ASCII:   GS I n
+//	Hex:     0x1D 0x49 n
+//	Decimal: 29 73 n
+//
+// Range:
+//
+//	n = 1–3, 49–51, 65–69
+//
+// Default:
+//
+//	None
+//
+// Parameters:
+//
+//	n: Specifies the information to transmit:
+//	   1, 49 -> Printer model ID (1 byte)
+//	   2, 50 -> Type ID (1 byte):
+//	            bit 0 -> Two-byte character codes supported
+//	            bit 1 -> Autocutter installed
+//	            bit 2 -> Customer display connected
+//	   3, 51 -> Version ID (1 byte)
+//	   65    -> Firmware version ("Header to NUL" block)
+//	   66    -> Maker name ("Header to NUL" block)
+//	   67    -> Printer name ("Header to NUL" block)
+//	   68    -> Serial number ("Header to NUL" block)
+//	   69    -> Printer font of language ("Header to NUL" block)
+//
+// Notes:
+//   - Block responses are 0x5F, the information as ASCII text, and NUL
+//   - The command is processed in order with the print data, so the answer may be delayed
+//     while the receive buffer holds data to print
+//   - Printers that do not support a function do not answer it; use a timeout
+//   - Bit 4 of the single-byte responses is fixed to 0, which tells them apart from ASB frames
+//
+// Errors:
+//
+//	Returns ErrInfoType if n is not a valid function.
+func (c *Commands) TransmitPrinterID(n InfoType) ([]byte, error) {
+	if err := ValidateInfoType(n); err != nil {
+		return nil, err
+	}
+	return []byte{common.GS, 'I', byte(n)}, nil
+}
+
+// TransmitPrinterInfo transmits the printer information selected by fn as a block
+// that carries fn as identifier.
+//
+// Format:
+//
+//	ASCII:   GS ( I pL pH fn
+//	Hex:     0x1D 0x28 0x49 0x01 0x00 fn
+//	Decimal: 29 40 73 1 0 fn
+//
+// Range:
+//
+//	(pL + pH × 256) = 1 (pL = 1, pH = 0)
+//	fn = 65–69
+//
+// Default:
+//
+//	None
+//
+// Parameters:
+//
+//	fn: Specifies the information to transmit:
+//	    65 -> Firmware version
+//	    66 -> Maker name
+//	    67 -> Printer name
+//	    68 -> Serial number
+//	    69 -> Printer font of language
+//
+// Notes:
+//   - Responses are 0x37, fn, the information as ASCII text, and NUL; the identifier
+//     lets the host match each answer to its request and skip late answers
+//   - Printers that do not implement the function skip its pL pH parameter bytes and do
+//     not answer it; use a timeout and fall back to GS I
+//   - The command is processed in order with the print data, so the answer may be delayed
+//     while the receive buffer holds data to print
+//
+// Errors:
+//
+//	Returns ErrInfoFunction if fn is not a valid function.
+func (c *Commands) TransmitPrinterInfo(fn InfoType) ([]byte, error) {
+	if err := ValidateInfoFunction(fn); err != nil {
+		return nil, err
+	}
+	return []byte{common.GS, '(', 'I', 0x01, 0x00, byte(fn)}, nil
+}
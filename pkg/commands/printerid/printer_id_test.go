@@ -0,0 +1,222 @@
+package printerid_test
+
+import (
+	"testing"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+	"github.com/adcondev/pos-printer/pkg/commands/printerid"
+)
+
+// ============================================================================
+// Command Tests
+// ============================================================================
+
+func TestCommands_TransmitPrinterID(t *testing.T) {
+	cmd := printerid.NewCommands()
+
+	tests := []struct {
+		name    string
+		n       printerid.InfoType
+		want    []byte
+		wantErr error
+	}{
+		{"model ID", printerid.ModelID, []byte{common.GS, 'I', 1}, nil},
+		{"type ID ASCII", printerid.TypeIDASCII, []byte{common.GS, 'I', '2'}, nil},
+		{"firmware", printerid.FirmwareVersion, []byte{common.GS, 'I', 65}, nil},
+		{"fonts", printerid.Fonts, []byte{common.GS, 'I', 69}, nil},
+		{"zero", 0, nil, printerid.ErrInfoType},
+		{"undefined block", 70, nil, printerid.ErrInfoType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmd.TransmitPrinterID(tt.n)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("TransmitPrinterID(%d) error = %v", tt.n, err)
+			}
+			testutils.AssertBytes(t, got, tt.want, "TransmitPrinterID(%d)", tt.n)
+		})
+	}
+}
+
+func TestCommands_TransmitPrinterInfo(t *testing.T) {
+	cmd := printerid.NewCommands()
+
+	tests := []struct {
+		name    string
+		fn      printerid.InfoType
+		want    []byte
+		wantErr error
+	}{
+		{"firmware", printerid.FirmwareVersion, []byte{common.GS, '(', 'I', 0x01, 0x00, 65}, nil},
+		{"model name", printerid.ModelName, []byte{common.GS, '(', 'I', 0x01, 0x00, 67}, nil},
+		{"fonts", printerid.Fonts, []byte{common.GS, '(', 'I', 0x01, 0x00, 69}, nil},
+		{"single-byte ID", printerid.ModelID, nil, printerid.ErrInfoFunction},
+		{"ASCII type ID", printerid.TypeIDASCII, nil, printerid.ErrInfoFunction},
+		{"undefined", 70, nil, printerid.ErrInfoFunction},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmd.TransmitPrinterInfo(tt.fn)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("TransmitPrinterInfo(%d) error = %v", tt.fn, err)
+			}
+			testutils.AssertBytes(t, got, tt.want, "TransmitPrinterInfo(%d)", tt.fn)
+		})
+	}
+}
+
+// ============================================================================
+// Response Decoding Tests
+// ============================================================================
+
+func TestParseTypeID(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       byte
+		want    printerid.TypeFlags
+		wantErr error
+	}{
+		{"no options", 0x00, printerid.TypeFlags{}, nil},
+		{"cutter", 0x02, printerid.TypeFlags{Cutter: true}, nil},
+		{"multi-byte with cutter", 0x03, printerid.TypeFlags{MultiByte: true, Cutter: true}, nil},
+		{"customer display", 0x04, printerid.TypeFlags{CustomerDisplay: true}, nil},
+		{"fixed bit set", 0x12, printerid.TypeFlags{}, printerid.ErrTypeID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := printerid.ParseTypeID(tt.b)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTypeID(%#02x) error = %v", tt.b, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTypeID(%#02x) = %+v, want %+v", tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    []byte
+		want    string
+		wantErr error
+	}{
+		{"maker", []byte("_EPSON\x00"), "EPSON", nil},
+		{"model", []byte("_TM-T20II\x00"), "TM-T20II", nil},
+		{"empty", []byte("_\x00"), "", nil},
+		{"stale byte before header", []byte("\x16_1.01 ESC/POS\x00"), "1.01 ESC/POS", nil},
+		{"wrong header", []byte("7EPSON\x00"), "", common.ErrResponse},
+		{"missing NUL", []byte("_EPSON"), "", common.ErrResponse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := printerid.ParseInfo(tt.resp)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseInfo(%q) error = %v", tt.resp, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseInfo(%q) = %q, want %q", tt.resp, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInfoFrame(t *testing.T) {
+	tests := []struct {
+		name string
+		buf  []byte
+		want int
+	}{
+		{"complete", []byte("_EPSON\x00"), 7},
+		{"incomplete", []byte("_EPS"), 0},
+		{"NUL before header is ignored", []byte("\x00_AB\x00rest"), 5},
+		{"no header", []byte{0x16}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := printerid.InfoFrame(tt.buf); got != tt.want {
+				t.Errorf("InfoFrame(%q) = %d, want %d", tt.buf, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInfoBlock(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    []byte
+		fn      printerid.InfoType
+		want    string
+		wantErr error
+	}{
+		{"model", []byte{0x37, 'C', 'T', 'M', '-', 'T', '8', '8', 0x00}, printerid.ModelName, "TM-T88", nil},
+		{"maker", []byte{0x37, 'B', 'E', 'P', 'S', 'O', 'N', 0x00}, printerid.Manufacturer, "EPSON", nil},
+		{"empty", []byte{0x37, 'D', 0x00}, printerid.SerialNumber, "", nil},
+		{"answer to another function", []byte{0x37, 'B', 'E', 'P', 'S', 'O', 'N', 0x00}, printerid.ModelName, "", common.ErrResponse},
+		{"GS I header", []byte("_EPSON\x00"), printerid.Manufacturer, "", common.ErrResponse},
+		{"missing NUL", []byte{0x37, 'B', 'E'}, printerid.Manufacturer, "", common.ErrResponse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := printerid.ParseInfoBlock(tt.resp, tt.fn)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseInfoBlock(%q) error = %v", tt.resp, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseInfoBlock(%q) = %q, want %q", tt.resp, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInfoBlockFrame(t *testing.T) {
+	tests := []struct {
+		name string
+		buf  []byte
+		want int
+	}{
+		{"complete", []byte{0x37, 'C', 'T', 'M', 0x00}, 5},
+		{"incomplete", []byte{0x37, 'C', 'T', 'M'}, 0},
+		{"header only", []byte{0x37}, 0},
+		{"late answer before block", []byte{0x37, 'B', 'E', 0x00, 0x37, 'C', 'T', 0x00}, 8},
+		{"GS I block before block", []byte{'_', 'T', 0x00, 0x37, 'C', 0x00}, 6},
+		{"only other answers", []byte{0x37, 'B', 'E', 0x00}, 0},
+	}
+
+	frame := printerid.InfoBlockFrame(printerid.ModelName)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := frame(tt.buf); got != tt.want {
+				t.Errorf("InfoBlockFrame(ModelName)(%q) = %d, want %d", tt.buf, got, tt.want)
+			}
+		})
+	}
+}
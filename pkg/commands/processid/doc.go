@@ -0,0 +1,8 @@
+// Package processid implements ESC/POS commands for print completion confirmation.
+//
+// ESC/POS is the command system used by thermal receipt printers. The GS ( H
+// command embeds a process ID in the print data; the printer answers it with a
+// "Header to NUL" block only after every command received before it has been
+// processed. Sending a process ID after the last command of a job lets the host
+// confirm that the job was actually printed.
+package processid
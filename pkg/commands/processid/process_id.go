@@ -0,0 +1,128 @@
+package processid
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+)
+
+// ============================================================================
+// Context
+// ============================================================================
+// This package implements ESC/POS commands for print completion confirmation.
+// ESC/POS is the command system used by thermal receipt printers. The printer
+// processes GS ( H in order with the print data and answers with the same
+// process ID once everything sent before it has been printed.
+
+// ============================================================================
+// Constant and Var Definitions
+// ============================================================================
+
+// ID is a process ID; the printer transmits it as four ASCII decimal digits
+type ID uint16
+
+const (
+	// MaxID is the largest process ID that fits in four decimal digits
+	MaxID ID = 9999
+	// idDigits is the number of digits of a process ID
+	idDigits = 4
+)
+
+// Function and mode bytes of GS ( H
+const (
+	// FnProcessID requests the transmission of a process ID response
+	FnProcessID byte = 48
+	// ModeProcessID is the only valid mode of FnProcessID
+	ModeProcessID byte = 48
+)
+
+// ResponseIdentifier is the identifier byte of the process ID response block
+const ResponseIdentifier byte = 0x22
+
+// ============================================================================
+// Error Definitions
+// ============================================================================
+
+var (
+	// ErrProcessID indicates a process ID outside 0-9999
+	ErrProcessID = errors.New("invalid process ID (try 0-9999)")
+)
+
+// ============================================================================
+// Interface Definitions
+// ============================================================================
+
+// Compile-time check that Commands implements Capability
+var _ Capability = (*Commands)(nil)
+
+// Capability defines the interface for print completion confirmation commands
+type Capability interface {
+	TransmitProcessID(id ID) ([]byte, error)
+}
+
+// ============================================================================
+// Main Implementation
+// ============================================================================
+
+// Commands implements the Capability interface for print completion confirmation
+type Commands struct{}
+
+// NewCommands creates a new Commands instance
+func NewCommands() *Commands {
+	return &Commands{}
+}
+
+// Digits returns the process ID as the four ASCII digits sent to the printer
+func (id ID) Digits() [idDigits]byte {
+	var d [idDigits]byte
+	n := uint16(id)
+	for i := idDigits - 1; i >= 0; i-- {
+		d[i] = '0' + byte(n%10)
+		n /= 10
+	}
+	return d
+}
+
+// String returns the process ID as four decimal digits
+func (id ID) String() string {
+	d := id.Digits()
+	return string(d[:])
+}
+
+// ============================================================================
+// Validation Helper Functions
+// ============================================================================
+
+// ValidateID validates if the process ID fits in four decimal digits
+func ValidateID(id ID) error {
+	if id > MaxID {
+		return fmt.Errorf("%w: %d", ErrProcessID, id)
+	}
+	return nil
+}
+
+// ============================================================================
+// Response Functions
+// ============================================================================
+
+// IsResponse reports whether resp is a process ID response block
+func IsResponse(resp []byte) bool {
+	return len(resp) >= 2 && resp[0] == common.BlockHeader && resp[1] == ResponseIdentifier
+}
+
+// ParseResponse decodes the process ID carried by a response to TransmitProcessID
+func ParseResponse(resp []byte) (ID, error) {
+	payload, err := common.ParseBlock(resp, common.BlockHeader, ResponseIdentifier)
+	if err != nil {
+		return 0, err
+	}
+	if len(payload) != idDigits {
+		return 0, fmt.Errorf("%w: process ID %q, want %d digits", common.ErrResponse, payload, idDigits)
+	}
+	n, err := common.ParseDecimal(payload)
+	if err != nil {
+		return 0, err
+	}
+	return ID(n), nil
+}
@@ -0,0 +1,51 @@
+package processid
+
+import (
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+)
+
+// TransmitProcessID requests the transmission of a process ID response.
+//
+// Format:
+//
+//	ASCII:   GS ( H pL pH fn m d1 d2 d3 d4
+//	Hex:     0x1D 0x28 0x48 0x06 0x00 0x30 0x30 d1 d2 d3 d4
+//	Decimal: 29 40 72 6 0 48 48 d1 d2 d3 d4
+//
+// Range:
+//
+//	(pL + pH × 256) = 6
+//	fn = 48
+//	m = 48
+//	d1, d2, d3, d4 = 48–57 ("0"–"9")
+//
+// Default:
+//
+//	None
+//
+// Parameters:
+//
+//	id: Process ID (0–9999), sent as the four ASCII digits d1 d2 d3 d4
+//
+// Notes:
+//   - The printer answers after every command received before this one has been processed,
+//     so the response confirms that the preceding print data was printed
+//   - The response is a "Header to NUL" block: 0x37 0x22 d1 d2 d3 d4 0x00
+//   - While the printer is offline or in an error state the response is not transmitted
+//   - Use a different process ID for each job so a late response is not mistaken for another job
+//
+// Errors:
+//
+//	Returns ErrProcessID if id is greater than 9999.
+func (c *Commands) TransmitProcessID(id ID) ([]byte, error) {
+	if err := ValidateID(id); err != nil {
+		return nil, err
+	}
+	d := id.Digits()
+	return []byte{
+		common.GS, '(', 'H',
+		0x06, 0x00, // pL, pH
+		FnProcessID, ModeProcessID,
+		d[0], d[1], d[2], d[3],
+	}, nil
+}
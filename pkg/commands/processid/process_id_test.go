@@ -0,0 +1,94 @@
+package processid_test
+
+import (
+	"testing"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+	"github.com/adcondev/pos-printer/pkg/commands/processid"
+)
+
+// ============================================================================
+// Command Tests
+// ============================================================================
+
+func TestCommands_TransmitProcessID(t *testing.T) {
+	cmd := processid.NewCommands()
+
+	tests := []struct {
+		name    string
+		id      processid.ID
+		want    []byte
+		wantErr error
+	}{
+		{"zero", 0, []byte{common.GS, '(', 'H', 6, 0, 48, 48, '0', '0', '0', '0'}, nil},
+		{"padded", 42, []byte{common.GS, '(', 'H', 6, 0, 48, 48, '0', '0', '4', '2'}, nil},
+		{"maximum", processid.MaxID, []byte{common.GS, '(', 'H', 6, 0, 48, 48, '9', '9', '9', '9'}, nil},
+		{"too large", 10000, nil, processid.ErrProcessID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmd.TransmitProcessID(tt.id)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("TransmitProcessID(%d) error = %v", tt.id, err)
+			}
+			testutils.AssertBytes(t, got, tt.want, "TransmitProcessID(%d)", tt.id)
+		})
+	}
+}
+
+// ============================================================================
+// Response Decoding Tests
+// ============================================================================
+
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    []byte
+		want    processid.ID
+		wantErr error
+	}{
+		{"valid", []byte{0x37, 0x22, '0', '1', '2', '3', 0x00}, 123, nil},
+		{"maximum", []byte{0x37, 0x22, '9', '9', '9', '9', 0x00}, 9999, nil},
+		{"wrong identifier", []byte{0x37, 0x36, '0', '0', '0', '1', 0x00}, 0, common.ErrResponse},
+		{"short ID", []byte{0x37, 0x22, '1', '2', 0x00}, 0, common.ErrResponse},
+		{"non-digit", []byte{0x37, 0x22, '1', 'x', '2', '3', 0x00}, 0, common.ErrResponse},
+		{"missing NUL", []byte{0x37, 0x22, '0', '0', '0', '1'}, 0, common.ErrResponse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := processid.ParseResponse(tt.resp)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseResponse(%q) error = %v", tt.resp, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseResponse(%q) = %d, want %d", tt.resp, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestID_String(t *testing.T) {
+	if got := processid.ID(7).String(); got != "0007" {
+		t.Errorf("ID(7).String() = %q, want %q", got, "0007")
+	}
+}
+
+func TestIsResponse(t *testing.T) {
+	if !processid.IsResponse([]byte{0x37, 0x22, '0', '0', '0', '0', 0x00}) {
+		t.Error("IsResponse() = false for a process ID block")
+	}
+	if processid.IsResponse([]byte{0x37, 0x36, '1', 0x00}) {
+		t.Error("IsResponse() = true for a QR size block")
+	}
+}
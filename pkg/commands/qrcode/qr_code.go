@@ -1,8 +1,11 @@
 package qrcode
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+
+	"github.com/adcondev/pos-printer/pkg/commands/common"
 )
 
 // ============================================================================
@@ -55,6 +58,23 @@ const (
 	LevelH ErrorCorrection = 51
 )
 
+// Size information response (GS ( k <Function 182>)
+const (
+	// SizeInfoIdentifier is the identifier byte of the size information response
+	SizeInfoIdentifier byte = 0x36
+	// sizeInfoSeparator separates the fields of the size information response
+	sizeInfoSeparator byte = 0x1F
+	// sizeInfoPrintable is the "other information" value when the symbol can be printed
+	sizeInfoPrintable byte = 0x30
+)
+
+// SymbolSize is the decoded size information of the symbol in the storage area
+type SymbolSize struct {
+	Width     int  // Horizontal size in dots (quiet zone excluded)
+	Height    int  // Vertical size in dots (quiet zone excluded)
+	Printable bool // Whether the symbol can be printed with current settings
+}
+
 // Data limits
 const (
 	MinDataLength = 1    // Minimum data length
@@ -184,3 +204,36 @@ func ValidateDataLength(data []byte) error {
 	}
 	return nil
 }
+
+// ============================================================================
+// Response Functions
+// ============================================================================
+
+// ParseSymbolSize decodes the printer response to GetQRCodeSize
+func ParseSymbolSize(resp []byte) (SymbolSize, error) {
+	payload, err := common.ParseBlock(resp, common.BlockHeader, SizeInfoIdentifier)
+	if err != nil {
+		return SymbolSize{}, err
+	}
+
+	// horizontal 0x1F vertical 0x1F '1' 0x1F other
+	fields := bytes.Split(payload, []byte{sizeInfoSeparator})
+	if len(fields) != 4 || len(fields[3]) != 1 {
+		return SymbolSize{}, fmt.Errorf("%w: malformed size information %q", common.ErrResponse, payload)
+	}
+
+	width, err := common.ParseDecimal(fields[0])
+	if err != nil {
+		return SymbolSize{}, err
+	}
+	height, err := common.ParseDecimal(fields[1])
+	if err != nil {
+		return SymbolSize{}, err
+	}
+
+	return SymbolSize{
+		Width:     width,
+		Height:    height,
+		Printable: fields[3][0] == sizeInfoPrintable,
+	}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/commands/common"
 	"github.com/adcondev/pos-printer/pkg/commands/qrcode"
 )
 
@@ -908,3 +909,58 @@ func TestCommands_ErrorMessages(t *testing.T) {
 		}
 	})
 }
+
+// ============================================================================
+// ParseSymbolSize Tests
+// ============================================================================
+
+func TestParseSymbolSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    []byte
+		want    qrcode.SymbolSize
+		wantErr error
+	}{
+		{
+			name: "printable symbol",
+			resp: []byte{0x37, 0x36, '1', '2', '0', 0x1F, '1', '2', '0', 0x1F, 0x31, 0x1F, 0x30, 0x00},
+			want: qrcode.SymbolSize{Width: 120, Height: 120, Printable: true},
+		},
+		{
+			name: "symbol too large",
+			resp: []byte{0x37, 0x36, '6', '0', '0', 0x1F, '6', '0', '0', 0x1F, 0x31, 0x1F, 0x31, 0x00},
+			want: qrcode.SymbolSize{Width: 600, Height: 600, Printable: false},
+		},
+		{
+			name:    "wrong identifier",
+			resp:    []byte{0x37, 0x30, '1', 0x1F, '1', 0x1F, 0x31, 0x1F, 0x30, 0x00},
+			wantErr: common.ErrResponse,
+		},
+		{
+			name:    "missing fields",
+			resp:    []byte{0x37, 0x36, '1', '2', '0', 0x00},
+			wantErr: common.ErrResponse,
+		},
+		{
+			name:    "non-numeric size",
+			resp:    []byte{0x37, 0x36, 'x', 0x1F, '1', 0x1F, 0x31, 0x1F, 0x30, 0x00},
+			wantErr: common.ErrResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := qrcode.ParseSymbolSize(tt.resp)
+			if !testutils.AssertErrorOccurred(t, err, tt.wantErr != nil, "ParseSymbolSize") {
+				return
+			}
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseSymbolSize() = %+v; want %+v", got, tt.want)
+			}
+		})
+	}
+}
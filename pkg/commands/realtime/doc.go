@@ -0,0 +1,7 @@
+// Package realtime implements ESC/POS real-time commands.
+//
+// ESC/POS is the command system used by thermal receipt printers. Real-time
+// commands are processed as soon as they are received, even while the printer
+// is offline or its receive buffer is full, which makes them suitable for
+// polling the printer status (DLE EOT) without waiting for pending print data.
+package realtime
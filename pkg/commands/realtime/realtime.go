@@ -0,0 +1,262 @@
+package realtime
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ============================================================================
+// Context
+// ============================================================================
+// This package implements ESC/POS real-time commands.
+// ESC/POS is the command system used by thermal receipt printers. Real-time
+// commands are executed immediately on reception, so the printer answers
+// status requests even when it is offline or busy printing.
+
+// ============================================================================
+// Constant and Var Definitions
+// ============================================================================
+
+// StatusType selects the status transmitted by DLE EOT
+type StatusType byte
+
+const (
+	// PrinterStatus requests the printer status (drawer pin, online/offline, feed button)
+	PrinterStatus StatusType = 1
+	// OfflineCauseStatus requests the offline cause status (cover, paper feed, paper end stop)
+	OfflineCauseStatus StatusType = 2
+	// ErrorCauseStatus requests the error cause status (cutter, unrecoverable errors)
+	ErrorCauseStatus StatusType = 3
+	// PaperSensorStatus requests the roll paper sensor status (near-end, end)
+	PaperSensorStatus StatusType = 4
+	// InkStatus requests the ink status (extended form, DLE EOT 7 a)
+	InkStatus StatusType = 7
+	// PeelerStatus requests the peeler status (extended form, DLE EOT 8 a)
+	PeelerStatus StatusType = 8
+)
+
+// InkUnit selects the ink cartridge reported by DLE EOT 7
+type InkUnit byte
+
+const (
+	// InkA selects the first ink (black or single color)
+	InkA InkUnit = 1
+	// InkB selects the second ink (two-color models)
+	InkB InkUnit = 2
+)
+
+// peelerFunction is the only valid parameter for DLE EOT 8
+const peelerFunction byte = 3
+
+// Status byte layout shared by every DLE EOT response: bits 1 and 4 are fixed to 1,
+// bits 0 and 7 are fixed to 0
+const (
+	fixedMask  byte = 0x93
+	fixedValue byte = 0x12
+)
+
+// Status bits
+const (
+	// DLE EOT 1
+	bitDrawerPin  byte = 0x04
+	bitOffline    byte = 0x08
+	bitWaitOnline byte = 0x20
+	bitFeedButton byte = 0x40
+
+	// DLE EOT 2
+	bitCoverOpen     byte = 0x04
+	bitFeedByButton  byte = 0x08
+	bitPaperEndStop  byte = 0x20
+	bitErrorOccurred byte = 0x40
+
+	// DLE EOT 3
+	bitRecoverableError     byte = 0x04
+	bitCutterError          byte = 0x08
+	bitUnrecoverableError   byte = 0x20
+	bitAutoRecoverableError byte = 0x40
+
+	// DLE EOT 4, DLE EOT 7 (both bits of each pair are set together)
+	bitsNearEnd byte = 0x0C
+	bitsEnd     byte = 0x60
+
+	// DLE EOT 8
+	bitPaperWaiting byte = 0x04
+)
+
+// Status is the decoded printer state reported by DLE EOT 1 to 4
+type Status struct {
+	// DLE EOT 1: printer status
+	DrawerPinHigh     bool // Drawer kick-out connector pin 3 is high
+	Offline           bool // Printer is offline
+	WaitingOnline     bool // Printer is waiting for online recovery
+	FeedButtonPressed bool // Paper feed button is being pressed
+
+	// DLE EOT 2: offline cause status
+	CoverOpen       bool // Roll paper cover is open
+	FeedingByButton bool // Paper is being fed by the paper feed button
+	PaperEndStop    bool // Printing stopped because of a paper end
+	ErrorOccurred   bool // An error has occurred
+
+	// DLE EOT 3: error cause status
+	RecoverableError     bool // A recoverable (mechanical) error has occurred
+	CutterError          bool // An autocutter error has occurred
+	UnrecoverableError   bool // An unrecoverable error has occurred
+	AutoRecoverableError bool // An automatically recoverable error has occurred
+
+	// DLE EOT 4: roll paper sensor status
+	PaperNearEnd bool // Roll paper near-end sensor detected a near-end
+	PaperEnd     bool // Roll paper end sensor detected no paper
+}
+
+// Ready reports whether the printer can print right now
+func (s Status) Ready() bool {
+	return !s.Offline && !s.CoverOpen && !s.PaperEnd && !s.ErrorOccurred &&
+		!s.CutterError && !s.UnrecoverableError && !s.AutoRecoverableError && !s.RecoverableError
+}
+
+// Ink is the decoded ink status reported by DLE EOT 7
+type Ink struct {
+	NearEnd bool // Ink near-end detected
+	End     bool // Ink end detected
+}
+
+// Peeler is the decoded peeler status reported by DLE EOT 8
+type Peeler struct {
+	PaperWaiting bool // Paper is waiting to be removed
+}
+
+// ============================================================================
+// Error Definitions
+// ============================================================================
+
+var (
+	// ErrStatusType indicates an invalid DLE EOT status type
+	ErrStatusType = errors.New("invalid status type (try 1-4)")
+	// ErrInkUnit indicates an invalid ink selection
+	ErrInkUnit = errors.New("invalid ink unit (try 1 or 2)")
+	// ErrStatusByte indicates a byte that is not a real-time status response
+	ErrStatusByte = errors.New("invalid real-time status byte")
+)
+
+// ============================================================================
+// Interface Definitions
+// ============================================================================
+
+// Interface compliance check
+var _ Capability = (*Commands)(nil)
+
+// Capability defines the interface for real-time commands
+type Capability interface {
+	TransmitStatus(n StatusType) ([]byte, error)
+	TransmitInkStatus(a InkUnit) ([]byte, error)
+	TransmitPeelerStatus() []byte
+}
+
+// ============================================================================
+// Main Implementation
+// ============================================================================
+
+// Commands implements the Capability interface for real-time commands
+type Commands struct{}
+
+// NewCommands creates a new Commands instance
+func NewCommands() *Commands {
+	return &Commands{}
+}
+
+// ============================================================================
+// Validation Helper Functions
+// ============================================================================
+
+// ValidateStatusType validates if the basic status type is valid
+func ValidateStatusType(n StatusType) error {
+	if n < PrinterStatus || n > PaperSensorStatus {
+		return fmt.Errorf("%w: %d", ErrStatusType, n)
+	}
+	return nil
+}
+
+// ValidateInkUnit validates if the ink unit is valid
+func ValidateInkUnit(a InkUnit) error {
+	switch a {
+	case InkA, InkB:
+		return nil
+	default:
+		return fmt.Errorf("%w: %d", ErrInkUnit, a)
+	}
+}
+
+// IsStatusByte reports whether b has the fixed bits of a real-time status response
+func IsStatusByte(b byte) bool {
+	return b&fixedMask == fixedValue
+}
+
+// ============================================================================
+// Response Functions
+// ============================================================================
+
+// Apply decodes the response b to DLE EOT n into s. Only the fields reported
+// by n are modified.
+func (s *Status) Apply(n StatusType, b byte) error {
+	if err := ValidateStatusType(n); err != nil {
+		return err
+	}
+	if !IsStatusByte(b) {
+		return fmt.Errorf("%w: %#02x", ErrStatusByte, b)
+	}
+
+	switch n {
+	case PrinterStatus:
+		s.DrawerPinHigh = b&bitDrawerPin != 0
+		s.Offline = b&bitOffline != 0
+		s.WaitingOnline = b&bitWaitOnline != 0
+		s.FeedButtonPressed = b&bitFeedButton != 0
+	case OfflineCauseStatus:
+		s.CoverOpen = b&bitCoverOpen != 0
+		s.FeedingByButton = b&bitFeedByButton != 0
+		s.PaperEndStop = b&bitPaperEndStop != 0
+		s.ErrorOccurred = b&bitErrorOccurred != 0
+	case ErrorCauseStatus:
+		s.RecoverableError = b&bitRecoverableError != 0
+		s.CutterError = b&bitCutterError != 0
+		s.UnrecoverableError = b&bitUnrecoverableError != 0
+		s.AutoRecoverableError = b&bitAutoRecoverableError != 0
+	case PaperSensorStatus:
+		s.PaperNearEnd = b&bitsNearEnd == bitsNearEnd
+		s.PaperEnd = b&bitsEnd == bitsEnd
+	}
+	return nil
+}
+
+// ParseStatus decodes the responses to DLE EOT 1, 2, 3 and 4, in that order
+func ParseStatus(resp []byte) (Status, error) {
+	var s Status
+	if len(resp) != int(PaperSensorStatus) {
+		return s, fmt.Errorf("%w: want %d status bytes, got %d", ErrStatusByte, PaperSensorStatus, len(resp))
+	}
+	for i, b := range resp {
+		if err := s.Apply(StatusType(i+1), b); err != nil {
+			return Status{}, err
+		}
+	}
+	return s, nil
+}
+
+// ParseInkStatus decodes the response to DLE EOT 7
+func ParseInkStatus(b byte) (Ink, error) {
+	if !IsStatusByte(b) {
+		return Ink{}, fmt.Errorf("%w: %#02x", ErrStatusByte, b)
+	}
+	return Ink{
+		NearEnd: b&bitsNearEnd == bitsNearEnd,
+		End:     b&bitsEnd == bitsEnd,
+	}, nil
+}
+
+// ParsePeelerStatus decodes the response to DLE EOT 8
+func ParsePeelerStatus(b byte) (Peeler, error) {
+	if !IsStatusByte(b) {
+		return Peeler{}, fmt.Errorf("%w: %#02x", ErrStatusByte, b)
+	}
+	return Peeler{PaperWaiting: b&bitPaperWaiting != 0}, nil
+}
@@ -0,0 +1,114 @@
+package realtime
+
+import (
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+)
+
+// TransmitStatus transmits the selected printer status in real time.
+//
+// Format:
+//
+//	ASCII:   DLE EOT n
+//	Hex:     0x10 0x04 n
+//	Decimal: 16 4 n
+//
+// Range:
+//
+//	n = 1–4
+//
+// Default:
+//
+//	None
+//
+// Parameters:
+//
+//	n: Specifies the status to be transmitted:
+//	   1 -> Printer status
+//	   2 -> Offline cause status
+//	   3 -> Error cause status
+//	   4 -> Roll paper sensor status
+//
+// Notes:
+//   - The printer transmits one status byte immediately after receiving the command
+//   - Bits 1 and 4 of the response are always 1; bits 0 and 7 are always 0
+//   - Executed even when the printer is offline, the receive buffer is full, or an error occurred
+//   - Do not insert this command inside the parameters of another command (e.g. bit image data)
+//   - When Automatic Status Back (GS a) is enabled, check bits 0, 1, 4 and 7 to tell
+//     real-time responses apart from ASB packets
+//
+// Errors:
+//
+//	Returns ErrStatusType if n is not between 1 and 4.
+func (c *Commands) TransmitStatus(n StatusType) ([]byte, error) {
+	if err := ValidateStatusType(n); err != nil {
+		return nil, err
+	}
+	return []byte{common.DLE, common.EOT, byte(n)}, nil
+}
+
+// TransmitInkStatus transmits the status of the selected ink in real time.
+//
+// Format:
+//
+//	ASCII:   DLE EOT 7 a
+//	Hex:     0x10 0x04 0x07 a
+//	Decimal: 16 4 7 a
+//
+// Range:
+//
+//	a = 1, 2
+//
+// Default:
+//
+//	None
+//
+// Parameters:
+//
+//	a: Specifies the ink:
+//	   1 -> Ink A (black or single color)
+//	   2 -> Ink B (second color)
+//
+// Notes:
+//   - Supported only by models with ink ribbons or cartridges
+//   - Bits 2 and 3 report an ink near-end, bits 5 and 6 report an ink end
+//
+// Errors:
+//
+//	Returns ErrInkUnit if a is not 1 or 2.
+func (c *Commands) TransmitInkStatus(a InkUnit) ([]byte, error) {
+	if err := ValidateInkUnit(a); err != nil {
+		return nil, err
+	}
+	return []byte{common.DLE, common.EOT, byte(InkStatus), byte(a)}, nil
+}
+
+// TransmitPeelerStatus transmits the peeler status in real time.
+//
+// Format:
+//
+//	ASCII:   DLE EOT 8 3
+//	Hex:     0x10 0x04 0x08 0x03
+//	Decimal: 16 4 8 3
+//
+// Range:
+//
+//	None
+//
+// Default:
+//
+//	None
+//
+// Parameters:
+//
+//	None
+//
+// Notes:
+//   - Supported only by label models with a peeler
+//   - Bit 2 of the response is 1 while paper is waiting to be removed
+//
+// Errors:
+//
+//	This function is safe and does not return errors.
+func (c *Commands) TransmitPeelerStatus() []byte {
+	return []byte{common.DLE, common.EOT, byte(PeelerStatus), peelerFunction}
+}
@@ -0,0 +1,199 @@
+package realtime_test
+
+import (
+	"testing"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+	"github.com/adcondev/pos-printer/pkg/commands/realtime"
+)
+
+// ============================================================================
+// Command Tests
+// ============================================================================
+
+func TestCommands_TransmitStatus(t *testing.T) {
+	cmd := realtime.NewCommands()
+
+	tests := []struct {
+		name    string
+		n       realtime.StatusType
+		want    []byte
+		wantErr error
+	}{
+		{"printer status", realtime.PrinterStatus, []byte{common.DLE, common.EOT, 1}, nil},
+		{"offline cause", realtime.OfflineCauseStatus, []byte{common.DLE, common.EOT, 2}, nil},
+		{"error cause", realtime.ErrorCauseStatus, []byte{common.DLE, common.EOT, 3}, nil},
+		{"paper sensor", realtime.PaperSensorStatus, []byte{common.DLE, common.EOT, 4}, nil},
+		{"zero", 0, nil, realtime.ErrStatusType},
+		{"extended ink form", realtime.InkStatus, nil, realtime.ErrStatusType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmd.TransmitStatus(tt.n)
+			if !testutils.AssertErrorOccurred(t, err, tt.wantErr != nil, "TransmitStatus") {
+				return
+			}
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			testutils.AssertBytes(t, got, tt.want, "TransmitStatus(%d)", tt.n)
+		})
+	}
+}
+
+func TestCommands_TransmitInkStatus(t *testing.T) {
+	cmd := realtime.NewCommands()
+
+	got, err := cmd.TransmitInkStatus(realtime.InkB)
+	if err != nil {
+		t.Fatalf("TransmitInkStatus(InkB) error = %v", err)
+	}
+	testutils.AssertBytes(t, got, []byte{common.DLE, common.EOT, 7, 2}, "TransmitInkStatus(InkB)")
+
+	_, err = cmd.TransmitInkStatus(3)
+	testutils.AssertError(t, err, realtime.ErrInkUnit)
+}
+
+func TestCommands_TransmitPeelerStatus(t *testing.T) {
+	cmd := realtime.NewCommands()
+	testutils.AssertBytes(t, cmd.TransmitPeelerStatus(), []byte{common.DLE, common.EOT, 8, 3}, "TransmitPeelerStatus()")
+}
+
+// ============================================================================
+// Response Decoding Tests
+// ============================================================================
+
+func TestParseStatus(t *testing.T) {
+	// Responses recorded from DLE EOT 1, 2, 3, 4 in that order
+	tests := []struct {
+		name    string
+		resp    []byte
+		want    realtime.Status
+		ready   bool
+		wantErr error
+	}{
+		{
+			name:  "idle and ready",
+			resp:  []byte{0x16, 0x12, 0x12, 0x12},
+			want:  realtime.Status{DrawerPinHigh: true},
+			ready: true,
+		},
+		{
+			name: "cover open",
+			resp: []byte{0x1E, 0x16, 0x12, 0x12},
+			want: realtime.Status{DrawerPinHigh: true, Offline: true, CoverOpen: true},
+		},
+		{
+			name: "out of paper",
+			resp: []byte{0x1A, 0x32, 0x12, 0x72},
+			want: realtime.Status{Offline: true, PaperEndStop: true, PaperEnd: true},
+		},
+		{
+			name:  "paper near end",
+			resp:  []byte{0x12, 0x12, 0x12, 0x1E},
+			want:  realtime.Status{PaperNearEnd: true},
+			ready: true,
+		},
+		{
+			name: "feed button held",
+			resp: []byte{0x5A, 0x1A, 0x12, 0x12},
+			want: realtime.Status{Offline: true, FeedButtonPressed: true, FeedingByButton: true},
+		},
+		{
+			name: "cutter jam",
+			resp: []byte{0x1A, 0x52, 0x1A, 0x12},
+			want: realtime.Status{Offline: true, ErrorOccurred: true, CutterError: true},
+		},
+		{
+			name: "unrecoverable error",
+			resp: []byte{0x1A, 0x52, 0x32, 0x12},
+			want: realtime.Status{Offline: true, ErrorOccurred: true, UnrecoverableError: true},
+		},
+		{
+			name: "head overheated",
+			resp: []byte{0x3A, 0x52, 0x52, 0x12},
+			want: realtime.Status{Offline: true, WaitingOnline: true, ErrorOccurred: true, AutoRecoverableError: true},
+		},
+		{
+			name:    "ASB packet instead of status",
+			resp:    []byte{0x10, 0x12, 0x12, 0x12},
+			wantErr: realtime.ErrStatusByte,
+		},
+		{
+			name:    "missing bytes",
+			resp:    []byte{0x16, 0x12},
+			wantErr: realtime.ErrStatusByte,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := realtime.ParseStatus(tt.resp)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStatus(% X) error = %v", tt.resp, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseStatus(% X) = %+v, want %+v", tt.resp, got, tt.want)
+			}
+			if got.Ready() != tt.ready {
+				t.Errorf("Ready() = %v, want %v", got.Ready(), tt.ready)
+			}
+		})
+	}
+}
+
+func TestStatus_Apply_KeepsOtherFields(t *testing.T) {
+	s := realtime.Status{PaperEnd: true}
+	if err := s.Apply(realtime.PrinterStatus, 0x1A); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if !s.Offline || !s.PaperEnd {
+		t.Errorf("Apply() = %+v, want Offline and PaperEnd", s)
+	}
+
+	testutils.AssertError(t, s.Apply(realtime.InkStatus, 0x12), realtime.ErrStatusType)
+}
+
+func TestParseInkStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		b    byte
+		want realtime.Ink
+	}{
+		{"ink adequate", 0x12, realtime.Ink{}},
+		{"ink near end", 0x1E, realtime.Ink{NearEnd: true}},
+		{"ink end", 0x7E, realtime.Ink{NearEnd: true, End: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := realtime.ParseInkStatus(tt.b)
+			if err != nil {
+				t.Fatalf("ParseInkStatus(%#02x) error = %v", tt.b, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseInkStatus(%#02x) = %+v, want %+v", tt.b, got, tt.want)
+			}
+		})
+	}
+
+	_, err := realtime.ParseInkStatus(0x00)
+	testutils.AssertError(t, err, realtime.ErrStatusByte)
+}
+
+func TestParsePeelerStatus(t *testing.T) {
+	got, err := realtime.ParsePeelerStatus(0x16)
+	if err != nil {
+		t.Fatalf("ParsePeelerStatus() error = %v", err)
+	}
+	if !got.PaperWaiting {
+		t.Errorf("ParsePeelerStatus(0x16) = %+v, want PaperWaiting", got)
+	}
+}
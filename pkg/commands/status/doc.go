@@ -0,0 +1,7 @@
+// Package status implements ESC/POS commands for printer status transmission.
+//
+// ESC/POS is the command system used by thermal receipt printers. This package
+// covers Automatic Status Back (ASB), in which the printer sends a 4-byte status
+// frame on its own whenever a monitored condition changes, and the decoding of
+// those frames out of a byte stream that also carries other printer responses.
+package status
@@ -0,0 +1,243 @@
+package status
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+	"github.com/adcondev/pos-printer/pkg/commands/realtime"
+)
+
+// ============================================================================
+// Context
+// ============================================================================
+// This package implements ESC/POS commands for printer status transmission.
+// ESC/POS is the command system used by thermal receipt printers. With
+// Automatic Status Back enabled the printer reports status changes by itself,
+// so hosts do not need to poll with real-time commands.
+
+// ============================================================================
+// Constant and Var Definitions
+// ============================================================================
+
+// ASBFlag selects the status changes that trigger an Automatic Status Back frame
+type ASBFlag byte
+
+const (
+	// ASBDisabled disables Automatic Status Back
+	ASBDisabled ASBFlag = 0x00
+	// ASBDrawer enables ASB on drawer kick-out connector pin 3 changes
+	ASBDrawer ASBFlag = 0x01
+	// ASBOnline enables ASB on online/offline changes
+	ASBOnline ASBFlag = 0x02
+	// ASBError enables ASB on error changes
+	ASBError ASBFlag = 0x04
+	// ASBPaperSensor enables ASB on roll paper sensor changes
+	ASBPaperSensor ASBFlag = 0x08
+	// ASBAll enables every ASB status
+	ASBAll = ASBDrawer | ASBOnline | ASBError | ASBPaperSensor
+)
+
+// ASBFrameLen is the length of an Automatic Status Back frame
+const ASBFrameLen = 4
+
+// ASB frame layout: the first byte has bits 0, 1 and 7 fixed to 0 and bit 4 fixed to 1,
+// the other three bytes have bits 4 and 7 fixed to 0
+const (
+	firstMask  byte = 0x93
+	firstValue byte = 0x10
+	restMask   byte = 0x90
+)
+
+// ASB status bits
+const (
+	// First byte
+	bitDrawerPin    byte = 0x04
+	bitOffline      byte = 0x08
+	bitCoverOpen    byte = 0x20
+	bitFeedByButton byte = 0x40
+
+	// Second byte
+	bitRecoverableError     byte = 0x04
+	bitCutterError          byte = 0x08
+	bitUnrecoverableError   byte = 0x20
+	bitAutoRecoverableError byte = 0x40
+
+	// Third byte (both bits of each pair are set together)
+	bitsNearEnd byte = 0x03
+	bitsEnd     byte = 0x0C
+)
+
+// ============================================================================
+// Error Definitions
+// ============================================================================
+
+var (
+	// ErrASBFlag indicates ASB flags outside the defined bits
+	ErrASBFlag = errors.New("invalid ASB flags (try 0-15)")
+	// ErrASBFrame indicates bytes that are not an Automatic Status Back frame
+	ErrASBFrame = errors.New("invalid automatic status back frame")
+)
+
+// ============================================================================
+// Interface Definitions
+// ============================================================================
+
+// Interface compliance check
+var _ Capability = (*Commands)(nil)
+
+// Capability defines the interface for status transmission commands
+type Capability interface {
+	EnableAutomaticStatusBack(n ASBFlag) ([]byte, error)
+}
+
+// ============================================================================
+// Main Implementation
+// ============================================================================
+
+// Commands implements the Capability interface for status transmission
+type Commands struct{}
+
+// NewCommands creates a new Commands instance
+func NewCommands() *Commands {
+	return &Commands{}
+}
+
+// ============================================================================
+// Validation Helper Functions
+// ============================================================================
+
+// ValidateASBFlag validates that only defined ASB bits are set
+func ValidateASBFlag(n ASBFlag) error {
+	if n&^ASBAll != 0 {
+		return fmt.Errorf("%w: %#02x", ErrASBFlag, byte(n))
+	}
+	return nil
+}
+
+// IsASBHeader reports whether b can be the first byte of an ASB frame
+func IsASBHeader(b byte) bool {
+	return b&firstMask == firstValue
+}
+
+// IsASBFrame reports whether frame is a well-formed ASB frame
+func IsASBFrame(frame []byte) bool {
+	if len(frame) != ASBFrameLen || !IsASBHeader(frame[0]) {
+		return false
+	}
+	for _, b := range frame[1:] {
+		if b&restMask != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ============================================================================
+// Response Functions
+// ============================================================================
+
+// ParseASB decodes an Automatic Status Back frame. The result uses the same fields
+// as the real-time status; ErrorOccurred is set when any error bit is set.
+func ParseASB(frame []byte) (realtime.Status, error) {
+	if !IsASBFrame(frame) {
+		return realtime.Status{}, fmt.Errorf("%w: % X", ErrASBFrame, frame)
+	}
+
+	s := realtime.Status{
+		DrawerPinHigh:   frame[0]&bitDrawerPin != 0,
+		Offline:         frame[0]&bitOffline != 0,
+		CoverOpen:       frame[0]&bitCoverOpen != 0,
+		FeedingByButton: frame[0]&bitFeedByButton != 0,
+
+		RecoverableError:     frame[1]&bitRecoverableError != 0,
+		CutterError:          frame[1]&bitCutterError != 0,
+		UnrecoverableError:   frame[1]&bitUnrecoverableError != 0,
+		AutoRecoverableError: frame[1]&bitAutoRecoverableError != 0,
+
+		PaperNearEnd: frame[2]&bitsNearEnd == bitsNearEnd,
+		PaperEnd:     frame[2]&bitsEnd == bitsEnd,
+	}
+	s.ErrorOccurred = s.RecoverableError || s.CutterError || s.UnrecoverableError || s.AutoRecoverableError
+	return s, nil
+}
+
+// ============================================================================
+// Stream Demultiplexing
+// ============================================================================
+
+// Demux separates ASB frames from the other responses found in the byte stream
+// received from the printer. "Header to NUL" blocks are passed through intact,
+// so their contents are never mistaken for ASB frames.
+//
+// The zero value is ready to use. Demux is not safe for concurrent use.
+type Demux struct {
+	pending []byte
+	inBlock bool
+}
+
+// Write consumes data and returns the complete ASB frames found and the remaining
+// response bytes, in arrival order within each result. Bytes that may start an ASB
+// frame are held back until the frame is complete or Flush is called.
+func (d *Demux) Write(data []byte) (frames [][]byte, other []byte) {
+	d.pending = append(d.pending, data...)
+
+	i := 0
+	for i < len(d.pending) {
+		b := d.pending[i]
+
+		if d.inBlock {
+			other = append(other, b)
+			d.inBlock = b != common.NUL
+			i++
+			continue
+		}
+
+		switch {
+		case b == common.BlockHeader || b == common.IDBlockHeader:
+			other = append(other, b)
+			d.inBlock = true
+			i++
+		case IsASBHeader(b):
+			if len(d.pending)-i < ASBFrameLen {
+				// Wait for the rest of the candidate frame
+				d.pending = append(d.pending[:0], d.pending[i:]...)
+				return frames, other
+			}
+			candidate := d.pending[i : i+ASBFrameLen]
+			if IsASBFrame(candidate) {
+				frames = append(frames, append([]byte(nil), candidate...))
+				i += ASBFrameLen
+			} else {
+				other = append(other, b)
+				i++
+			}
+		default:
+			other = append(other, b)
+			i++
+		}
+	}
+
+	d.pending = d.pending[:0]
+	return frames, other
+}
+
+// Pending reports whether bytes are held back waiting to complete an ASB frame
+func (d *Demux) Pending() bool {
+	return len(d.pending) > 0
+}
+
+// Flush gives up on an incomplete ASB frame and returns the held bytes as responses
+func (d *Demux) Flush() []byte {
+	held := d.pending
+	d.pending = nil
+
+	for _, b := range held {
+		if d.inBlock {
+			d.inBlock = b != common.NUL
+		} else {
+			d.inBlock = b == common.BlockHeader || b == common.IDBlockHeader
+		}
+	}
+	return held
+}
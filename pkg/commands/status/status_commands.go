@@ -0,0 +1,48 @@
+package status
+
+import (
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+)
+
+// EnableAutomaticStatusBack enables or disables Automatic Status Back (ASB).
+//
+// Format:
+//
+//	ASCII:   GS a n
+//	Hex:     0x1D 0x61 n
+//	Decimal: 29 97 n
+//
+// Range:
+//
+//	n = 0–15
+//
+// Default:
+//
+//	Depends on the printer model (usually n = 0)
+//
+// Parameters:
+//
+//	n: Selects the status changes that trigger a transmission (OR the flags):
+//	   0x01 -> Drawer kick-out connector pin 3 status
+//	   0x02 -> Online/offline status
+//	   0x04 -> Error status
+//	   0x08 -> Roll paper sensor status
+//	   0x00 -> ASB disabled
+//
+// Notes:
+//   - When any enabled status is on, the printer transmits the first 4-byte status frame immediately
+//   - Afterwards a frame is transmitted every time an enabled status changes
+//   - Each frame reports every status, including the ones not enabled
+//   - The first byte of a frame has bits 0 and 1 fixed to 0, which tells it apart from
+//     real-time status responses (DLE EOT), where bit 1 is fixed to 1
+//   - Settings remain in effect until ESC @ is executed, the printer is reset, or power is turned off
+//
+// Errors:
+//
+//	Returns ErrASBFlag if n sets bits other than 0–3.
+func (c *Commands) EnableAutomaticStatusBack(n ASBFlag) ([]byte, error) {
+	if err := ValidateASBFlag(n); err != nil {
+		return nil, err
+	}
+	return []byte{common.GS, 'a', byte(n)}, nil
+}
@@ -0,0 +1,211 @@
+package status_test
+
+import (
+	"testing"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+	"github.com/adcondev/pos-printer/pkg/commands/realtime"
+	"github.com/adcondev/pos-printer/pkg/commands/status"
+)
+
+// ============================================================================
+// Command Tests
+// ============================================================================
+
+func TestCommands_EnableAutomaticStatusBack(t *testing.T) {
+	cmd := status.NewCommands()
+
+	tests := []struct {
+		name    string
+		n       status.ASBFlag
+		want    []byte
+		wantErr error
+	}{
+		{"disabled", status.ASBDisabled, []byte{common.GS, 'a', 0x00}, nil},
+		{"all", status.ASBAll, []byte{common.GS, 'a', 0x0F}, nil},
+		{"drawer and paper", status.ASBDrawer | status.ASBPaperSensor, []byte{common.GS, 'a', 0x09}, nil},
+		{"undefined bit", 0x10, nil, status.ErrASBFlag},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cmd.EnableAutomaticStatusBack(tt.n)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("EnableAutomaticStatusBack(%#02x) error = %v", byte(tt.n), err)
+			}
+			testutils.AssertBytes(t, got, tt.want, "EnableAutomaticStatusBack(%#02x)", byte(tt.n))
+		})
+	}
+}
+
+// ============================================================================
+// Response Decoding Tests
+// ============================================================================
+
+func TestParseASB(t *testing.T) {
+	tests := []struct {
+		name    string
+		frame   []byte
+		want    realtime.Status
+		wantErr error
+	}{
+		{
+			name:  "all normal",
+			frame: []byte{0x10, 0x00, 0x00, 0x00},
+			want:  realtime.Status{},
+		},
+		{
+			name:  "cover open",
+			frame: []byte{0x38, 0x00, 0x00, 0x00},
+			want:  realtime.Status{Offline: true, CoverOpen: true},
+		},
+		{
+			name:  "drawer pin high",
+			frame: []byte{0x14, 0x00, 0x00, 0x00},
+			want:  realtime.Status{DrawerPinHigh: true},
+		},
+		{
+			name:  "paper near end",
+			frame: []byte{0x10, 0x00, 0x03, 0x00},
+			want:  realtime.Status{PaperNearEnd: true},
+		},
+		{
+			name:  "paper end",
+			frame: []byte{0x18, 0x00, 0x0F, 0x00},
+			want:  realtime.Status{Offline: true, PaperNearEnd: true, PaperEnd: true},
+		},
+		{
+			name:  "autocutter error",
+			frame: []byte{0x18, 0x08, 0x00, 0x00},
+			want:  realtime.Status{Offline: true, CutterError: true, ErrorOccurred: true},
+		},
+		{
+			name:    "real-time status byte",
+			frame:   []byte{0x12, 0x00, 0x00, 0x00},
+			wantErr: status.ErrASBFrame,
+		},
+		{
+			name:    "short frame",
+			frame:   []byte{0x10, 0x00},
+			wantErr: status.ErrASBFrame,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := status.ParseASB(tt.frame)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseASB(% X) error = %v", tt.frame, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseASB(% X) = %+v, want %+v", tt.frame, got, tt.want)
+			}
+		})
+	}
+}
+
+// ============================================================================
+// Stream Demultiplexing Tests
+// ============================================================================
+
+func TestDemux_Write(t *testing.T) {
+	asb := []byte{0x38, 0x00, 0x00, 0x00}
+	rtStatus := []byte{0x16}
+	// GS I 66 manufacturer block; 'P' (0x50) would look like an ASB header outside a block
+	idBlock := []byte{common.IDBlockHeader, 'E', 'P', 'S', 'O', 'N', common.NUL}
+
+	tests := []struct {
+		name       string
+		chunks     [][]byte
+		wantFrames int
+		wantOther  []byte
+		pending    bool
+	}{
+		{
+			name:       "single frame",
+			chunks:     [][]byte{asb},
+			wantFrames: 1,
+		},
+		{
+			name:      "real-time status only",
+			chunks:    [][]byte{rtStatus},
+			wantOther: rtStatus,
+		},
+		{
+			name:       "frame split across reads",
+			chunks:     [][]byte{asb[:1], asb[1:3], asb[3:]},
+			wantFrames: 1,
+		},
+		{
+			name:       "frame between responses",
+			chunks:     [][]byte{append(append(append([]byte{}, rtStatus...), asb...), rtStatus...)},
+			wantFrames: 1,
+			wantOther:  []byte{0x16, 0x16},
+		},
+		{
+			name:       "block is never split",
+			chunks:     [][]byte{idBlock, asb},
+			wantFrames: 1,
+			wantOther:  idBlock,
+		},
+		{
+			name:       "frame before block split across reads",
+			chunks:     [][]byte{asb[:2], append(asb[2:], idBlock[:3]...), idBlock[3:]},
+			wantFrames: 1,
+			wantOther:  idBlock,
+		},
+		{
+			name:      "incomplete candidate is held",
+			chunks:    [][]byte{{0x16, 0x30}},
+			wantOther: []byte{0x16},
+			pending:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d status.Demux
+			var frames [][]byte
+			var other []byte
+			for _, chunk := range tt.chunks {
+				f, o := d.Write(chunk)
+				frames = append(frames, f...)
+				other = append(other, o...)
+			}
+
+			if len(frames) != tt.wantFrames {
+				t.Fatalf("frames = %d, want %d", len(frames), tt.wantFrames)
+			}
+			for _, f := range frames {
+				testutils.AssertBytes(t, f, asb, "frame")
+			}
+			testutils.AssertBytes(t, other, tt.wantOther, "other")
+			if d.Pending() != tt.pending {
+				t.Errorf("Pending() = %v, want %v", d.Pending(), tt.pending)
+			}
+		})
+	}
+}
+
+func TestDemux_Flush(t *testing.T) {
+	var d status.Demux
+
+	_, other := d.Write([]byte{0x30, 0x31})
+	if len(other) != 0 || !d.Pending() {
+		t.Fatalf("Write() other = % X, pending = %v; want candidate held", other, d.Pending())
+	}
+
+	testutils.AssertBytes(t, d.Flush(), []byte{0x30, 0x31}, "Flush()")
+	if d.Pending() {
+		t.Error("Pending() = true after Flush")
+	}
+}
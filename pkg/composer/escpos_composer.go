@@ -7,36 +7,48 @@ import (
 
 	"github.com/adcondev/pos-printer/pkg/commands/barcode"
 	"github.com/adcondev/pos-printer/pkg/commands/bitimage"
+	"github.com/adcondev/pos-printer/pkg/commands/buzzer"
 	"github.com/adcondev/pos-printer/pkg/commands/character"
 	"github.com/adcondev/pos-printer/pkg/commands/common"
+	"github.com/adcondev/pos-printer/pkg/commands/drawer"
 	"github.com/adcondev/pos-printer/pkg/commands/linespacing"
 	"github.com/adcondev/pos-printer/pkg/commands/mechanismcontrol"
+	"github.com/adcondev/pos-printer/pkg/commands/pdf417"
 	"github.com/adcondev/pos-printer/pkg/commands/print"
+	"github.com/adcondev/pos-printer/pkg/commands/printerid"
 	"github.com/adcondev/pos-printer/pkg/commands/printposition"
+	"github.com/adcondev/pos-printer/pkg/commands/processid"
 	"github.com/adcondev/pos-printer/pkg/commands/qrcode"
+	"github.com/adcondev/pos-printer/pkg/commands/realtime"
+	"github.com/adcondev/pos-printer/pkg/commands/status"
 )
 
 // EscposProtocol implements the ESCPOS Commands
 type EscposProtocol struct {
 	Barcode          barcode.Capability
 	BitImage         bitimage.Capability
+	Buzzer           buzzer.Capability
 	Character        character.Capability
+	Drawer           drawer.Capability
 	LineSpacing      linespacing.Capability
 	MechanismControl mechanismcontrol.Capability
+	PDF417           pdf417.Capability
 	Print            print.Capability
+	PrinterID        printerid.Capability
 	PrintPosition    printposition.Capability
+	ProcessID        processid.Capability
 	QRCode           qrcode.Capability
+	RealTime         realtime.Capability
+	Status           status.Capability
 	// TODO: Implement other capabilities
 	// PrintingPaper    printingpaper.Capability
 	// PaperSensor      papersensor.Capability
 	// PanelButton      panelbutton.Capability
-	// Status           status.Capability
 	// MacroFunctions   macrofunctions.Capability
 	// Kanji 		    kanji.Capability
 	// Miscellaneous 	miscellaneous.Capability
 	// Customize 	    customize.Capability
 	// CounterPrinting  counterprinting.Capability
-	// PDF417           pdf417.Capability
 	// MaxiCode         maxicode.Capability
 	// DataBar          databar.Capability
 	// CompositeSym     compositesym.Capability
@@ -49,12 +61,19 @@ func NewEscpos() *EscposProtocol {
 	return &EscposProtocol{
 		Barcode:          barcode.NewCommands(),
 		BitImage:         bitimage.NewCommands(),
+		Buzzer:           buzzer.NewCommands(),
 		Character:        character.NewCommands(),
+		Drawer:           drawer.NewCommands(),
 		LineSpacing:      linespacing.NewCommands(),
 		MechanismControl: mechanismcontrol.NewCommands(),
+		PDF417:           pdf417.NewCommands(),
 		Print:            print.NewCommands(),
+		PrinterID:        printerid.NewCommands(),
 		PrintPosition:    printposition.NewCommands(),
+		ProcessID:        processid.NewCommands(),
 		QRCode:           qrcode.NewCommands(),
+		RealTime:         realtime.NewCommands(),
+		Status:           status.NewCommands(),
 	}
 }
 
@@ -1,13 +1,142 @@
 package connection
 
-import "io"
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+)
 
 // Connector define la interfaz para cualquier tipo de conexión con la impresora
 type Connector interface {
 	io.WriteCloser // Write([]byte) (int, error) y Close() error
 
 	// TODO: Agregar más métodos si necesitas:
-	// - Read([]byte) (int, error) para status
 	// - IsConnected() bool
 	// - Reset() error
 }
+
+// ReadWriteConnector is implemented by connectors that can also receive data from the printer,
+// such as status bytes, size information and transmission confirmations.
+//
+// Deadlines set through SetReadDeadline/SetWriteDeadline take precedence over the per-call
+// timeouts of the connector configuration; a zero time restores the configured timeouts.
+// The context variants return the context error when ctx is done before the I/O completes.
+type ReadWriteConnector interface {
+	Connector
+	io.Reader
+
+	ReadContext(ctx context.Context, buf []byte) (int, error)
+	WriteContext(ctx context.Context, data []byte) (int, error)
+	SetReadDeadline(t time.Time) error
+	SetWriteDeadline(t time.Time) error
+}
+
+// JobMarker is implemented by connectors that record job boundaries, such as spoolers that
+// submit each job separately. service.Printer calls BeginJob and EndJob around every job.
+type JobMarker interface {
+	BeginJob(name string) error
+	EndJob() error
+}
+
+// ErrNotBidirectional indicates that the connector cannot receive data from the printer
+var ErrNotBidirectional = errors.New("connector does not support reading from the printer")
+
+// readCapable is implemented by wrappers whose read support depends on the connector they wrap
+type readCapable interface {
+	CanRead() bool
+}
+
+// AsReadWriter returns conn as a ReadWriteConnector when it supports reading
+func AsReadWriter(conn Connector) (ReadWriteConnector, bool) {
+	rw, ok := conn.(ReadWriteConnector)
+	if !ok {
+		return nil, false
+	}
+	if w, isWrapper := conn.(readCapable); isWrapper && !w.CanRead() {
+		return nil, false
+	}
+	return rw, true
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+// deadline stores an absolute deadline that can be updated while I/O is in progress
+type deadline struct {
+	nanos atomic.Int64
+}
+
+func (d *deadline) set(t time.Time) {
+	if t.IsZero() {
+		d.nanos.Store(0)
+		return
+	}
+	d.nanos.Store(t.UnixNano())
+}
+
+func (d *deadline) get() time.Time {
+	n := d.nanos.Load()
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// pastDeadline is used to unblock pending I/O when a context is canceled
+var pastDeadline = time.Unix(1, 0)
+
+// effectiveDeadline picks the earliest of the context deadline, the explicit
+// deadline and now+timeout. A zero result means no deadline.
+func effectiveDeadline(ctx context.Context, explicit time.Time, timeout time.Duration) time.Time {
+	result := explicit
+	if result.IsZero() && timeout > 0 {
+		result = time.Now().Add(timeout)
+	}
+	if ctxDeadline, ok := ctx.Deadline(); ok && (result.IsZero() || ctxDeadline.Before(result)) {
+		result = ctxDeadline
+	}
+	return result
+}
+
+// doWithContext runs op after applying deadline through set. If ctx is canceled
+// while op is blocked, the deadline is moved to the past to interrupt it, and
+// the context error is returned instead of the resulting timeout.
+func doWithContext(ctx context.Context, until time.Time, set func(time.Time) error,
+	op func() (int, error)) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if err := set(until); err != nil {
+		return 0, err
+	}
+
+	stop := context.AfterFunc(ctx, func() { _ = set(pastDeadline) })
+	n, err := op()
+	stop()
+
+	if err != nil && ctx.Err() != nil {
+		return n, ctx.Err()
+	}
+	return n, err
+}
+
+// sleepContext pauses for d or until ctx is done, whichever happens first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// expired reports whether a non-zero deadline has passed
+func expired(until time.Time) bool {
+	return !until.IsZero() && time.Now().After(until)
+}
@@ -0,0 +1,309 @@
+package connection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StdoutPath selects standard output as the capture destination of a FileConnector
+const StdoutPath = "-"
+
+// dumpExt is the extension of the capture files written in a dump directory
+const dumpExt = ".bin"
+
+// Interface compliance checks
+var (
+	_ Connector = (*FileConnector)(nil)
+	_ JobMarker = (*FileConnector)(nil)
+)
+
+// FileConfig holds the destination of a FileConnector
+type FileConfig struct {
+	// Path is the capture file, truncated when opened; StdoutPath writes to standard output.
+	// Ignored when Dir is set.
+	Path string
+	// Dir, when set, stores every job in a new .bin dump inside this directory
+	Dir string
+	// MaxFiles bounds the dumps kept in Dir; the oldest ones are removed (0 keeps all)
+	MaxFiles int
+	// Sidecar writes a JSON file next to each capture (same name, .json extension) with the
+	// time of every Write call and the job boundaries. Not available for standard output.
+	Sidecar bool
+}
+
+// WriteRecord describes one Write call in a capture sidecar
+type WriteRecord struct {
+	Time   time.Time `json:"time"`
+	Offset int64     `json:"offset"`
+	Length int       `json:"length"`
+}
+
+// JobRecord describes one job in a capture sidecar
+type JobRecord struct {
+	Name   string     `json:"name,omitempty"`
+	Start  time.Time  `json:"start"`
+	End    *time.Time `json:"end,omitempty"` // nil while the job is open
+	Offset int64      `json:"offset"`
+	Length int64      `json:"length"`
+}
+
+// CaptureLog is the content of a capture sidecar
+type CaptureLog struct {
+	File    string        `json:"file"`
+	Created time.Time     `json:"created"`
+	Writes  []WriteRecord `json:"writes"`
+	Jobs    []JobRecord   `json:"jobs"`
+}
+
+// FileConnector records the ESC/POS stream to a file, to standard output or to a rotating
+// directory of .bin dumps, for debugging and reproducing printer issues
+type FileConnector struct {
+	config FileConfig
+
+	mu     sync.Mutex
+	out    io.Writer // nil until the capture is opened
+	file   *os.File  // nil for standard output
+	path   string
+	offset int64
+	log    *CaptureLog // nil when no sidecar is written
+	job    *JobRecord  // open job, nil between jobs
+	seq    int
+	closed bool
+}
+
+// NewFileConnector opens the capture described by config. In directory mode the first
+// dump is created on the first write or job.
+func NewFileConnector(config *FileConfig) (*FileConnector, error) {
+	if config == nil {
+		return nil, errors.New("file config cannot be nil")
+	}
+	if config.Dir == "" && config.Path == "" {
+		return nil, errors.New("file config needs a Path or a Dir")
+	}
+
+	c := &FileConnector{config: *config}
+	if c.config.Dir != "" {
+		if err := os.MkdirAll(c.config.Dir, 0o750); err != nil {
+			return nil, fmt.Errorf("create dump directory: %w", err)
+		}
+		return c, nil
+	}
+	if err := c.open(c.config.Path); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Path returns the current capture file, or StdoutPath
+func (c *FileConnector) Path() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.path
+}
+
+// Write appends data to the capture
+func (c *FileConnector) Write(data []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return 0, ErrConnectionClosed
+	}
+	if err := c.ensureOpen(); err != nil {
+		return 0, err
+	}
+
+	n, err := c.out.Write(data)
+	if c.log != nil && n > 0 {
+		c.log.Writes = append(c.log.Writes, WriteRecord{Time: time.Now(), Offset: c.offset, Length: n})
+	}
+	c.offset += int64(n)
+	if err != nil {
+		return n, fmt.Errorf("write capture: %w", err)
+	}
+	return n, nil
+}
+
+// BeginJob marks the start of a job, ending the previous one if still open.
+// In directory mode every job starts a new dump.
+func (c *FileConnector) BeginJob(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrConnectionClosed
+	}
+	if c.job != nil {
+		if err := c.endJob(); err != nil {
+			return err
+		}
+	}
+	if c.config.Dir != "" && c.offset > 0 {
+		if err := c.finish(); err != nil {
+			return err
+		}
+	}
+	if err := c.ensureOpen(); err != nil {
+		return err
+	}
+
+	c.job = &JobRecord{Name: name, Start: time.Now(), Offset: c.offset}
+	return nil
+}
+
+// EndJob marks the end of the open job and updates the sidecar.
+// In directory mode the dump is closed so the next job goes to a new file.
+func (c *FileConnector) EndJob() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrConnectionClosed
+	}
+	if c.job == nil {
+		return nil
+	}
+	if err := c.endJob(); err != nil {
+		return err
+	}
+	if c.config.Dir != "" {
+		return c.finish()
+	}
+	return nil
+}
+
+// Close ends the open job, writes the sidecar and closes the capture file
+func (c *FileConnector) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	var errs []error
+	if c.job != nil {
+		errs = append(errs, c.endJob())
+	}
+	errs = append(errs, c.finish())
+	return errors.Join(errs...)
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+// ensureOpen opens a new dump in directory mode when none is open. Must be called with mu held.
+func (c *FileConnector) ensureOpen() error {
+	if c.out != nil {
+		return nil
+	}
+	if c.config.Dir == "" {
+		return ErrConnectionClosed
+	}
+
+	c.seq++
+	name := fmt.Sprintf("%s-%04d%s", time.Now().Format("20060102-150405.000"), c.seq, dumpExt)
+	if err := c.open(filepath.Join(c.config.Dir, name)); err != nil {
+		return err
+	}
+	return c.prune()
+}
+
+// open starts a capture at path. Must be called with mu held (or before c is shared).
+func (c *FileConnector) open(path string) error {
+	c.path = path
+	c.offset = 0
+	c.log = nil
+
+	if path == StdoutPath {
+		c.out = os.Stdout
+		return nil
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("open capture file: %w", err)
+	}
+	c.file = f
+	c.out = f
+	if c.config.Sidecar {
+		c.log = &CaptureLog{File: filepath.Base(path), Created: time.Now(), Writes: []WriteRecord{}, Jobs: []JobRecord{}}
+	}
+	return nil
+}
+
+// endJob closes the open job and saves the sidecar. Must be called with mu held.
+func (c *FileConnector) endJob() error {
+	end := time.Now()
+	c.job.End = &end
+	c.job.Length = c.offset - c.job.Offset
+	if c.log != nil {
+		c.log.Jobs = append(c.log.Jobs, *c.job)
+	}
+	c.job = nil
+	return c.saveSidecar()
+}
+
+// finish saves the sidecar and closes the current capture. Must be called with mu held.
+func (c *FileConnector) finish() error {
+	if c.out == nil {
+		return nil
+	}
+	err := c.saveSidecar()
+	if c.file != nil {
+		err = errors.Join(err, c.file.Close())
+	}
+	c.out, c.file, c.log = nil, nil, nil
+	return err
+}
+
+// saveSidecar rewrites the sidecar of the current capture. Must be called with mu held.
+func (c *FileConnector) saveSidecar() error {
+	if c.log == nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(c.log, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode capture sidecar: %w", err)
+	}
+	if err := os.WriteFile(sidecarPath(c.path), data, 0o640); err != nil {
+		return fmt.Errorf("write capture sidecar: %w", err)
+	}
+	return nil
+}
+
+// prune removes the oldest dumps (and their sidecars) beyond MaxFiles. Must be called with mu held.
+func (c *FileConnector) prune() error {
+	if c.config.MaxFiles <= 0 {
+		return nil
+	}
+	dumps, err := filepath.Glob(filepath.Join(c.config.Dir, "*"+dumpExt))
+	if err != nil {
+		return err
+	}
+	// Dump names start with their creation time, so name order is age order
+	sort.Strings(dumps)
+	for len(dumps) > c.config.MaxFiles {
+		if err := os.Remove(dumps[0]); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove old dump: %w", err)
+		}
+		_ = os.Remove(sidecarPath(dumps[0]))
+		dumps = dumps[1:]
+	}
+	return nil
+}
+
+// sidecarPath returns the JSON sidecar path of a capture file
+func sidecarPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".json"
+}
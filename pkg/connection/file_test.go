@@ -0,0 +1,133 @@
+package connection_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adcondev/pos-printer/pkg/connection"
+)
+
+func readCaptureLog(t *testing.T, path string) connection.CaptureLog {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read sidecar: %v", err)
+	}
+	var log connection.CaptureLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("decode sidecar: %v", err)
+	}
+	return log
+}
+
+func TestFileConnector_FileWithSidecar(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.bin")
+
+	c, err := connection.NewFileConnector(&connection.FileConfig{Path: path, Sidecar: true})
+	if err != nil {
+		t.Fatalf("NewFileConnector() error = %v", err)
+	}
+
+	if _, err := c.Write([]byte{0x1B, '@'}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := c.BeginJob("order-17"); err != nil {
+		t.Fatalf("BeginJob() error = %v", err)
+	}
+	for _, chunk := range [][]byte{[]byte("Hello\n"), {0x1D, 'V', 0x41, 0x00}} {
+		if _, err := c.Write(chunk); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read capture: %v", err)
+	}
+	want := []byte{0x1B, '@', 'H', 'e', 'l', 'l', 'o', '\n', 0x1D, 'V', 0x41, 0x00}
+	if !bytes.Equal(got, want) {
+		t.Errorf("capture = %#v, want %#v", got, want)
+	}
+
+	log := readCaptureLog(t, filepath.Join(filepath.Dir(path), "capture.json"))
+	if log.File != "capture.bin" || len(log.Writes) != 3 {
+		t.Fatalf("sidecar = %+v, want 3 writes of capture.bin", log)
+	}
+	if log.Writes[2].Offset != 8 || log.Writes[2].Length != 4 {
+		t.Errorf("third write = %+v, want offset 8 length 4", log.Writes[2])
+	}
+	if len(log.Jobs) != 1 {
+		t.Fatalf("sidecar jobs = %+v, want 1 job (closed by Close)", log.Jobs)
+	}
+	job := log.Jobs[0]
+	if job.Name != "order-17" || job.Offset != 2 || job.Length != 10 || job.End == nil {
+		t.Errorf("job = %+v, want order-17 at offset 2, 10 bytes, ended", job)
+	}
+
+	if _, err := c.Write([]byte("late")); !errors.Is(err, connection.ErrConnectionClosed) {
+		t.Errorf("Write() after Close error = %v, want ErrConnectionClosed", err)
+	}
+}
+
+func TestFileConnector_RotatingDir(t *testing.T) {
+	dir := t.TempDir()
+
+	c, err := connection.NewFileConnector(&connection.FileConfig{Dir: dir, MaxFiles: 2, Sidecar: true})
+	if err != nil {
+		t.Fatalf("NewFileConnector() error = %v", err)
+	}
+	defer c.Close()
+
+	jobs := []string{"first", "second", "third"}
+	var last string
+	for _, name := range jobs {
+		if err := c.BeginJob(name); err != nil {
+			t.Fatalf("BeginJob(%s) error = %v", name, err)
+		}
+		if _, err := c.Write([]byte(name)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		last = c.Path()
+		if err := c.EndJob(); err != nil {
+			t.Fatalf("EndJob() error = %v", err)
+		}
+	}
+
+	dumps, _ := filepath.Glob(filepath.Join(dir, "*.bin"))
+	sidecars, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(dumps) != 2 || len(sidecars) != 2 {
+		t.Fatalf("dir holds %d dumps and %d sidecars, want 2 of each", len(dumps), len(sidecars))
+	}
+
+	got, err := os.ReadFile(last)
+	if err != nil {
+		t.Fatalf("read last dump: %v", err)
+	}
+	if string(got) != "third" {
+		t.Errorf("last dump = %q, want %q", got, "third")
+	}
+	log := readCaptureLog(t, last[:len(last)-len(".bin")]+".json")
+	if len(log.Jobs) != 1 || log.Jobs[0].Name != "third" {
+		t.Errorf("last sidecar jobs = %+v, want the third job", log.Jobs)
+	}
+}
+
+func TestNewFileConnector_Errors(t *testing.T) {
+	if _, err := connection.NewFileConnector(nil); err == nil {
+		t.Error("NewFileConnector(nil) expected error")
+	}
+	if _, err := connection.NewFileConnector(&connection.FileConfig{}); err == nil {
+		t.Error("NewFileConnector() without Path or Dir expected error")
+	}
+	bad := filepath.Join(t.TempDir(), "missing", "capture.bin")
+	if _, err := connection.NewFileConnector(&connection.FileConfig{Path: bad}); err == nil {
+		t.Error("NewFileConnector() in a missing directory expected error")
+	}
+}
@@ -0,0 +1,468 @@
+package connection
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultIPPPort is the IPP port used when the printer URI has none
+const DefaultIPPPort = "631"
+
+// Document formats that make CUPS pass ESC/POS bytes to the printer untouched
+const (
+	IPPFormatCUPSRaw     = "application/vnd.cups-raw"
+	IPPFormatOctetStream = "application/octet-stream"
+)
+
+// IPP defaults
+const (
+	DefaultIPPTimeout      = 30 * time.Second
+	DefaultIPPPollInterval = 500 * time.Millisecond
+)
+
+// ippSuccessMax is the last successful IPP status code (successful-ok-*)
+const ippSuccessMax uint16 = 0x00FF
+
+var (
+	// ErrIPPURL indicates a printer URI that is not ipp, ipps, http or https
+	ErrIPPURL = errors.New("invalid IPP printer URI")
+	// ErrIPPRequest indicates that the IPP server answered with an error status
+	ErrIPPRequest = errors.New("IPP request failed")
+	// ErrIPPJobCanceled indicates that the job was canceled before completing
+	ErrIPPJobCanceled = errors.New("IPP job canceled")
+	// ErrIPPJobAborted indicates that the system aborted the job
+	ErrIPPJobAborted = errors.New("IPP job aborted")
+)
+
+// ippStatusNames names the IPP status codes that submitting raw jobs commonly runs into
+var ippStatusNames = map[uint16]string{
+	0x0400: "client-error-bad-request",
+	0x0401: "client-error-forbidden",
+	0x0402: "client-error-not-authenticated",
+	0x0403: "client-error-not-authorized",
+	0x0406: "client-error-not-found",
+	0x040A: "client-error-document-format-not-supported",
+	0x0500: "server-error-internal-error",
+	0x0501: "server-error-operation-not-supported",
+	0x0502: "server-error-service-unavailable",
+	0x0504: "server-error-device-error",
+	0x0506: "server-error-not-accepting-jobs",
+	0x0507: "server-error-busy",
+}
+
+// Interface compliance checks
+var (
+	_ Connector = (*IPPConnector)(nil)
+	_ JobMarker = (*IPPConnector)(nil)
+)
+
+// JobState is the IPP job-state of a submitted job
+type JobState int
+
+// IPP job states (RFC 8011, section 5.3.7)
+const (
+	JobPending           JobState = 3
+	JobPendingHeld       JobState = 4
+	JobProcessing        JobState = 5
+	JobProcessingStopped JobState = 6
+	JobCanceled          JobState = 7
+	JobAborted           JobState = 8
+	JobCompleted         JobState = 9
+)
+
+// String returns the IPP keyword of the state
+func (s JobState) String() string {
+	switch s {
+	case JobPending:
+		return "pending"
+	case JobPendingHeld:
+		return "pending-held"
+	case JobProcessing:
+		return "processing"
+	case JobProcessingStopped:
+		return "processing-stopped"
+	case JobCanceled:
+		return "canceled"
+	case JobAborted:
+		return "aborted"
+	case JobCompleted:
+		return "completed"
+	default:
+		return fmt.Sprintf("JobState(%d)", int(s))
+	}
+}
+
+// Terminal reports whether the job has finished, successfully or not
+func (s JobState) Terminal() bool {
+	return s == JobCanceled || s == JobAborted || s == JobCompleted
+}
+
+// Err maps the canceled and aborted states to errors
+func (s JobState) Err() error {
+	switch s {
+	case JobCanceled:
+		return ErrIPPJobCanceled
+	case JobAborted:
+		return ErrIPPJobAborted
+	default:
+		return nil
+	}
+}
+
+// IPPJob is the state of a job reported by the IPP server
+type IPPJob struct {
+	ID      int
+	State   JobState
+	Reasons []string // job-state-reasons keywords
+}
+
+// IPPConfig holds the settings of an IPP connection
+type IPPConfig struct {
+	// URL is the printer URI, e.g. ipp://localhost:631/printers/receipt (ipp, ipps, http or https)
+	URL string
+	// DocumentFormat is the MIME type of the jobs (default: IPPFormatCUPSRaw)
+	DocumentFormat string
+	// User is the requesting-user-name of the jobs
+	User string
+	// JobName is the name of jobs sent without BeginJob
+	JobName string
+	// Timeout bounds each IPP request (0 = DefaultIPPTimeout, negative disables the deadline)
+	Timeout time.Duration
+	// WaitCompletion makes EndJob and Close poll the job until it reaches a terminal state
+	WaitCompletion bool
+	// PollInterval is the pause between Get-Job-Attributes requests while waiting
+	PollInterval time.Duration
+	// Client is the HTTP client used for the requests (default: a new http.Client)
+	Client *http.Client
+}
+
+// DefaultIPPConfig returns a configuration that submits raw jobs to the printer URI
+func DefaultIPPConfig(printerURL string) *IPPConfig {
+	return &IPPConfig{
+		URL:            printerURL,
+		DocumentFormat: IPPFormatCUPSRaw,
+		User:           DefaultLPDUser,
+		JobName:        DefaultLPDJobName,
+		Timeout:        DefaultIPPTimeout,
+		PollInterval:   DefaultIPPPollInterval,
+	}
+}
+
+// IPPConnector submits jobs to an IPP server such as CUPS. Writes are buffered and sent as
+// one Print-Job request when EndJob or Close is called.
+type IPPConnector struct {
+	config     IPPConfig
+	printerURI string // ipp:// form sent in the printer-uri attribute
+	endpoint   string // http:// form the requests are posted to
+	client     *http.Client
+	requestID  atomic.Uint32
+
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	jobName string
+	lastJob IPPJob
+	closed  bool
+}
+
+// NewIPPConnector validates config; no request is made until the first job is sent
+func NewIPPConnector(config *IPPConfig) (*IPPConnector, error) {
+	if config == nil {
+		return nil, errors.New("IPP config cannot be nil")
+	}
+	if config.URL == "" {
+		return nil, ErrEmptyAddress
+	}
+
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrIPPURL, err)
+	}
+	endpoint, printerURI := *u, *u
+	switch u.Scheme {
+	case "ipp", "ipps":
+		if u.Port() == "" {
+			endpoint.Host = net.JoinHostPort(u.Hostname(), DefaultIPPPort)
+			printerURI.Host = endpoint.Host
+		}
+		endpoint.Scheme = "http"
+		if u.Scheme == "ipps" {
+			endpoint.Scheme = "https"
+		}
+	case "http", "https":
+		printerURI.Scheme = "ipp"
+		if u.Scheme == "https" {
+			printerURI.Scheme = "ipps"
+		}
+	default:
+		return nil, fmt.Errorf("%w: scheme %q", ErrIPPURL, u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("%w: missing host in %q", ErrIPPURL, config.URL)
+	}
+
+	cfg := *config
+	if cfg.DocumentFormat == "" {
+		cfg.DocumentFormat = IPPFormatCUPSRaw
+	}
+	if cfg.User == "" {
+		cfg.User = DefaultLPDUser
+	}
+	if cfg.JobName == "" {
+		cfg.JobName = DefaultLPDJobName
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = DefaultIPPTimeout
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultIPPPollInterval
+	}
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	return &IPPConnector{
+		config:     cfg,
+		printerURI: printerURI.String(),
+		endpoint:   endpoint.String(),
+		client:     client,
+	}, nil
+}
+
+// Write buffers data for the current job
+func (c *IPPConnector) Write(data []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return 0, ErrConnectionClosed
+	}
+	return c.buf.Write(data)
+}
+
+// BeginJob sends the data buffered so far, if any, and names the next job
+func (c *IPPConnector) BeginJob(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrConnectionClosed
+	}
+	if err := c.flush(context.Background()); err != nil {
+		return err
+	}
+	c.jobName = name
+	return nil
+}
+
+// EndJob sends the buffered data as one job
+func (c *IPPConnector) EndJob() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrConnectionClosed
+	}
+	return c.flush(context.Background())
+}
+
+// Close sends the buffered data, if any, as the last job
+func (c *IPPConnector) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.flush(context.Background())
+}
+
+// LastJob returns the state of the last job sent, as reported by the server
+func (c *IPPConnector) LastJob() IPPJob {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastJob
+}
+
+// GetJob queries the state of job id with Get-Job-Attributes
+func (c *IPPConnector) GetJob(ctx context.Context, id int) (IPPJob, error) {
+	req := c.newRequest(ippOpGetJobAttributes)
+	req.groups[0].attrs = append(req.groups[0].attrs,
+		integerAttr(ippTagInteger, "job-id", int32(id)),
+		stringAttr(ippTagName, "requesting-user-name", c.config.User),
+		ippAttribute{tag: ippTagKeyword, name: "requested-attributes", values: [][]byte{
+			[]byte("job-id"), []byte("job-state"), []byte("job-state-reasons"),
+		}},
+	)
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return IPPJob{}, fmt.Errorf("get job %d: %w", id, err)
+	}
+	job := parseIPPJob(resp)
+	if job.ID == 0 {
+		job.ID = id
+	}
+	return job, nil
+}
+
+// WaitJob polls job id until it reaches a terminal state or ctx is done.
+// Canceled and aborted jobs are reported as ErrIPPJobCanceled and ErrIPPJobAborted.
+func (c *IPPConnector) WaitJob(ctx context.Context, id int) (IPPJob, error) {
+	for {
+		job, err := c.GetJob(ctx, id)
+		if err != nil {
+			return job, err
+		}
+		if job.State.Terminal() {
+			return job, jobError(job)
+		}
+		if err := sleepContext(ctx, c.config.PollInterval); err != nil {
+			return job, err
+		}
+	}
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+// flush sends the buffered data as a Print-Job request. The buffer is emptied even when
+// the request fails: the server may have accepted the job before the error, so sending it
+// again could print it twice. Must be called with mu held.
+func (c *IPPConnector) flush(ctx context.Context) error {
+	if c.buf.Len() == 0 {
+		return nil
+	}
+	defer func() {
+		c.buf.Reset()
+		c.jobName = ""
+	}()
+
+	name := c.jobName
+	if name == "" {
+		name = c.config.JobName
+	}
+	req := c.newRequest(ippOpPrintJob)
+	req.groups[0].attrs = append(req.groups[0].attrs,
+		stringAttr(ippTagName, "requesting-user-name", c.config.User),
+		stringAttr(ippTagName, "job-name", name),
+		stringAttr(ippTagMimeType, "document-format", c.config.DocumentFormat),
+	)
+	req.data = c.buf.Bytes()
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return fmt.Errorf("print job: %w", err)
+	}
+
+	job := parseIPPJob(resp)
+	c.lastJob = job
+	if err := jobError(job); err != nil {
+		return err
+	}
+	if !c.config.WaitCompletion || job.State.Terminal() {
+		return nil
+	}
+
+	job, err = c.WaitJob(ctx, job.ID)
+	c.lastJob = job
+	return err
+}
+
+// newRequest creates a request with the mandatory operation attributes
+func (c *IPPConnector) newRequest(op uint16) *ippMessage {
+	return &ippMessage{
+		code:      op,
+		requestID: c.requestID.Add(1),
+		groups: []ippGroup{{
+			tag: ippTagOperation,
+			attrs: []ippAttribute{
+				stringAttr(ippTagCharset, "attributes-charset", "utf-8"),
+				stringAttr(ippTagLanguage, "attributes-natural-language", "en"),
+				stringAttr(ippTagURI, "printer-uri", c.printerURI),
+			},
+		}},
+	}
+}
+
+// do posts req and decodes the response, turning error status codes into ErrIPPRequest
+func (c *IPPConnector) do(ctx context.Context, req *ippMessage) (*ippMessage, error) {
+	if c.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+		defer cancel()
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(req.encode()))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/ipp")
+
+	httpResp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: HTTP %s", ErrIPPRequest, httpResp.Status)
+	}
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read IPP response: %w", err)
+	}
+	resp, err := decodeIPP(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.code > ippSuccessMax {
+		name, ok := ippStatusNames[resp.code]
+		if !ok {
+			name = "error"
+		}
+		msg := ""
+		if a, ok := resp.attr(ippTagOperation, "status-message"); ok && len(a.values) > 0 {
+			msg = ": " + string(a.values[0])
+		}
+		return nil, fmt.Errorf("%w: %s (0x%04x)%s", ErrIPPRequest, name, resp.code, msg)
+	}
+	return resp, nil
+}
+
+// parseIPPJob extracts the job attributes from a response
+func parseIPPJob(resp *ippMessage) IPPJob {
+	var job IPPJob
+	if a, ok := resp.attr(ippTagJob, "job-id"); ok {
+		job.ID, _ = a.integer()
+	}
+	if a, ok := resp.attr(ippTagJob, "job-state"); ok {
+		state, _ := a.integer()
+		job.State = JobState(state)
+	}
+	if a, ok := resp.attr(ippTagJob, "job-state-reasons"); ok {
+		job.Reasons = a.strings()
+	}
+	return job
+}
+
+// jobError returns the error of a canceled or aborted job, with its reasons
+func jobError(job IPPJob) error {
+	err := job.State.Err()
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: job %d %v", err, job.ID, job.Reasons)
+}
@@ -0,0 +1,201 @@
+package connection
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// IPP message encoding (RFC 8010). Only the parts needed to submit jobs and read their
+// state are implemented: attribute groups with single or multiple values of any tag.
+
+// IPP version sent in requests (1.1)
+const (
+	ippVersionMajor byte = 1
+	ippVersionMinor byte = 1
+)
+
+// IPP operations
+const (
+	ippOpPrintJob         uint16 = 0x0002
+	ippOpGetJobAttributes uint16 = 0x0009
+)
+
+// IPP delimiter tags
+const (
+	ippTagOperation byte = 0x01
+	ippTagJob       byte = 0x02
+	ippTagEnd       byte = 0x03
+)
+
+// IPP value tags
+const (
+	ippTagInteger  byte = 0x21
+	ippTagName     byte = 0x42
+	ippTagKeyword  byte = 0x44
+	ippTagURI      byte = 0x45
+	ippTagCharset  byte = 0x47
+	ippTagLanguage byte = 0x48
+	ippTagMimeType byte = 0x49
+)
+
+// errIPPMessage indicates a truncated or malformed IPP message
+var errIPPMessage = errors.New("malformed IPP message")
+
+// ippAttribute is a named attribute with one or more values of the same tag
+type ippAttribute struct {
+	tag    byte
+	name   string
+	values [][]byte
+}
+
+// ippGroup is an attribute group introduced by a delimiter tag
+type ippGroup struct {
+	tag   byte
+	attrs []ippAttribute
+}
+
+// ippMessage is an IPP request or response; code is the operation or the status code
+type ippMessage struct {
+	code      uint16
+	requestID uint32
+	groups    []ippGroup
+	data      []byte
+}
+
+// stringAttr creates a single-valued textual attribute
+func stringAttr(tag byte, name, value string) ippAttribute {
+	return ippAttribute{tag: tag, name: name, values: [][]byte{[]byte(value)}}
+}
+
+// integerAttr creates a single-valued integer or enum attribute
+func integerAttr(tag byte, name string, value int32) ippAttribute {
+	v := make([]byte, 4)
+	binary.BigEndian.PutUint32(v, uint32(value))
+	return ippAttribute{tag: tag, name: name, values: [][]byte{v}}
+}
+
+// encode serializes the message, followed by its document data
+func (m *ippMessage) encode() []byte {
+	var b bytes.Buffer
+	b.Write([]byte{ippVersionMajor, ippVersionMinor})
+	_ = binary.Write(&b, binary.BigEndian, m.code)
+	_ = binary.Write(&b, binary.BigEndian, m.requestID)
+
+	for _, g := range m.groups {
+		b.WriteByte(g.tag)
+		for _, a := range g.attrs {
+			for i, v := range a.values {
+				name := a.name
+				if i > 0 {
+					name = "" // Additional values repeat the attribute with an empty name
+				}
+				b.WriteByte(a.tag)
+				_ = binary.Write(&b, binary.BigEndian, uint16(len(name)))
+				b.WriteString(name)
+				_ = binary.Write(&b, binary.BigEndian, uint16(len(v)))
+				b.Write(v)
+			}
+		}
+	}
+	b.WriteByte(ippTagEnd)
+	b.Write(m.data)
+	return b.Bytes()
+}
+
+// decodeIPP parses an IPP message; bytes after the end tag are returned as data
+func decodeIPP(buf []byte) (*ippMessage, error) {
+	if len(buf) < 8 {
+		return nil, fmt.Errorf("%w: %d byte header", errIPPMessage, len(buf))
+	}
+	m := &ippMessage{
+		code:      binary.BigEndian.Uint16(buf[2:4]),
+		requestID: binary.BigEndian.Uint32(buf[4:8]),
+	}
+
+	pos := 8
+	var group *ippGroup
+	for {
+		if pos >= len(buf) {
+			return nil, fmt.Errorf("%w: missing end tag", errIPPMessage)
+		}
+		tag := buf[pos]
+		pos++
+
+		if tag == ippTagEnd {
+			m.data = buf[pos:]
+			return m, nil
+		}
+		if tag < 0x10 {
+			m.groups = append(m.groups, ippGroup{tag: tag})
+			group = &m.groups[len(m.groups)-1]
+			continue
+		}
+		if group == nil {
+			return nil, fmt.Errorf("%w: attribute outside a group", errIPPMessage)
+		}
+
+		name, next, err := readIPPField(buf, pos)
+		if err != nil {
+			return nil, err
+		}
+		value, next, err := readIPPField(buf, next)
+		if err != nil {
+			return nil, err
+		}
+		pos = next
+
+		if len(name) == 0 && len(group.attrs) > 0 {
+			last := &group.attrs[len(group.attrs)-1]
+			last.values = append(last.values, value)
+			continue
+		}
+		group.attrs = append(group.attrs, ippAttribute{tag: tag, name: string(name), values: [][]byte{value}})
+	}
+}
+
+// readIPPField reads a 2-byte length followed by that many bytes
+func readIPPField(buf []byte, pos int) ([]byte, int, error) {
+	if pos+2 > len(buf) {
+		return nil, 0, fmt.Errorf("%w: truncated length", errIPPMessage)
+	}
+	n := int(binary.BigEndian.Uint16(buf[pos : pos+2]))
+	pos += 2
+	if pos+n > len(buf) {
+		return nil, 0, fmt.Errorf("%w: truncated value", errIPPMessage)
+	}
+	return buf[pos : pos+n], pos + n, nil
+}
+
+// attr returns the first attribute called name in the groups with the given tag
+func (m *ippMessage) attr(groupTag byte, name string) (ippAttribute, bool) {
+	for _, g := range m.groups {
+		if g.tag != groupTag {
+			continue
+		}
+		for _, a := range g.attrs {
+			if a.name == name {
+				return a, true
+			}
+		}
+	}
+	return ippAttribute{}, false
+}
+
+// integer returns the first value of an integer or enum attribute
+func (a ippAttribute) integer() (int, bool) {
+	if len(a.values) == 0 || len(a.values[0]) != 4 {
+		return 0, false
+	}
+	return int(int32(binary.BigEndian.Uint32(a.values[0]))), true
+}
+
+// strings returns the values of a textual attribute
+func (a ippAttribute) strings() []string {
+	out := make([]string, len(a.values))
+	for i, v := range a.values {
+		out[i] = string(v)
+	}
+	return out
+}
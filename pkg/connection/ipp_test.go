@@ -0,0 +1,257 @@
+package connection_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/connection"
+)
+
+func newIPPConnector(t *testing.T, server *testutils.IPPServer, wait bool) *connection.IPPConnector {
+	t.Helper()
+	cfg := connection.DefaultIPPConfig(server.URL())
+	cfg.WaitCompletion = wait
+	cfg.PollInterval = time.Millisecond
+	c, err := connection.NewIPPConnector(cfg)
+	if err != nil {
+		t.Fatalf("NewIPPConnector() error = %v", err)
+	}
+	return c
+}
+
+func TestIPPConnector_SendsPrintJobOnEndJob(t *testing.T) {
+	server := testutils.NewIPPServer(t)
+	c := newIPPConnector(t, server, false)
+
+	payload := []byte{0x1B, '@', 'H', 'i', '\n', 0x1D, 'V', 0x41, 0x00}
+	if err := c.BeginJob("ticket-42"); err != nil {
+		t.Fatalf("BeginJob() error = %v", err)
+	}
+	if _, err := c.Write(payload); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := server.Jobs(); len(got) != 0 {
+		t.Fatalf("server received %d jobs before EndJob, want 0", len(got))
+	}
+	if err := c.EndJob(); err != nil {
+		t.Fatalf("EndJob() error = %v", err)
+	}
+
+	jobs := server.Jobs()
+	if len(jobs) != 1 {
+		t.Fatalf("server received %d jobs, want 1", len(jobs))
+	}
+	job := jobs[0]
+	if job.ContentType != "application/ipp" {
+		t.Errorf("Content-Type = %q, want application/ipp", job.ContentType)
+	}
+	if !bytes.Equal(job.Data, payload) {
+		t.Errorf("Data = %#v, want %#v", job.Data, payload)
+	}
+	want := map[string]string{
+		"attributes-charset":   "utf-8",
+		"printer-uri":          server.URL(),
+		"requesting-user-name": connection.DefaultLPDUser,
+		"job-name":             "ticket-42",
+		"document-format":      connection.IPPFormatCUPSRaw,
+	}
+	for name, value := range want {
+		if got := job.Attr(name); got != value {
+			t.Errorf("attribute %s = %q, want %q", name, got, value)
+		}
+	}
+
+	last := c.LastJob()
+	if last.ID != 1 || last.State != connection.JobPending {
+		t.Errorf("LastJob() = %+v, want job 1 pending", last)
+	}
+
+	// Nothing buffered: Close sends no job
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got := len(server.Jobs()); got != 1 {
+		t.Errorf("server received %d jobs after Close, want 1", got)
+	}
+	if _, err := c.Write(payload); !errors.Is(err, connection.ErrConnectionClosed) {
+		t.Errorf("Write() after Close error = %v, want ErrConnectionClosed", err)
+	}
+}
+
+func TestIPPConnector_WaitCompletion(t *testing.T) {
+	tests := []struct {
+		name    string
+		states  []int
+		wantErr error
+		want    connection.JobState
+	}{
+		{"completed", []int{5, 5, 9}, nil, connection.JobCompleted},
+		{"aborted", []int{5, 8}, connection.ErrIPPJobAborted, connection.JobAborted},
+		{"canceled", []int{7}, connection.ErrIPPJobCanceled, connection.JobCanceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testutils.NewIPPServer(t)
+			server.SetJobStates(tt.states...)
+			c := newIPPConnector(t, server, true)
+
+			if _, err := c.Write([]byte("receipt")); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			err := c.Close()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Close() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := c.LastJob().State; got != tt.want {
+				t.Errorf("LastJob().State = %v, want %v", got, tt.want)
+			}
+
+			polls := 0
+			for _, r := range server.Requests() {
+				if r.Operation == 0x0009 {
+					polls++
+					if r.Attr("job-id") != "1" {
+						t.Errorf("Get-Job-Attributes job-id = %q, want 1", r.Attr("job-id"))
+					}
+				}
+			}
+			if polls != len(tt.states) {
+				t.Errorf("Get-Job-Attributes requests = %d, want %d", polls, len(tt.states))
+			}
+		})
+	}
+}
+
+func TestIPPConnector_GetJobAndWaitJob(t *testing.T) {
+	server := testutils.NewIPPServer(t)
+	server.SetJobStates(6)
+	server.SetJobReasons("media-empty", "cover-open")
+	c := newIPPConnector(t, server, false)
+
+	job, err := c.GetJob(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if job.ID != 7 || job.State != connection.JobProcessingStopped || job.State.Terminal() {
+		t.Errorf("GetJob() = %+v, want job 7 processing-stopped", job)
+	}
+	if strings.Join(job.Reasons, ",") != "media-empty,cover-open" {
+		t.Errorf("Reasons = %v, want [media-empty cover-open]", job.Reasons)
+	}
+
+	// A stopped job never finishes: WaitJob ends with the context
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.WaitJob(ctx, 7); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitJob() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestIPPConnector_ErrorStatus(t *testing.T) {
+	server := testutils.NewIPPServer(t)
+	server.FailWith(0x0506, "Printer is paused")
+	c := newIPPConnector(t, server, false)
+
+	if _, err := c.Write([]byte("receipt")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	err := c.EndJob()
+	if !errors.Is(err, connection.ErrIPPRequest) {
+		t.Fatalf("EndJob() error = %v, want ErrIPPRequest", err)
+	}
+	if !strings.Contains(err.Error(), "server-error-not-accepting-jobs") {
+		t.Errorf("EndJob() error = %q, want it to mention %q", err, "server-error-not-accepting-jobs")
+	}
+	if !strings.Contains(err.Error(), "Printer is paused") {
+		t.Errorf("EndJob() error = %q, want it to mention %q", err, "Printer is paused")
+	}
+
+	// The failed job is not submitted again
+	if err := c.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if got := len(server.Jobs()); got != 1 {
+		t.Errorf("server received %d jobs, want 1", got)
+	}
+}
+
+func TestIPPConnector_Timeout(t *testing.T) {
+	// A server that accepts connections but never answers
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	var accepted atomic.Int32
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted.Add(1)
+			defer conn.Close()
+		}
+	}()
+
+	cfg := connection.DefaultIPPConfig("ipp://" + ln.Addr().String() + "/printers/receipt")
+	cfg.Timeout = 50 * time.Millisecond
+	c, err := connection.NewIPPConnector(cfg)
+	if err != nil {
+		t.Fatalf("NewIPPConnector() error = %v", err)
+	}
+
+	if _, err := c.Write([]byte("receipt")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := c.EndJob(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("EndJob() error = %v, want context.DeadlineExceeded", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if n := accepted.Load(); n != 1 {
+		t.Errorf("server accepted %d connections, want 1", n)
+	}
+}
+
+func TestIPPConnector_NewValidation(t *testing.T) {
+	if _, err := connection.NewIPPConnector(nil); err == nil {
+		t.Error("NewIPPConnector(nil) expected error")
+	}
+	if _, err := connection.NewIPPConnector(&connection.IPPConfig{}); !errors.Is(err, connection.ErrEmptyAddress) {
+		t.Errorf("empty URL error = %v, want ErrEmptyAddress", err)
+	}
+	for _, u := range []string{"lpd://host/queue", "ipp:///printers/receipt"} {
+		if _, err := connection.NewIPPConnector(connection.DefaultIPPConfig(u)); !errors.Is(err, connection.ErrIPPURL) {
+			t.Errorf("NewIPPConnector(%q) error = %v, want ErrIPPURL", u, err)
+		}
+	}
+	for _, u := range []string{"ipp://cups.local/printers/receipt", "https://cups.local:8631/printers/receipt"} {
+		if _, err := connection.NewIPPConnector(connection.DefaultIPPConfig(u)); err != nil {
+			t.Errorf("NewIPPConnector(%q) error = %v", u, err)
+		}
+	}
+}
+
+func TestJobState_String(t *testing.T) {
+	tests := map[connection.JobState]string{
+		connection.JobPending:   "pending",
+		connection.JobCompleted: "completed",
+		connection.JobAborted:   "aborted",
+		connection.JobState(42): "JobState(42)",
+	}
+	for state, want := range tests {
+		if got := state.String(); got != want {
+			t.Errorf("JobState(%d).String() = %q, want %q", int(state), got, want)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package connection
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultLPDPort is the Line Printer Daemon port (RFC 1179)
+const DefaultLPDPort = "515"
+
+// LPD defaults
+const (
+	DefaultLPDUser    = "pos-printer"
+	DefaultLPDJobName = "escpos"
+	DefaultLPDTimeout = 30 * time.Second
+)
+
+// LPD protocol bytes (RFC 1179)
+const (
+	lpdReceiveJob  byte = 0x02 // Command: receive a printer job
+	lpdControlFile byte = 0x02 // Subcommand: receive control file
+	lpdDataFile    byte = 0x03 // Subcommand: receive data file
+	lpdAck         byte = 0x00 // Positive acknowledgement
+)
+
+// Control file line length limits (RFC 1179, section 7)
+const (
+	lpdMaxHost    = 31
+	lpdMaxUser    = 31
+	lpdMaxJobName = 99
+)
+
+var (
+	// ErrLPDQueue indicates an empty queue name or one with whitespace or control characters
+	ErrLPDQueue = errors.New("invalid LPD queue name")
+	// ErrLPDRejected indicates that the LPD server answered a step with a negative acknowledgement
+	ErrLPDRejected = errors.New("LPD server rejected the job")
+)
+
+// Interface compliance checks
+var (
+	_ Connector = (*LPDConnector)(nil)
+	_ JobMarker = (*LPDConnector)(nil)
+)
+
+// LPDConfig holds the settings of an LPD (RFC 1179) connection
+type LPDConfig struct {
+	// Address is the server address as host or host:port; port 515 is used when omitted
+	Address string
+	// Queue is the print queue (printer name) on the server
+	Queue string
+	// Host is the client host name sent in the control file (default: os.Hostname)
+	Host string
+	// User is the job owner sent in the control file
+	User string
+	// JobName is the name of jobs sent without BeginJob
+	JobName string
+	// DialTimeout bounds the time spent establishing the connection
+	DialTimeout time.Duration
+	// Timeout bounds the transfer of each job (0 disables the deadline)
+	Timeout time.Duration
+}
+
+// DefaultLPDConfig returns a configuration with sensible defaults for the given server and queue
+func DefaultLPDConfig(address, queue string) *LPDConfig {
+	return &LPDConfig{
+		Address:     address,
+		Queue:       queue,
+		User:        DefaultLPDUser,
+		JobName:     DefaultLPDJobName,
+		DialTimeout: DefaultDialTimeout,
+		Timeout:     DefaultLPDTimeout,
+	}
+}
+
+// LPDConnector sends jobs to an LPD print server. Writes are buffered, and the job is
+// transferred as a raw ('l') data file, which passes the ESC/POS bytes through untouched,
+// when EndJob or Close is called.
+type LPDConnector struct {
+	config LPDConfig
+
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	jobName string
+	jobNum  int
+	closed  bool
+}
+
+// NewLPDConnector validates config; no connection is made until the first job is sent
+func NewLPDConnector(config *LPDConfig) (*LPDConnector, error) {
+	if config == nil {
+		return nil, errors.New("LPD config cannot be nil")
+	}
+	if config.Address == "" {
+		return nil, ErrEmptyAddress
+	}
+	if config.Queue == "" || strings.IndexFunc(config.Queue, isQueueSeparator) >= 0 {
+		return nil, fmt.Errorf("%w: %q", ErrLPDQueue, config.Queue)
+	}
+
+	cfg := *config
+	cfg.Address = normalizeAddress(cfg.Address, DefaultLPDPort)
+	if cfg.Host == "" {
+		cfg.Host, _ = os.Hostname()
+	}
+	cfg.Host = controlField(cfg.Host, lpdMaxHost)
+	if cfg.Host == "" {
+		cfg.Host = "localhost"
+	}
+	cfg.User = controlField(cfg.User, lpdMaxUser)
+	if cfg.User == "" {
+		cfg.User = DefaultLPDUser
+	}
+	cfg.JobName = controlField(cfg.JobName, lpdMaxJobName)
+	if cfg.JobName == "" {
+		cfg.JobName = DefaultLPDJobName
+	}
+
+	return &LPDConnector{
+		config: cfg,
+		// Job numbers only need to be unique for a while on the server
+		jobNum: os.Getpid() % 1000,
+	}, nil
+}
+
+// Address returns the resolved host:port of the LPD server
+func (c *LPDConnector) Address() string {
+	return c.config.Address
+}
+
+// Write buffers data for the current job
+func (c *LPDConnector) Write(data []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return 0, ErrConnectionClosed
+	}
+	return c.buf.Write(data)
+}
+
+// BeginJob sends the data buffered so far, if any, and names the next job
+func (c *LPDConnector) BeginJob(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrConnectionClosed
+	}
+	if err := c.flush(); err != nil {
+		return err
+	}
+	c.jobName = name
+	return nil
+}
+
+// EndJob sends the buffered data as one job
+func (c *LPDConnector) EndJob() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrConnectionClosed
+	}
+	return c.flush()
+}
+
+// Close sends the buffered data, if any, as the last job
+func (c *LPDConnector) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.flush()
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+// flush sends the buffered data as a job. The buffer is emptied even when the transfer
+// fails: the server may have received the data before the error, so sending it again
+// could print the job twice. Must be called with mu held.
+func (c *LPDConnector) flush() error {
+	if c.buf.Len() == 0 {
+		return nil
+	}
+	defer func() {
+		c.buf.Reset()
+		c.jobName = ""
+	}()
+
+	name := controlField(c.jobName, lpdMaxJobName)
+	if name == "" {
+		name = c.config.JobName
+	}
+	c.jobNum = (c.jobNum + 1) % 1000
+
+	return c.send(name, c.buf.Bytes())
+}
+
+// send transfers one job: the receive job command, the control file and the data file
+func (c *LPDConnector) send(jobName string, data []byte) error {
+	dialer := &net.Dialer{Timeout: c.config.DialTimeout}
+	conn, err := dialer.Dial("tcp", c.config.Address)
+	if err != nil {
+		return fmt.Errorf("dial LPD server %s: %w", c.config.Address, err)
+	}
+	defer conn.Close()
+
+	if c.config.Timeout > 0 {
+		if err := conn.SetDeadline(time.Now().Add(c.config.Timeout)); err != nil {
+			return err
+		}
+	}
+
+	suffix := fmt.Sprintf("A%03d%s", c.jobNum, c.config.Host)
+	dataName := "df" + suffix
+	control := fmt.Sprintf("H%s\nP%s\nJ%s\nl%s\nU%s\nN%s\n",
+		c.config.Host, c.config.User, jobName, dataName, dataName, jobName)
+
+	w := bufio.NewWriter(conn)
+	steps := []struct {
+		name string
+		send func() error
+	}{
+		{"receive job", func() error {
+			_, err := fmt.Fprintf(w, "%c%s\n", lpdReceiveJob, c.config.Queue)
+			return err
+		}},
+		{"control file header", func() error {
+			_, err := fmt.Fprintf(w, "%c%d cf%s\n", lpdControlFile, len(control), suffix)
+			return err
+		}},
+		{"control file", func() error {
+			_, err := w.WriteString(control + "\x00")
+			return err
+		}},
+		{"data file header", func() error {
+			_, err := fmt.Fprintf(w, "%c%d %s\n", lpdDataFile, len(data), dataName)
+			return err
+		}},
+		{"data file", func() error {
+			if _, err := w.Write(data); err != nil {
+				return err
+			}
+			return w.WriteByte(0)
+		}},
+	}
+
+	ack := make([]byte, 1)
+	for _, step := range steps {
+		if err := step.send(); err != nil {
+			return fmt.Errorf("LPD %s: %w", step.name, err)
+		}
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("LPD %s: %w", step.name, err)
+		}
+		if _, err := conn.Read(ack); err != nil {
+			return fmt.Errorf("LPD %s acknowledgement: %w", step.name, err)
+		}
+		if ack[0] != lpdAck {
+			return fmt.Errorf("%w: %s (code %d)", ErrLPDRejected, step.name, ack[0])
+		}
+	}
+	return nil
+}
+
+// isQueueSeparator reports whether r would end the queue name in the receive job command
+func isQueueSeparator(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsControl(r)
+}
+
+// controlField prepares s for a control file line: control characters, which would
+// end the line or inject new ones, are removed and the result is cut to at most n
+// bytes without splitting a UTF-8 sequence
+func controlField(s string, n int) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
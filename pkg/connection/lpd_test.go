@@ -0,0 +1,218 @@
+package connection_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/connection"
+)
+
+func newLPDConfig(addr, queue string) *connection.LPDConfig {
+	cfg := connection.DefaultLPDConfig(addr, queue)
+	cfg.Host = "pos01"
+	return cfg
+}
+
+func TestLPDConnector_SendsRawJobOnClose(t *testing.T) {
+	server := testutils.NewLPDServer(t, "receipts")
+
+	c, err := connection.NewLPDConnector(newLPDConfig(server.Addr(), "receipts"))
+	if err != nil {
+		t.Fatalf("NewLPDConnector() error = %v", err)
+	}
+
+	// ESC/POS data with NUL and newline bytes must pass through untouched
+	payload := []byte{0x1B, '@', 'H', 'i', '\n', 0x1D, 'V', 0x41, 0x00}
+	if _, err := c.Write(payload[:5]); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if _, err := c.Write(payload[5:]); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := server.Jobs(); len(got) != 0 {
+		t.Fatalf("server received %d jobs before Close, want 0", len(got))
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	jobs := server.Jobs()
+	if len(jobs) != 1 {
+		t.Fatalf("server received %d jobs, want 1", len(jobs))
+	}
+	job := jobs[0]
+	if job.Queue != "receipts" {
+		t.Errorf("Queue = %q, want %q", job.Queue, "receipts")
+	}
+	if !bytes.Equal(job.Data, payload) {
+		t.Errorf("Data = %#v, want %#v", job.Data, payload)
+	}
+
+	lines := job.ControlLines()
+	if lines['l'] != job.DataName || lines['U'] != job.DataName {
+		t.Errorf("control file %q does not print and unlink %s as raw", job.Control, job.DataName)
+	}
+	if lines['H'] != "pos01" || lines['P'] != connection.DefaultLPDUser || lines['J'] != connection.DefaultLPDJobName {
+		t.Errorf("control file %q has unexpected host, user or job name", job.Control)
+	}
+	if job.ControlName[:3] != "cfA" || job.DataName[:3] != "dfA" || job.ControlName[3:] != job.DataName[3:] {
+		t.Errorf("file names %q and %q do not match", job.ControlName, job.DataName)
+	}
+
+	if _, err := c.Write([]byte("late")); !errors.Is(err, connection.ErrConnectionClosed) {
+		t.Errorf("Write() after Close error = %v, want ErrConnectionClosed", err)
+	}
+}
+
+func TestLPDConnector_JobBoundaries(t *testing.T) {
+	server := testutils.NewLPDServer(t)
+
+	c, err := connection.NewLPDConnector(newLPDConfig(server.Addr(), "raw"))
+	if err != nil {
+		t.Fatalf("NewLPDConnector() error = %v", err)
+	}
+
+	for _, name := range []string{"order-1", "order-2"} {
+		if err := c.BeginJob(name); err != nil {
+			t.Fatalf("BeginJob() error = %v", err)
+		}
+		if _, err := c.Write([]byte(name)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		if err := c.EndJob(); err != nil {
+			t.Fatalf("EndJob() error = %v", err)
+		}
+	}
+	// Nothing left to send
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	jobs := server.Jobs()
+	if len(jobs) != 2 {
+		t.Fatalf("server received %d jobs, want 2", len(jobs))
+	}
+	for i, name := range []string{"order-1", "order-2"} {
+		if string(jobs[i].Data) != name || jobs[i].ControlLines()['J'] != name {
+			t.Errorf("job %d = %q named %q, want %q", i, jobs[i].Data, jobs[i].ControlLines()['J'], name)
+		}
+	}
+	if jobs[0].DataName == jobs[1].DataName {
+		t.Errorf("both jobs use data file %s", jobs[0].DataName)
+	}
+}
+
+func TestLPDConnector_SanitizesControlFields(t *testing.T) {
+	server := testutils.NewLPDServer(t)
+
+	cfg := newLPDConfig(server.Addr(), "raw")
+	cfg.User = "cashier\nPintruder"
+	c, err := connection.NewLPDConnector(cfg)
+	if err != nil {
+		t.Fatalf("NewLPDConnector() error = %v", err)
+	}
+
+	long := strings.Repeat("ñ", 60) // 120 bytes
+	names := []string{"order-1\nUdfA001evil\r\nJx", "\n\t", long}
+	for _, name := range names {
+		if err := c.BeginJob(name); err != nil {
+			t.Fatalf("BeginJob() error = %v", err)
+		}
+		if _, err := c.Write([]byte("data")); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		if err := c.EndJob(); err != nil {
+			t.Fatalf("EndJob() error = %v", err)
+		}
+	}
+
+	jobs := server.Jobs()
+	if len(jobs) != len(names) {
+		t.Fatalf("server received %d jobs, want %d", len(jobs), len(names))
+	}
+	want := []string{"order-1UdfA001evilJx", connection.DefaultLPDJobName, strings.Repeat("ñ", 49)}
+	for i, job := range jobs {
+		if n := strings.Count(job.Control, "\n"); n != 6 {
+			t.Errorf("job %d control file %q has %d lines, want 6", i, job.Control, n)
+		}
+		lines := job.ControlLines()
+		if lines['J'] != want[i] || lines['N'] != want[i] {
+			t.Errorf("job %d named %q/%q, want %q", i, lines['J'], lines['N'], want[i])
+		}
+		if lines['P'] != "cashierPintruder" {
+			t.Errorf("job %d user = %q, want %q", i, lines['P'], "cashierPintruder")
+		}
+	}
+}
+
+func TestLPDConnector_QueueRejected(t *testing.T) {
+	server := testutils.NewLPDServer(t, "receipts")
+
+	c, err := connection.NewLPDConnector(newLPDConfig(server.Addr(), "kitchen"))
+	if err != nil {
+		t.Fatalf("NewLPDConnector() error = %v", err)
+	}
+	_, _ = c.Write([]byte("x"))
+	if err := c.Close(); !errors.Is(err, connection.ErrLPDRejected) {
+		t.Errorf("Close() error = %v, want ErrLPDRejected", err)
+	}
+}
+
+func TestLPDConnector_FailedJobNotResent(t *testing.T) {
+	server := testutils.NewLPDServer(t)
+	server.RejectJobs(true)
+
+	c, err := connection.NewLPDConnector(newLPDConfig(server.Addr(), "raw"))
+	if err != nil {
+		t.Fatalf("NewLPDConnector() error = %v", err)
+	}
+	if _, err := c.Write([]byte("receipt")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := c.EndJob(); !errors.Is(err, connection.ErrLPDRejected) {
+		t.Fatalf("EndJob() error = %v, want ErrLPDRejected", err)
+	}
+
+	// The rejected job reached the server once and is not sent again
+	if err := c.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if jobs := server.Jobs(); len(jobs) != 1 {
+		t.Errorf("server received %d jobs, want 1", len(jobs))
+	}
+}
+
+func TestNewLPDConnector_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *connection.LPDConfig
+		wantErr error
+	}{
+		{"empty address", connection.DefaultLPDConfig("", "raw"), connection.ErrEmptyAddress},
+		{"empty queue", connection.DefaultLPDConfig("127.0.0.1", ""), connection.ErrLPDQueue},
+		{"queue with spaces", connection.DefaultLPDConfig("127.0.0.1", "front desk"), connection.ErrLPDQueue},
+		{"queue with control byte", connection.DefaultLPDConfig("127.0.0.1", "raw\x00"), connection.ErrLPDQueue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := connection.NewLPDConnector(tt.config)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewLPDConnector() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLPDConnector_DefaultPort(t *testing.T) {
+	c, err := connection.NewLPDConnector(connection.DefaultLPDConfig("192.0.2.10", "raw"))
+	if err != nil {
+		t.Fatalf("NewLPDConnector() error = %v", err)
+	}
+	if c.Address() != "192.0.2.10:515" {
+		t.Errorf("Address() = %q, want %q", c.Address(), "192.0.2.10:515")
+	}
+}
@@ -0,0 +1,287 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// DefaultNetworkPort is the raw printing port (a.k.a. JetDirect/AppSocket) used by most network printers
+const DefaultNetworkPort = "9100"
+
+// Network defaults
+const (
+	DefaultDialTimeout  = 5 * time.Second
+	DefaultWriteTimeout = 10 * time.Second
+	DefaultReadTimeout  = 2 * time.Second
+	DefaultKeepAlive    = 30 * time.Second
+	DefaultIdleProbe    = time.Second
+)
+
+var (
+	// ErrEmptyAddress indicates that no printer address was provided
+	ErrEmptyAddress = errors.New("network address cannot be empty")
+	// ErrConnectionClosed indicates that the connection was closed locally or by the printer
+	ErrConnectionClosed = errors.New("connection closed")
+)
+
+// Interface compliance check
+var _ ReadWriteConnector = (*NetworkConnector)(nil)
+
+// NetworkConfig holds the settings of a raw TCP/IP connection
+type NetworkConfig struct {
+	// Address is the printer address as host or host:port; port 9100 is used when omitted
+	Address string
+	// DialTimeout bounds the time spent establishing the connection
+	DialTimeout time.Duration
+	// WriteTimeout bounds each Write call (0 disables the deadline)
+	WriteTimeout time.Duration
+	// ReadTimeout bounds each Read call (0 disables the deadline)
+	ReadTimeout time.Duration
+	// KeepAlive sets the TCP keepalive period (negative disables keepalive)
+	KeepAlive time.Duration
+	// IdleProbe is how long the socket must be idle before a write first checks that the
+	// printer has not closed its side (0 disables the check; failed writes are still detected)
+	IdleProbe time.Duration
+}
+
+// DefaultNetworkConfig returns a configuration with sensible timeouts for the given address
+func DefaultNetworkConfig(address string) *NetworkConfig {
+	return &NetworkConfig{
+		Address:      address,
+		DialTimeout:  DefaultDialTimeout,
+		WriteTimeout: DefaultWriteTimeout,
+		ReadTimeout:  DefaultReadTimeout,
+		KeepAlive:    DefaultKeepAlive,
+		IdleProbe:    DefaultIdleProbe,
+	}
+}
+
+// NetworkConnector implements a connector for printers reachable through raw TCP/IP (port 9100)
+type NetworkConnector struct {
+	config NetworkConfig
+	conn   net.Conn
+	closed atomic.Bool
+
+	peerClosed atomic.Bool  // the printer closed or reset its side of the socket
+	lastActive atomic.Int64 // time of the last successful I/O, in Unix nanoseconds
+
+	writeMu       sync.Mutex
+	writeDeadline deadline
+
+	readMu       sync.Mutex
+	readDeadline deadline
+	pending      []byte // bytes received while probing the socket, served on next Read
+}
+
+// NewNetworkConnector dials the printer described by config
+func NewNetworkConnector(config *NetworkConfig) (*NetworkConnector, error) {
+	if config == nil {
+		return nil, errors.New("network config cannot be nil")
+	}
+	if config.Address == "" {
+		return nil, ErrEmptyAddress
+	}
+
+	cfg := *config
+	cfg.Address = normalizeAddress(cfg.Address, DefaultNetworkPort)
+
+	dialer := &net.Dialer{
+		Timeout:   cfg.DialTimeout,
+		KeepAlive: cfg.KeepAlive,
+	}
+	conn, err := dialer.Dial("tcp", cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("dial printer %s: %w", cfg.Address, err)
+	}
+
+	c := &NetworkConnector{
+		config: cfg,
+		conn:   conn,
+	}
+	c.touch()
+	return c, nil
+}
+
+// Address returns the resolved host:port of the printer
+func (c *NetworkConnector) Address() string {
+	return c.config.Address
+}
+
+// Write sends data to the printer, failing fast if the printer closed its side of the socket
+func (c *NetworkConnector) Write(data []byte) (int, error) {
+	return c.WriteContext(context.Background(), data)
+}
+
+// WriteContext is like Write but aborts when ctx is done
+func (c *NetworkConnector) WriteContext(ctx context.Context, data []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closed.Load() {
+		return 0, ErrConnectionClosed
+	}
+	if c.peerClosed.Load() {
+		return 0, fmt.Errorf("%w: printer closed the connection", ErrConnectionClosed)
+	}
+	// Writes in a burst skip the probe; only a socket that sat idle is checked first
+	if c.config.IdleProbe > 0 && c.idle() >= c.config.IdleProbe {
+		if err := c.probe(); err != nil {
+			return 0, err
+		}
+	}
+
+	deadline := effectiveDeadline(ctx, c.writeDeadline.get(), c.config.WriteTimeout)
+	n, err := doWithContext(ctx, deadline, c.conn.SetWriteDeadline, func() (int, error) {
+		return c.conn.Write(data)
+	})
+	if err != nil {
+		if isConnectionReset(err) {
+			c.peerClosed.Store(true)
+			return n, fmt.Errorf("%w: %w", ErrConnectionClosed, err)
+		}
+		return n, fmt.Errorf("write to printer: %w", err)
+	}
+	c.touch()
+	return n, nil
+}
+
+// Read receives status bytes sent back by the printer
+func (c *NetworkConnector) Read(buf []byte) (int, error) {
+	return c.ReadContext(context.Background(), buf)
+}
+
+// ReadContext is like Read but aborts when ctx is done
+func (c *NetworkConnector) ReadContext(ctx context.Context, buf []byte) (int, error) {
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+
+	if len(c.pending) > 0 {
+		n := copy(buf, c.pending)
+		c.pending = c.pending[n:]
+		return n, nil
+	}
+	if c.closed.Load() {
+		return 0, ErrConnectionClosed
+	}
+
+	deadline := effectiveDeadline(ctx, c.readDeadline.get(), c.config.ReadTimeout)
+	n, err := doWithContext(ctx, deadline, c.conn.SetReadDeadline, func() (int, error) {
+		return c.conn.Read(buf)
+	})
+	if err != nil {
+		if errors.Is(err, io.EOF) || isConnectionReset(err) {
+			c.peerClosed.Store(true)
+			return n, fmt.Errorf("%w: %w", ErrConnectionClosed, err)
+		}
+		return n, fmt.Errorf("read from printer: %w", err)
+	}
+	c.touch()
+	return n, nil
+}
+
+// SetReadDeadline sets an absolute deadline for subsequent reads (zero restores ReadTimeout)
+func (c *NetworkConnector) SetReadDeadline(t time.Time) error {
+	c.readDeadline.set(t)
+	return nil
+}
+
+// SetWriteDeadline sets an absolute deadline for subsequent writes (zero restores WriteTimeout)
+func (c *NetworkConnector) SetWriteDeadline(t time.Time) error {
+	c.writeDeadline.set(t)
+	return nil
+}
+
+// IsConnected reports whether the socket is still usable
+func (c *NetworkConnector) IsConnected() bool {
+	if c.closed.Load() || c.peerClosed.Load() {
+		return false
+	}
+	return c.probe() == nil
+}
+
+// Close closes the TCP connection
+func (c *NetworkConnector) Close() error {
+	if c.closed.Swap(true) {
+		return nil
+	}
+	if err := c.conn.Close(); err != nil {
+		return fmt.Errorf("close connection: %w", err)
+	}
+	return nil
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+// probeTimeout is how long probe waits for the peer before assuming the socket is healthy
+const probeTimeout = time.Millisecond
+
+// probe detects a half-closed socket by attempting a very short read.
+// A FIN from the printer surfaces as io.EOF; any data read is kept for the next Read.
+// The probe is skipped while another goroutine is reading, since that reader
+// will observe the closed socket itself. Writes only probe after IdleProbe without
+// I/O, so the cost is not paid on every small write of a ticket.
+func (c *NetworkConnector) probe() error {
+	if !c.readMu.TryLock() {
+		return nil
+	}
+	defer c.readMu.Unlock()
+
+	if err := c.conn.SetReadDeadline(time.Now().Add(probeTimeout)); err != nil {
+		return fmt.Errorf("set probe deadline: %w", err)
+	}
+
+	buf := make([]byte, 256)
+	n, err := c.conn.Read(buf)
+	if n > 0 {
+		c.pending = append(c.pending, buf[:n]...)
+	}
+
+	var netErr net.Error
+	switch {
+	case err == nil:
+		c.touch()
+		return nil
+	case errors.As(err, &netErr) && netErr.Timeout():
+		c.touch()
+		return nil
+	case errors.Is(err, io.EOF) || isConnectionReset(err):
+		c.peerClosed.Store(true)
+		return fmt.Errorf("%w: printer closed the connection", ErrConnectionClosed)
+	default:
+		return fmt.Errorf("probe connection: %w", err)
+	}
+}
+
+// touch records successful I/O on the socket
+func (c *NetworkConnector) touch() {
+	c.lastActive.Store(time.Now().UnixNano())
+}
+
+// idle returns the time since the last successful I/O
+func (c *NetworkConnector) idle() time.Duration {
+	return time.Since(time.Unix(0, c.lastActive.Load()))
+}
+
+// normalizeAddress appends port when the address has none
+func normalizeAddress(address, port string) string {
+	if _, _, err := net.SplitHostPort(address); err == nil {
+		return address
+	}
+	return net.JoinHostPort(address, port)
+}
+
+// isConnectionReset reports whether err means the peer is gone
+func isConnectionReset(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, net.ErrClosed)
+}
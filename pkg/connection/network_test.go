@@ -0,0 +1,252 @@
+package connection_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/adcondev/pos-printer/pkg/connection"
+)
+
+// startListener starts a local stand-in for a network printer and hands every accepted connection to handle
+func startListener(t *testing.T, handle func(net.Conn)) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go handle(conn)
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func newTestConfig(address string) *connection.NetworkConfig {
+	cfg := connection.DefaultNetworkConfig(address)
+	cfg.DialTimeout = time.Second
+	cfg.WriteTimeout = time.Second
+	cfg.ReadTimeout = 200 * time.Millisecond
+	return cfg
+}
+
+func TestNetworkConnector_Write(t *testing.T) {
+	received := make(chan []byte, 1)
+	addr := startListener(t, func(conn net.Conn) {
+		defer conn.Close()
+		data, _ := io.ReadAll(conn)
+		received <- data
+	})
+
+	c, err := connection.NewNetworkConnector(newTestConfig(addr))
+	if err != nil {
+		t.Fatalf("NewNetworkConnector: %v", err)
+	}
+
+	payload := []byte{0x1B, '@', 'H', 'i', '\n'}
+	n, err := c.Write(payload)
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if n != len(payload) {
+		t.Errorf("Write returned %d; want %d", n, len(payload))
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	select {
+	case got := <-received:
+		if !bytes.Equal(got, payload) {
+			t.Errorf("printer received %#v; want %#v", got, payload)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for printer data")
+	}
+}
+
+func TestNetworkConnector_Read(t *testing.T) {
+	addr := startListener(t, func(conn net.Conn) {
+		defer conn.Close()
+		buf := make([]byte, 3)
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return
+		}
+		// Answer DLE EOT 1 with a status byte
+		_, _ = conn.Write([]byte{0x16})
+		time.Sleep(500 * time.Millisecond)
+	})
+
+	c, err := connection.NewNetworkConnector(newTestConfig(addr))
+	if err != nil {
+		t.Fatalf("NewNetworkConnector: %v", err)
+	}
+	defer c.Close()
+
+	if _, err := c.Write([]byte{0x10, 0x04, 0x01}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	buf := make([]byte, 8)
+	n, err := c.Read(buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if n != 1 || buf[0] != 0x16 {
+		t.Errorf("Read = %#v; want [0x16]", buf[:n])
+	}
+}
+
+func TestNetworkConnector_ReadTimeout(t *testing.T) {
+	addr := startListener(t, func(conn net.Conn) {
+		defer conn.Close()
+		time.Sleep(time.Second)
+	})
+
+	c, err := connection.NewNetworkConnector(newTestConfig(addr))
+	if err != nil {
+		t.Fatalf("NewNetworkConnector: %v", err)
+	}
+	defer c.Close()
+
+	_, err = c.Read(make([]byte, 1))
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("Read error = %v; want timeout", err)
+	}
+}
+
+func TestNetworkConnector_HalfClosed(t *testing.T) {
+	closed := make(chan struct{})
+	addr := startListener(t, func(conn net.Conn) {
+		_ = conn.Close()
+		close(closed)
+	})
+
+	c, err := connection.NewNetworkConnector(newTestConfig(addr))
+	if err != nil {
+		t.Fatalf("NewNetworkConnector: %v", err)
+	}
+	defer c.Close()
+
+	<-closed
+	time.Sleep(50 * time.Millisecond)
+
+	if c.IsConnected() {
+		t.Error("IsConnected() = true after printer closed the socket")
+	}
+	if _, err := c.Write([]byte("data")); !errors.Is(err, connection.ErrConnectionClosed) {
+		t.Errorf("Write error = %v; want %v", err, connection.ErrConnectionClosed)
+	}
+}
+
+func TestNetworkConnector_IdleProbe(t *testing.T) {
+	closed := make(chan struct{})
+	addr := startListener(t, func(conn net.Conn) {
+		_ = conn.Close()
+		close(closed)
+	})
+
+	cfg := newTestConfig(addr)
+	cfg.IdleProbe = 20 * time.Millisecond
+	c, err := connection.NewNetworkConnector(cfg)
+	if err != nil {
+		t.Fatalf("NewNetworkConnector: %v", err)
+	}
+	defer c.Close()
+
+	<-closed
+	time.Sleep(50 * time.Millisecond)
+
+	// The socket sat idle longer than IdleProbe, so the write checks it first
+	if _, err := c.Write([]byte("data")); !errors.Is(err, connection.ErrConnectionClosed) {
+		t.Errorf("Write error = %v; want %v", err, connection.ErrConnectionClosed)
+	}
+	if c.IsConnected() {
+		t.Error("IsConnected() = true after a write found the socket closed")
+	}
+}
+
+func TestNetworkConnector_Close(t *testing.T) {
+	addr := startListener(t, func(conn net.Conn) {
+		defer conn.Close()
+		_, _ = io.Copy(io.Discard, conn)
+	})
+
+	c, err := connection.NewNetworkConnector(newTestConfig(addr))
+	if err != nil {
+		t.Fatalf("NewNetworkConnector: %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	if _, err := c.Write([]byte("x")); !errors.Is(err, connection.ErrConnectionClosed) {
+		t.Errorf("Write after Close error = %v; want %v", err, connection.ErrConnectionClosed)
+	}
+}
+
+func TestNewNetworkConnector_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *connection.NetworkConfig
+		wantErr error
+	}{
+		{"nil config", nil, nil},
+		{"empty address", connection.DefaultNetworkConfig(""), connection.ErrEmptyAddress},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := connection.NewNetworkConnector(tt.config)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v; want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("dial refused", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("listen: %v", err)
+		}
+		addr := ln.Addr().String()
+		_ = ln.Close()
+
+		if _, err := connection.NewNetworkConnector(newTestConfig(addr)); err == nil {
+			t.Error("expected dial error")
+		}
+	})
+}
+
+func TestNetworkConnector_DefaultPort(t *testing.T) {
+	c := &connection.NetworkConfig{Address: "127.0.0.1", DialTimeout: 100 * time.Millisecond}
+	// Nothing listens on 9100 in the test environment; only the resolved address matters
+	conn, err := connection.NewNetworkConnector(c)
+	if err == nil {
+		defer conn.Close()
+		if conn.Address() != "127.0.0.1:9100" {
+			t.Errorf("Address() = %q; want 127.0.0.1:9100", conn.Address())
+		}
+		return
+	}
+	if !bytes.Contains([]byte(err.Error()), []byte("127.0.0.1:9100")) {
+		t.Errorf("dial error %q does not mention default port", err)
+	}
+}
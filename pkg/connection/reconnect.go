@@ -0,0 +1,411 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Reconnect defaults
+const (
+	DefaultMaxRetries     = 5
+	DefaultInitialBackoff = 200 * time.Millisecond
+	DefaultMaxBackoff     = 5 * time.Second
+)
+
+var (
+	// ErrRetriesExhausted indicates that the connection could not be restored within the retry budget
+	ErrRetriesExhausted = errors.New("reconnect retries exhausted")
+	// ErrInterrupted indicates that the connection dropped after part of a write or job was sent;
+	// the rest is not sent on a new connection because it would start in the middle of a command
+	ErrInterrupted = errors.New("connection dropped during write")
+)
+
+// Interface compliance checks
+var (
+	_ ReadWriteConnector = (*ReconnectingConnector)(nil)
+	_ JobMarker          = (*ReconnectingConnector)(nil)
+)
+
+// ConnectorFactory opens a new connection to the printer
+type ConnectorFactory func() (Connector, error)
+
+// ReconnectConfig holds the retry policy and event hooks of a ReconnectingConnector
+type ReconnectConfig struct {
+	// MaxRetries bounds the reconnections attempted by a single Write or Read call
+	MaxRetries int
+	// InitialBackoff is the pause before the first reconnection; it doubles on each retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the pause between reconnections
+	MaxBackoff time.Duration
+	// OnConnect, when set, is called after every successful (re)connection
+	OnConnect func()
+	// OnDisconnect, when set, is called with the error that made a connection be dropped
+	OnDisconnect func(err error)
+}
+
+// DefaultReconnectConfig returns a retry policy suited to network printers that sleep or roam
+func DefaultReconnectConfig() *ReconnectConfig {
+	return &ReconnectConfig{
+		MaxRetries:     DefaultMaxRetries,
+		InitialBackoff: DefaultInitialBackoff,
+		MaxBackoff:     DefaultMaxBackoff,
+	}
+}
+
+// ReconnectingConnector decorates a connector opened through a factory: when a write or read
+// fails because the connection dropped, it closes the connection, opens a new one with
+// exponential backoff and retries.
+//
+// A write is only retried on a new connection while none of its bytes were accepted. Bytes
+// accepted by a connection have only reached a local buffer (the socket's, for network
+// printers), so there is no telling how much the printer received, and resuming on a new
+// connection could start in the middle of a command. A write that drops after sending part of
+// its data fails with ErrInterrupted instead. Between BeginJob and EndJob the whole job is the
+// unit: once any of its bytes were sent, a dropped connection fails the current write and the
+// rest of the job with ErrInterrupted, and the next job starts on a new connection. Callers
+// such as the job queue then print the job again from its start.
+type ReconnectingConnector struct {
+	factory ConnectorFactory
+	config  ReconnectConfig
+	canRead bool
+
+	mu     sync.Mutex // guards conn, gen and closed
+	conn   Connector  // nil while disconnected
+	gen    uint64     // incremented on every new connection
+	closed bool
+
+	dialMu sync.Mutex // serializes reconnections, which run without mu held
+
+	writeMu       sync.Mutex // guards the write side and the job state
+	writeDeadline deadline
+	inJob         bool   // between BeginJob and EndJob
+	jobGen        uint64 // connection that received the job's first bytes, 0 if none yet
+	jobErr        error  // ErrInterrupted once the job lost its connection
+
+	readMu       sync.Mutex
+	readDeadline deadline
+
+	acked      atomic.Int64
+	reconnects atomic.Int64
+}
+
+// NewReconnectingConnector opens the first connection through factory.
+// A nil config uses DefaultReconnectConfig.
+func NewReconnectingConnector(factory ConnectorFactory, config *ReconnectConfig) (*ReconnectingConnector, error) {
+	if factory == nil {
+		return nil, errors.New("connector factory cannot be nil")
+	}
+	if config == nil {
+		config = DefaultReconnectConfig()
+	}
+
+	conn, err := factory()
+	if err != nil {
+		return nil, fmt.Errorf("open printer connection: %w", err)
+	}
+	_, canRead := AsReadWriter(conn)
+
+	c := &ReconnectingConnector{
+		factory: factory,
+		config:  *config,
+		canRead: canRead,
+		conn:    conn,
+		gen:     1,
+	}
+	if c.config.OnConnect != nil {
+		c.config.OnConnect()
+	}
+	return c, nil
+}
+
+// Write sends data, reconnecting when the connection dropped before any of it was sent.
+// It fails with ErrInterrupted when the connection drops after part of data, or of the
+// current job, was sent.
+func (c *ReconnectingConnector) Write(data []byte) (int, error) {
+	return c.WriteContext(context.Background(), data)
+}
+
+// WriteContext is like Write but aborts when ctx is done, including the backoff pauses
+func (c *ReconnectingConnector) WriteContext(ctx context.Context, data []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.jobErr != nil {
+		return 0, c.jobErr
+	}
+
+	written := 0
+	err := c.do(ctx, func(conn Connector, gen uint64) (bool, error) {
+		// The job already sent bytes on a connection that has been replaced since
+		if c.inJob && c.jobGen != 0 && c.jobGen != gen {
+			return false, fmt.Errorf("%w: job continued on a new connection", ErrInterrupted)
+		}
+
+		n, err := writeTo(ctx, conn, data, c.writeDeadline.get())
+		written = n
+		c.acked.Add(int64(n))
+		if n > 0 && c.inJob && c.jobGen == 0 {
+			c.jobGen = gen
+		}
+		switch {
+		case err == nil:
+			return false, nil
+		case ctx.Err() != nil:
+			return false, err
+		case n > 0 || (c.inJob && c.jobGen != 0):
+			c.drop(gen, err)
+			return false, fmt.Errorf("%w after %d of %d bytes: %w", ErrInterrupted, n, len(data), err)
+		default:
+			return true, err
+		}
+	})
+	if c.inJob && errors.Is(err, ErrInterrupted) {
+		c.jobErr = err
+	}
+	return written, err
+}
+
+// BeginJob starts a job: from now until EndJob a dropped connection is only replaced while
+// none of the job's bytes were sent. The mark is forwarded to the current connection when it
+// records job boundaries.
+func (c *ReconnectingConnector) BeginJob(name string) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.inJob = true
+	c.jobGen = 0
+	c.jobErr = nil
+	return c.forwardJob(func(m JobMarker) error { return m.BeginJob(name) })
+}
+
+// EndJob ends the job started by BeginJob; the next write may open a new connection again
+func (c *ReconnectingConnector) EndJob() error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.inJob = false
+	c.jobGen = 0
+	c.jobErr = nil
+	return c.forwardJob(JobMarker.EndJob)
+}
+
+// Read receives data from the printer, reconnecting when the connection was dropped
+func (c *ReconnectingConnector) Read(buf []byte) (int, error) {
+	return c.ReadContext(context.Background(), buf)
+}
+
+// ReadContext is like Read but aborts when ctx is done
+func (c *ReconnectingConnector) ReadContext(ctx context.Context, buf []byte) (int, error) {
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+
+	n := 0
+	err := c.do(ctx, func(conn Connector, _ uint64) (bool, error) {
+		rw, ok := AsReadWriter(conn)
+		if !ok {
+			return false, ErrNotBidirectional
+		}
+		if err := rw.SetReadDeadline(c.readDeadline.get()); err != nil {
+			return false, err
+		}
+
+		var err error
+		n, err = rw.ReadContext(ctx, buf)
+		if err == nil || n > 0 {
+			return false, err
+		}
+		return ctx.Err() == nil && isDisconnect(err), err
+	})
+	return n, err
+}
+
+// SetReadDeadline sets an absolute deadline for subsequent reads, kept across reconnections
+func (c *ReconnectingConnector) SetReadDeadline(t time.Time) error {
+	c.readDeadline.set(t)
+	return nil
+}
+
+// SetWriteDeadline sets an absolute deadline for subsequent writes, kept across reconnections
+func (c *ReconnectingConnector) SetWriteDeadline(t time.Time) error {
+	c.writeDeadline.set(t)
+	return nil
+}
+
+// CanRead reports whether the connections opened by the factory can receive data from the printer
+func (c *ReconnectingConnector) CanRead() bool {
+	return c.canRead
+}
+
+// Acknowledged returns the number of bytes accepted by the connections since creation. Accepted
+// bytes have reached the connection's local buffer, not necessarily the printer.
+func (c *ReconnectingConnector) Acknowledged() int64 {
+	return c.acked.Load()
+}
+
+// Reconnects returns the number of connections opened after the first one
+func (c *ReconnectingConnector) Reconnects() int64 {
+	return c.reconnects.Load()
+}
+
+// Close closes the current connection; later calls fail with ErrConnectionClosed
+func (c *ReconnectingConnector) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	if c.conn == nil {
+		return nil
+	}
+	conn := c.conn
+	c.conn = nil
+	return conn.Close()
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+// do runs op on the current connection, identified by its generation. While op reports that
+// the connection failed, the connection is dropped and op is retried on a new one, with
+// exponential backoff, until MaxRetries reconnections have been attempted.
+func (c *ReconnectingConnector) do(ctx context.Context, op func(conn Connector, gen uint64) (retry bool, err error)) error {
+	for attempt := 0; ; attempt++ {
+		conn, gen, err := c.current()
+		if err == nil {
+			var retry bool
+			if retry, err = op(conn, gen); !retry {
+				return err
+			}
+			c.drop(gen, err)
+		} else if errors.Is(err, ErrConnectionClosed) {
+			return err
+		}
+
+		if attempt >= c.config.MaxRetries {
+			return fmt.Errorf("%w after %d attempts: %w", ErrRetriesExhausted, attempt+1, err)
+		}
+		if err := sleepContext(ctx, c.backoff(attempt)); err != nil {
+			return err
+		}
+	}
+}
+
+// current returns the open connection, opening a new one if the previous was dropped.
+// The factory runs without mu held, so Close is not held up by a slow dial.
+func (c *ReconnectingConnector) current() (Connector, uint64, error) {
+	if conn, gen, err := c.open(); conn != nil || err != nil {
+		return conn, gen, err
+	}
+
+	c.dialMu.Lock()
+	defer c.dialMu.Unlock()
+	// Another goroutine may have reconnected while this one waited
+	if conn, gen, err := c.open(); conn != nil || err != nil {
+		return conn, gen, err
+	}
+
+	conn, err := c.factory()
+	if err != nil {
+		return nil, 0, fmt.Errorf("reopen printer connection: %w", err)
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		_ = conn.Close()
+		return nil, 0, ErrConnectionClosed
+	}
+	c.conn = conn
+	c.gen++
+	gen := c.gen
+	c.mu.Unlock()
+
+	c.reconnects.Add(1)
+	if c.config.OnConnect != nil {
+		c.config.OnConnect()
+	}
+	return conn, gen, nil
+}
+
+// open returns the open connection, or a nil connection and error while disconnected
+func (c *ReconnectingConnector) open() (Connector, uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil, 0, ErrConnectionClosed
+	}
+	return c.conn, c.gen, nil
+}
+
+// forwardJob calls mark on the current connection when it records job boundaries
+func (c *ReconnectingConnector) forwardJob(mark func(JobMarker) error) error {
+	conn, _, err := c.open()
+	if err != nil {
+		return err
+	}
+	if m, ok := conn.(JobMarker); ok {
+		return mark(m)
+	}
+	return nil
+}
+
+// drop closes connection generation gen after it failed with err.
+// A connection already replaced by another goroutine is left alone.
+func (c *ReconnectingConnector) drop(gen uint64, err error) {
+	c.mu.Lock()
+	if c.conn == nil || c.gen != gen {
+		c.mu.Unlock()
+		return
+	}
+	conn := c.conn
+	c.conn = nil
+	c.mu.Unlock()
+
+	_ = conn.Close()
+	if c.config.OnDisconnect != nil {
+		c.config.OnDisconnect(err)
+	}
+}
+
+// backoff returns the pause before reconnection attempt+1
+func (c *ReconnectingConnector) backoff(attempt int) time.Duration {
+	d := c.config.InitialBackoff
+	for i := 0; i < attempt && d < c.config.MaxBackoff; i++ {
+		d *= 2
+	}
+	if c.config.MaxBackoff > 0 && d > c.config.MaxBackoff {
+		d = c.config.MaxBackoff
+	}
+	return d
+}
+
+// writeTo writes data to conn, honoring ctx and the write deadline when conn supports them
+func writeTo(ctx context.Context, conn Connector, data []byte, until time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	rw, ok := AsReadWriter(conn)
+	if !ok {
+		return conn.Write(data)
+	}
+	if err := rw.SetWriteDeadline(until); err != nil {
+		return 0, err
+	}
+	return rw.WriteContext(ctx, data)
+}
+
+// isDisconnect reports whether a read error means the connection was lost, as opposed to a timeout
+func isDisconnect(err error) bool {
+	return errors.Is(err, ErrConnectionClosed) || errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrClosedPipe) || errors.Is(err, net.ErrClosed) || errors.Is(err, os.ErrClosed)
+}
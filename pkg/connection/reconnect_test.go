@@ -0,0 +1,399 @@
+package connection_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adcondev/pos-printer/pkg/connection"
+)
+
+// flakyPrinter is an in-memory printer whose connections drop after accepting a set number of bytes
+type flakyPrinter struct {
+	mu       sync.Mutex
+	printed  bytes.Buffer
+	budgets  []int // bytes accepted by each successive connection (-1 = unlimited)
+	dialErrs int   // factory calls that fail before the next connection opens
+	opened   int
+}
+
+// factory opens the next flaky connection
+func (p *flakyPrinter) factory() (connection.Connector, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.dialErrs > 0 {
+		p.dialErrs--
+		return nil, errors.New("printer unreachable")
+	}
+	budget := -1
+	if p.opened < len(p.budgets) {
+		budget = p.budgets[p.opened]
+	}
+	p.opened++
+	return &flakyConn{printer: p, budget: budget}, nil
+}
+
+func (p *flakyPrinter) output() []byte {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]byte(nil), p.printed.Bytes()...)
+}
+
+// flakyConn is a write-only connection that accepts budget bytes and then fails
+type flakyConn struct {
+	printer *flakyPrinter
+	budget  int
+	closed  bool
+}
+
+func (c *flakyConn) Write(data []byte) (int, error) {
+	c.printer.mu.Lock()
+	defer c.printer.mu.Unlock()
+
+	if c.closed {
+		return 0, connection.ErrConnectionClosed
+	}
+	if c.budget < 0 || len(data) <= c.budget {
+		if c.budget >= 0 {
+			c.budget -= len(data)
+		}
+		c.printer.printed.Write(data)
+		return len(data), nil
+	}
+
+	n := c.budget
+	c.printer.printed.Write(data[:n])
+	c.budget = 0
+	return n, io.ErrClosedPipe
+}
+
+func (c *flakyConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func fastReconnectConfig() *connection.ReconnectConfig {
+	return &connection.ReconnectConfig{
+		MaxRetries:     3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+}
+
+func TestReconnectingConnector_InterruptedWrite(t *testing.T) {
+	printer := &flakyPrinter{budgets: []int{5, -1}}
+
+	var connects, disconnects int
+	cfg := fastReconnectConfig()
+	cfg.OnConnect = func() { connects++ }
+	cfg.OnDisconnect = func(error) { disconnects++ }
+
+	conn, err := connection.NewReconnectingConnector(printer.factory, cfg)
+	if err != nil {
+		t.Fatalf("NewReconnectingConnector() error = %v", err)
+	}
+	defer conn.Close()
+
+	// The rest of the write is not resumed on a new connection, where it would start mid-command
+	n, err := conn.Write([]byte("RECEIPT #0042\n"))
+	if !errors.Is(err, connection.ErrInterrupted) || !errors.Is(err, io.ErrClosedPipe) {
+		t.Fatalf("Write() error = %v, want ErrInterrupted wrapping the failure", err)
+	}
+	if n != 5 || string(printer.output()) != "RECEI" {
+		t.Errorf("Write() = %d, printed %q; want 5, %q", n, printer.output(), "RECEI")
+	}
+
+	// The next write starts on a new connection
+	if _, err := conn.Write([]byte("next")); err != nil {
+		t.Fatalf("Write() after interruption error = %v", err)
+	}
+	if got := string(printer.output()); got != "RECEInext" {
+		t.Errorf("printed %q, want %q", got, "RECEInext")
+	}
+	if conn.Acknowledged() != 9 {
+		t.Errorf("Acknowledged() = %d, want 9", conn.Acknowledged())
+	}
+	if conn.Reconnects() != 1 || connects != 2 || disconnects != 1 {
+		t.Errorf("Reconnects() = %d, connects = %d, disconnects = %d; want 1, 2, 1",
+			conn.Reconnects(), connects, disconnects)
+	}
+}
+
+func TestReconnectingConnector_Job(t *testing.T) {
+	tests := []struct {
+		name    string
+		budgets []int
+		writes  []string
+		wantErr []error
+		printed string
+	}{
+		{
+			name:    "drop before the job starts reconnects",
+			budgets: []int{0, -1},
+			writes:  []string{"\x1b@", "TOTAL"},
+			wantErr: []error{nil, nil},
+			printed: "\x1b@TOTAL",
+		},
+		{
+			name:    "drop after the job started fails the rest of it",
+			budgets: []int{4, -1},
+			writes:  []string{"\x1b@", "TOTAL", "\n"},
+			wantErr: []error{nil, connection.ErrInterrupted, connection.ErrInterrupted},
+			printed: "\x1b@TO",
+		},
+		{
+			name:    "drop between writes of the job fails the rest of it",
+			budgets: []int{2, -1},
+			writes:  []string{"\x1b@", "TOTAL"},
+			wantErr: []error{nil, connection.ErrInterrupted},
+			printed: "\x1b@",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			printer := &flakyPrinter{budgets: tt.budgets}
+			conn, err := connection.NewReconnectingConnector(printer.factory, fastReconnectConfig())
+			if err != nil {
+				t.Fatalf("NewReconnectingConnector() error = %v", err)
+			}
+			defer conn.Close()
+
+			if err := conn.BeginJob("receipt"); err != nil {
+				t.Fatalf("BeginJob() error = %v", err)
+			}
+			for i, w := range tt.writes {
+				_, err := conn.Write([]byte(w))
+				if tt.wantErr[i] == nil && err != nil {
+					t.Fatalf("Write(%q) error = %v", w, err)
+				}
+				if tt.wantErr[i] != nil && !errors.Is(err, tt.wantErr[i]) {
+					t.Fatalf("Write(%q) error = %v, want %v", w, err, tt.wantErr[i])
+				}
+			}
+			if err := conn.EndJob(); err != nil {
+				t.Fatalf("EndJob() error = %v", err)
+			}
+			if got := string(printer.output()); got != tt.printed {
+				t.Errorf("printed %q, want %q", got, tt.printed)
+			}
+
+			// The next job is printed whole
+			if err := conn.BeginJob("next"); err != nil {
+				t.Fatalf("BeginJob() error = %v", err)
+			}
+			if _, err := conn.Write([]byte("NEXT")); err != nil {
+				t.Fatalf("Write() in next job error = %v", err)
+			}
+			if err := conn.EndJob(); err != nil {
+				t.Fatalf("EndJob() error = %v", err)
+			}
+			if got := string(printer.output()); got != tt.printed+"NEXT" {
+				t.Errorf("printed %q, want %q", got, tt.printed+"NEXT")
+			}
+		})
+	}
+}
+
+func TestReconnectingConnector_RetriesFactory(t *testing.T) {
+	printer := &flakyPrinter{budgets: []int{0, -1}}
+
+	conn, err := connection.NewReconnectingConnector(printer.factory, fastReconnectConfig())
+	if err != nil {
+		t.Fatalf("NewReconnectingConnector() error = %v", err)
+	}
+	defer conn.Close()
+
+	// The printer is asleep for two dial attempts after the first connection drops
+	printer.mu.Lock()
+	printer.dialErrs = 2
+	printer.mu.Unlock()
+
+	if _, err := conn.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := printer.output(); string(got) != "hello" {
+		t.Errorf("printed %q, want %q", got, "hello")
+	}
+}
+
+func TestReconnectingConnector_RetriesExhausted(t *testing.T) {
+	printer := &flakyPrinter{budgets: []int{0, 0, 0, 0, 0}}
+
+	conn, err := connection.NewReconnectingConnector(printer.factory, fastReconnectConfig())
+	if err != nil {
+		t.Fatalf("NewReconnectingConnector() error = %v", err)
+	}
+	defer conn.Close()
+
+	n, err := conn.Write([]byte("abcdefghij"))
+	if !errors.Is(err, connection.ErrRetriesExhausted) {
+		t.Fatalf("Write() error = %v, want ErrRetriesExhausted", err)
+	}
+	if !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("Write() error = %v, want it to wrap the last failure", err)
+	}
+	// The first connection and 3 retries
+	if n != 0 || printer.opened != 4 {
+		t.Errorf("Write() = %d with %d connections; want 0, 4", n, printer.opened)
+	}
+}
+
+func TestReconnectingConnector_Canceled(t *testing.T) {
+	printer := &flakyPrinter{budgets: []int{0}}
+	cfg := fastReconnectConfig()
+	cfg.InitialBackoff = time.Hour
+	cfg.MaxBackoff = time.Hour
+
+	conn, err := connection.NewReconnectingConnector(printer.factory, cfg)
+	if err != nil {
+		t.Fatalf("NewReconnectingConnector() error = %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := conn.WriteContext(ctx, []byte("x")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WriteContext() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestReconnectingConnector_Close(t *testing.T) {
+	printer := &flakyPrinter{}
+
+	conn, err := connection.NewReconnectingConnector(printer.factory, fastReconnectConfig())
+	if err != nil {
+		t.Fatalf("NewReconnectingConnector() error = %v", err)
+	}
+	if err := conn.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := conn.Write([]byte("late")); !errors.Is(err, connection.ErrConnectionClosed) {
+		t.Errorf("Write() after Close error = %v, want ErrConnectionClosed", err)
+	}
+	if printer.opened != 1 {
+		t.Errorf("factory opened %d connections, want 1", printer.opened)
+	}
+}
+
+func TestReconnectingConnector_CloseWhileDialing(t *testing.T) {
+	dialing := make(chan struct{})
+	release := make(chan struct{})
+	printer := &flakyPrinter{budgets: []int{0}}
+	var redialed *flakyConn
+	calls := 0
+	factory := func() (connection.Connector, error) {
+		calls++
+		if calls == 1 {
+			return printer.factory()
+		}
+		close(dialing)
+		<-release
+		c, err := printer.factory()
+		redialed = c.(*flakyConn)
+		return c, err
+	}
+
+	conn, err := connection.NewReconnectingConnector(factory, fastReconnectConfig())
+	if err != nil {
+		t.Fatalf("NewReconnectingConnector() error = %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := conn.Write([]byte("x"))
+		done <- err
+	}()
+	<-dialing
+
+	// Close does not wait for the dial in progress
+	closed := make(chan error, 1)
+	go func() { closed <- conn.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close() blocked by the dial in progress")
+	}
+	close(release)
+
+	if err := <-done; !errors.Is(err, connection.ErrConnectionClosed) {
+		t.Errorf("Write() error = %v, want ErrConnectionClosed", err)
+	}
+	if !redialed.closed {
+		t.Error("connection dialed during Close was leaked")
+	}
+}
+
+func TestReconnectingConnector_WriteOnly(t *testing.T) {
+	printer := &flakyPrinter{}
+
+	conn, err := connection.NewReconnectingConnector(printer.factory, fastReconnectConfig())
+	if err != nil {
+		t.Fatalf("NewReconnectingConnector() error = %v", err)
+	}
+	defer conn.Close()
+
+	if _, ok := connection.AsReadWriter(conn); ok {
+		t.Error("AsReadWriter() = true for a wrapper around write-only connections")
+	}
+}
+
+func TestNewReconnectingConnector_FactoryError(t *testing.T) {
+	printer := &flakyPrinter{dialErrs: 1}
+
+	if _, err := connection.NewReconnectingConnector(printer.factory, nil); err == nil {
+		t.Error("NewReconnectingConnector() expected error when the first connection fails")
+	}
+}
+
+func TestReconnectingConnector_ReadAfterPrinterRestart(t *testing.T) {
+	var accepted sync.WaitGroup
+	accepted.Add(1)
+	first := true
+	var mu sync.Mutex
+	addr := startListener(t, func(conn net.Conn) {
+		mu.Lock()
+		restart := first
+		first = false
+		mu.Unlock()
+
+		defer conn.Close()
+		if restart {
+			// The printer goes to sleep: the first connection is dropped
+			accepted.Done()
+			return
+		}
+		_, _ = conn.Write([]byte{0x16})
+		time.Sleep(500 * time.Millisecond)
+	})
+
+	conn, err := connection.NewReconnectingConnector(func() (connection.Connector, error) {
+		return connection.NewNetworkConnector(newTestConfig(addr))
+	}, fastReconnectConfig())
+	if err != nil {
+		t.Fatalf("NewReconnectingConnector() error = %v", err)
+	}
+	defer conn.Close()
+	accepted.Wait()
+
+	buf := make([]byte, 8)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if n != 1 || buf[0] != 0x16 {
+		t.Errorf("Read() = %#v, want [0x16]", buf[:n])
+	}
+	if conn.Reconnects() != 1 {
+		t.Errorf("Reconnects() = %d, want 1", conn.Reconnects())
+	}
+}
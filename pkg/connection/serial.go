@@ -0,0 +1,144 @@
+package connection
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Parity represents the parity bit mode of a serial line
+type Parity byte
+
+const (
+	// ParityNone disables the parity bit
+	ParityNone Parity = 'N'
+	// ParityOdd enables odd parity
+	ParityOdd Parity = 'O'
+	// ParityEven enables even parity
+	ParityEven Parity = 'E'
+)
+
+// FlowControl represents the handshake used to keep the printer buffer from overrunning
+type FlowControl byte
+
+const (
+	// FlowNone disables flow control
+	FlowNone FlowControl = iota
+	// FlowXonXoff enables software flow control (XON/XOFF)
+	FlowXonXoff
+	// FlowRTSCTS enables hardware flow control on the RTS/CTS lines
+	FlowRTSCTS
+	// FlowDTRDSR enables hardware flow control on the DTR/DSR lines
+	FlowDTRDSR
+)
+
+// Serial defaults
+const (
+	// DefaultBaudRate is the factory baud rate of most ESC/POS serial printers
+	DefaultBaudRate = 9600
+	// DefaultSerialChunkSize is the number of bytes written before waiting for the line to drain
+	DefaultSerialChunkSize = 1024
+)
+
+// supportedBaudRates lists the baud rates accepted by SerialConfig
+var supportedBaudRates = []int{1200, 2400, 4800, 9600, 19200, 38400, 57600, 115200, 230400}
+
+var (
+	// ErrEmptyDevice indicates that no device path was provided
+	ErrEmptyDevice = errors.New("device path cannot be empty")
+	// ErrBaudRate indicates an unsupported baud rate
+	ErrBaudRate = errors.New("invalid baud rate (try 1200-230400)")
+	// ErrDataBits indicates an invalid number of data bits
+	ErrDataBits = errors.New("invalid data bits (try 5-8)")
+	// ErrStopBits indicates an invalid number of stop bits
+	ErrStopBits = errors.New("invalid stop bits (try 1 or 2)")
+	// ErrParity indicates an invalid parity mode
+	ErrParity = errors.New("invalid parity (try 'N', 'O' or 'E')")
+	// ErrFlowControl indicates an invalid flow control mode
+	ErrFlowControl = errors.New("invalid flow control mode")
+	// ErrFlowTimeout indicates that the printer did not become ready in time
+	ErrFlowTimeout = errors.New("printer not ready (flow control timeout)")
+)
+
+// SerialConfig holds the line settings of a serial (RS-232 / USB-CDC) printer
+type SerialConfig struct {
+	// Device is the path of the tty, e.g. /dev/ttyS0 or /dev/ttyUSB0
+	Device string
+	// BaudRate is the line speed in bits per second
+	BaudRate int
+	// DataBits is the character size (5-8)
+	DataBits int
+	// Parity is the parity mode
+	Parity Parity
+	// StopBits is the number of stop bits (1 or 2)
+	StopBits int
+	// FlowControl is the handshake mode
+	FlowControl FlowControl
+
+	// ChunkSize is the number of bytes written at once; 0 writes everything in a single call
+	ChunkSize int
+	// ChunkDelay is an extra pause after each chunk for printers with very small buffers
+	ChunkDelay time.Duration
+	// WriteTimeout bounds each Write call, including flow control waits (0 disables the deadline)
+	WriteTimeout time.Duration
+	// ReadTimeout bounds each Read call (0 disables the deadline)
+	ReadTimeout time.Duration
+}
+
+// DefaultSerialConfig returns a 9600 8N1 configuration without flow control
+func DefaultSerialConfig(device string) *SerialConfig {
+	return &SerialConfig{
+		Device:       device,
+		BaudRate:     DefaultBaudRate,
+		DataBits:     8,
+		Parity:       ParityNone,
+		StopBits:     1,
+		FlowControl:  FlowNone,
+		ChunkSize:    DefaultSerialChunkSize,
+		WriteTimeout: DefaultWriteTimeout,
+		ReadTimeout:  DefaultReadTimeout,
+	}
+}
+
+// Validate checks that the configuration describes a usable serial line
+func (c *SerialConfig) Validate() error {
+	if c.Device == "" {
+		return ErrEmptyDevice
+	}
+	if !isSupportedBaudRate(c.BaudRate) {
+		return fmt.Errorf("%w: %d", ErrBaudRate, c.BaudRate)
+	}
+	if c.DataBits < 5 || c.DataBits > 8 {
+		return fmt.Errorf("%w: %d", ErrDataBits, c.DataBits)
+	}
+	if c.StopBits != 1 && c.StopBits != 2 {
+		return fmt.Errorf("%w: %d", ErrStopBits, c.StopBits)
+	}
+	switch c.Parity {
+	case ParityNone, ParityOdd, ParityEven:
+	default:
+		return fmt.Errorf("%w: %q", ErrParity, c.Parity)
+	}
+	if c.FlowControl > FlowDTRDSR {
+		return fmt.Errorf("%w: %d", ErrFlowControl, c.FlowControl)
+	}
+	return nil
+}
+
+// byteDuration returns how long the line takes to transmit n bytes at the configured framing
+func (c *SerialConfig) byteDuration(n int) time.Duration {
+	bits := 1 + c.DataBits + c.StopBits // start + data + stop
+	if c.Parity != ParityNone {
+		bits++
+	}
+	return time.Duration(n*bits) * time.Second / time.Duration(c.BaudRate)
+}
+
+func isSupportedBaudRate(rate int) bool {
+	for _, r := range supportedBaudRates {
+		if r == rate {
+			return true
+		}
+	}
+	return false
+}
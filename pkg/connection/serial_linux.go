@@ -0,0 +1,318 @@
+//go:build linux
+
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// flowPollInterval is the pause between modem line checks while waiting for the printer
+const flowPollInterval = 2 * time.Millisecond
+
+// baudRates maps line speeds to their termios constants
+var baudRates = map[int]uint32{
+	1200:   unix.B1200,
+	2400:   unix.B2400,
+	4800:   unix.B4800,
+	9600:   unix.B9600,
+	19200:  unix.B19200,
+	38400:  unix.B38400,
+	57600:  unix.B57600,
+	115200: unix.B115200,
+	230400: unix.B230400,
+}
+
+// dataBits maps character sizes to their termios constants
+var dataBits = map[int]uint32{
+	5: unix.CS5,
+	6: unix.CS6,
+	7: unix.CS7,
+	8: unix.CS8,
+}
+
+// Interface compliance check
+var _ ReadWriteConnector = (*SerialConnector)(nil)
+
+// SerialConnector implements a connector for printers attached to a tty (RS-232 or USB-CDC)
+type SerialConnector struct {
+	config SerialConfig
+	file   *os.File
+	closed atomic.Bool
+
+	writeMu       sync.Mutex
+	writeDeadline deadline
+
+	readMu       sync.Mutex
+	readDeadline deadline
+}
+
+// NewSerialConnector opens and configures the serial device described by config
+func NewSerialConnector(config *SerialConfig) (*SerialConnector, error) {
+	if config == nil {
+		return nil, errors.New("serial config cannot be nil")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	// O_NONBLOCK lets the runtime poller handle the fd, so read/write deadlines work
+	file, err := os.OpenFile(config.Device, os.O_RDWR|syscall.O_NOCTTY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, fmt.Errorf("open serial device %s: %w", config.Device, err)
+	}
+
+	c := &SerialConnector{
+		config: *config,
+		file:   file,
+	}
+	if err := c.configure(); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("configure serial device %s: %w", config.Device, err)
+	}
+
+	return c, nil
+}
+
+// Write sends data to the printer in paced chunks so its receive buffer does not overrun
+func (c *SerialConnector) Write(data []byte) (int, error) {
+	return c.WriteContext(context.Background(), data)
+}
+
+// WriteContext is like Write but aborts between or inside chunks when ctx is done
+func (c *SerialConnector) WriteContext(ctx context.Context, data []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closed.Load() {
+		return 0, ErrConnectionClosed
+	}
+
+	until := effectiveDeadline(ctx, c.writeDeadline.get(), c.config.WriteTimeout)
+	chunkSize := c.config.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = len(data)
+	}
+
+	written := 0
+	for written < len(data) {
+		end := written + chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+
+		if c.config.FlowControl == FlowDTRDSR {
+			if err := c.waitForDSR(ctx, until); err != nil {
+				return written, err
+			}
+		}
+
+		chunk := data[written:end]
+		n, err := doWithContext(ctx, until, c.file.SetWriteDeadline, func() (int, error) {
+			return c.file.Write(chunk)
+		})
+		written += n
+		if err != nil {
+			return written, fmt.Errorf("write to serial device: %w", err)
+		}
+
+		// Only pace between chunks; the last one drains on its own
+		if written < len(data) {
+			if err := c.drain(ctx, n, until); err != nil {
+				return written, err
+			}
+			if c.config.ChunkDelay > 0 {
+				if err := sleepContext(ctx, c.config.ChunkDelay); err != nil {
+					return written, err
+				}
+			}
+		}
+	}
+
+	return written, nil
+}
+
+// Read receives status bytes sent back by the printer
+func (c *SerialConnector) Read(buf []byte) (int, error) {
+	return c.ReadContext(context.Background(), buf)
+}
+
+// ReadContext is like Read but aborts when ctx is done
+func (c *SerialConnector) ReadContext(ctx context.Context, buf []byte) (int, error) {
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+
+	if c.closed.Load() {
+		return 0, ErrConnectionClosed
+	}
+
+	until := effectiveDeadline(ctx, c.readDeadline.get(), c.config.ReadTimeout)
+	n, err := doWithContext(ctx, until, c.file.SetReadDeadline, func() (int, error) {
+		return c.file.Read(buf)
+	})
+	if err != nil {
+		return n, fmt.Errorf("read from serial device: %w", err)
+	}
+	return n, nil
+}
+
+// SetReadDeadline sets an absolute deadline for subsequent reads (zero restores ReadTimeout)
+func (c *SerialConnector) SetReadDeadline(t time.Time) error {
+	c.readDeadline.set(t)
+	return nil
+}
+
+// SetWriteDeadline sets an absolute deadline for subsequent writes (zero restores WriteTimeout)
+func (c *SerialConnector) SetWriteDeadline(t time.Time) error {
+	c.writeDeadline.set(t)
+	return nil
+}
+
+// Close closes the serial device
+func (c *SerialConnector) Close() error {
+	if c.closed.Swap(true) {
+		return nil
+	}
+	if err := c.file.Close(); err != nil {
+		return fmt.Errorf("close serial device: %w", err)
+	}
+	return nil
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+// configure applies the line settings through termios and raises the modem control lines.
+// The speed goes in the CBAUD bits of c_cflag, whose mask differs between architectures.
+func (c *SerialConnector) configure() error {
+	var tio *unix.Termios
+	err := c.control(func(fd int) (err error) {
+		tio, err = unix.IoctlGetTermios(fd, unix.TCGETS)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("get termios: %w", err)
+	}
+
+	// Raw mode: no echo, no line discipline processing, no output post-processing
+	tio.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP |
+		unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON | unix.IXOFF | unix.IXANY
+	tio.Oflag &^= unix.OPOST
+	tio.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+
+	tio.Cflag &^= unix.CBAUD | unix.CSIZE | unix.CSTOPB | unix.PARENB | unix.PARODD | unix.CRTSCTS
+	tio.Cflag |= baudRates[c.config.BaudRate] | dataBits[c.config.DataBits] | unix.CREAD | unix.CLOCAL
+
+	if c.config.StopBits == 2 {
+		tio.Cflag |= unix.CSTOPB
+	}
+	switch c.config.Parity {
+	case ParityOdd:
+		tio.Cflag |= unix.PARENB | unix.PARODD
+	case ParityEven:
+		tio.Cflag |= unix.PARENB
+	}
+
+	switch c.config.FlowControl {
+	case FlowXonXoff:
+		tio.Iflag |= unix.IXON | unix.IXOFF
+	case FlowRTSCTS:
+		tio.Cflag |= unix.CRTSCTS
+	}
+
+	// Return as soon as at least one byte is available
+	tio.Cc[unix.VMIN] = 1
+	tio.Cc[unix.VTIME] = 0
+
+	err = c.control(func(fd int) error {
+		return unix.IoctlSetTermios(fd, unix.TCSETS, tio)
+	})
+	if err != nil {
+		return fmt.Errorf("set termios: %w", err)
+	}
+
+	if c.config.FlowControl == FlowRTSCTS || c.config.FlowControl == FlowDTRDSR {
+		err := c.control(func(fd int) error {
+			return unix.IoctlSetPointerInt(fd, unix.TIOCMBIS, unix.TIOCM_DTR|unix.TIOCM_RTS)
+		})
+		if err != nil {
+			return fmt.Errorf("raise DTR/RTS: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// drain waits until the kernel output queue is empty. Devices that cannot report
+// their queue (e.g. some USB adapters) fall back to the theoretical line time of n bytes.
+func (c *SerialConnector) drain(ctx context.Context, n int, until time.Time) error {
+	for {
+		var queued int
+		err := c.control(func(fd int) (err error) {
+			queued, err = unix.IoctlGetInt(fd, unix.TIOCOUTQ)
+			return err
+		})
+		if err != nil {
+			return sleepContext(ctx, c.config.byteDuration(n))
+		}
+		if queued == 0 {
+			return nil
+		}
+		if expired(until) {
+			return fmt.Errorf("%w: %d bytes still queued", ErrFlowTimeout, queued)
+		}
+		if err := sleepContext(ctx, c.config.byteDuration(queued)); err != nil {
+			return err
+		}
+	}
+}
+
+// waitForDSR blocks until the printer asserts DSR. The kernel has no DTR/DSR
+// handshake, so the line is polled before each chunk.
+func (c *SerialConnector) waitForDSR(ctx context.Context, until time.Time) error {
+	for {
+		var lines int
+		err := c.control(func(fd int) (err error) {
+			lines, err = unix.IoctlGetInt(fd, unix.TIOCMGET)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("read modem lines: %w", err)
+		}
+		if lines&unix.TIOCM_DSR != 0 {
+			return nil
+		}
+		if expired(until) {
+			return fmt.Errorf("%w: DSR not asserted", ErrFlowTimeout)
+		}
+		if err := sleepContext(ctx, flowPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// control runs fn with the underlying file descriptor
+func (c *SerialConnector) control(fn func(fd int) error) error {
+	raw, err := c.file.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var fnErr error
+	err = raw.Control(func(fd uintptr) {
+		fnErr = fn(int(fd))
+	})
+	if err != nil {
+		return err
+	}
+	return fnErr
+}
@@ -0,0 +1,194 @@
+//go:build linux
+
+package connection_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/adcondev/pos-printer/pkg/connection"
+)
+
+// openPTY allocates a pseudo-terminal pair. The master plays the printer; the slave path is handed to the connector.
+func openPTY(t *testing.T) (master *os.File, slave string) {
+	t.Helper()
+
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("pty not available: %v", err)
+	}
+	t.Cleanup(func() { _ = master.Close() })
+
+	var unlock int32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, master.Fd(), syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); errno != 0 { //nolint:gosec
+		t.Skipf("unlock pty: %v", errno)
+	}
+	var n uint32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, master.Fd(), syscall.TIOCGPTN, uintptr(unsafe.Pointer(&n))); errno != 0 { //nolint:gosec
+		t.Skipf("get pty number: %v", errno)
+	}
+
+	slave = fmt.Sprintf("/dev/pts/%d", n)
+	if _, err := os.Stat(slave); err != nil {
+		t.Skipf("pty slave not available: %v", err)
+	}
+	return master, slave
+}
+
+func newSerialTestConfig(device string) *connection.SerialConfig {
+	cfg := connection.DefaultSerialConfig(device)
+	cfg.BaudRate = 115200
+	cfg.WriteTimeout = 2 * time.Second
+	cfg.ReadTimeout = 200 * time.Millisecond
+	return cfg
+}
+
+func TestSerialConnector_Write(t *testing.T) {
+	master, slave := openPTY(t)
+
+	tests := []struct {
+		name   string
+		config func(*connection.SerialConfig)
+	}{
+		{"8N1 no flow control", func(*connection.SerialConfig) {}},
+		{"7E2 xon/xoff", func(c *connection.SerialConfig) {
+			c.DataBits = 7
+			c.Parity = connection.ParityEven
+			c.StopBits = 2
+			c.FlowControl = connection.FlowXonXoff
+		}},
+		{"rts/cts", func(c *connection.SerialConfig) {
+			c.FlowControl = connection.FlowRTSCTS
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newSerialTestConfig(slave)
+			tt.config(cfg)
+
+			c, err := connection.NewSerialConnector(cfg)
+			if err != nil {
+				if errors.Is(err, syscall.ENOTTY) || errors.Is(err, syscall.EINVAL) {
+					t.Skipf("pty does not support this setting: %v", err)
+				}
+				t.Fatalf("NewSerialConnector: %v", err)
+			}
+			defer c.Close()
+
+			payload := []byte{0x1B, '@', 0x0A, 0x0D}
+			n, err := c.Write(payload)
+			if err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			if n != len(payload) {
+				t.Errorf("Write returned %d; want %d", n, len(payload))
+			}
+
+			got := make([]byte, len(payload))
+			if _, err := io.ReadFull(master, got); err != nil {
+				t.Fatalf("read master: %v", err)
+			}
+			// Raw mode must pass control bytes untouched (no CR/LF translation)
+			if !bytes.Equal(got, payload) {
+				t.Errorf("printer received %#v; want %#v", got, payload)
+			}
+		})
+	}
+}
+
+func TestSerialConnector_ChunkedWrite(t *testing.T) {
+	master, slave := openPTY(t)
+
+	cfg := newSerialTestConfig(slave)
+	cfg.ChunkSize = 64
+	cfg.ChunkDelay = time.Millisecond
+
+	c, err := connection.NewSerialConnector(cfg)
+	if err != nil {
+		t.Fatalf("NewSerialConnector: %v", err)
+	}
+	defer c.Close()
+
+	payload := bytes.Repeat([]byte{0xAA, 0x55}, 1000)
+	received := make(chan []byte, 1)
+	go func() {
+		got := make([]byte, len(payload))
+		_, _ = io.ReadFull(master, got)
+		received <- got
+	}()
+
+	n, err := c.Write(payload)
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if n != len(payload) {
+		t.Errorf("Write returned %d; want %d", n, len(payload))
+	}
+
+	select {
+	case got := <-received:
+		if !bytes.Equal(got, payload) {
+			t.Error("printer received corrupted payload")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for payload")
+	}
+}
+
+func TestSerialConnector_Read(t *testing.T) {
+	master, slave := openPTY(t)
+
+	c, err := connection.NewSerialConnector(newSerialTestConfig(slave))
+	if err != nil {
+		t.Fatalf("NewSerialConnector: %v", err)
+	}
+	defer c.Close()
+
+	if _, err := master.Write([]byte{0x12}); err != nil {
+		t.Fatalf("write master: %v", err)
+	}
+
+	buf := make([]byte, 4)
+	n, err := c.Read(buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if n != 1 || buf[0] != 0x12 {
+		t.Errorf("Read = %#v; want [0x12]", buf[:n])
+	}
+
+	// Nothing else pending: the read deadline must fire
+	if _, err := c.Read(buf); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("Read error = %v; want %v", err, os.ErrDeadlineExceeded)
+	}
+}
+
+func TestSerialConnector_Close(t *testing.T) {
+	_, slave := openPTY(t)
+
+	c, err := connection.NewSerialConnector(newSerialTestConfig(slave))
+	if err != nil {
+		t.Fatalf("NewSerialConnector: %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := c.Write([]byte("x")); !errors.Is(err, connection.ErrConnectionClosed) {
+		t.Errorf("Write after Close error = %v; want %v", err, connection.ErrConnectionClosed)
+	}
+}
+
+func TestNewSerialConnector_OpenError(t *testing.T) {
+	_, err := connection.NewSerialConnector(connection.DefaultSerialConfig("/dev/does-not-exist"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("error = %v; want %v", err, os.ErrNotExist)
+	}
+}
@@ -0,0 +1,33 @@
+//go:build !linux
+
+package connection
+
+import (
+	"errors"
+)
+
+// errSerialUnsupported is returned by the serial connector on non-Linux systems
+var errSerialUnsupported = errors.New("SerialConnector no está disponible en este sistema operativo")
+
+// SerialConnector es un stub para sistemas no-Linux
+type SerialConnector struct{}
+
+// NewSerialConnector devuelve un error en sistemas no-Linux
+func NewSerialConnector(_ *SerialConfig) (*SerialConnector, error) {
+	return nil, errSerialUnsupported
+}
+
+// Write implementación para sistemas no-Linux
+func (c *SerialConnector) Write(_ []byte) (int, error) {
+	return 0, errSerialUnsupported
+}
+
+// Read implementación para sistemas no-Linux
+func (c *SerialConnector) Read(_ []byte) (int, error) {
+	return 0, errSerialUnsupported
+}
+
+// Close implementación para sistemas no-Linux
+func (c *SerialConnector) Close() error {
+	return errSerialUnsupported
+}
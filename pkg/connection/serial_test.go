@@ -0,0 +1,46 @@
+package connection_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adcondev/pos-printer/pkg/connection"
+)
+
+func TestSerialConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*connection.SerialConfig)
+		wantErr error
+	}{
+		{"default config", func(*connection.SerialConfig) {}, nil},
+		{"115200 7O2 dtr/dsr", func(c *connection.SerialConfig) {
+			c.BaudRate = 115200
+			c.DataBits = 7
+			c.Parity = connection.ParityOdd
+			c.StopBits = 2
+			c.FlowControl = connection.FlowDTRDSR
+		}, nil},
+		{"empty device", func(c *connection.SerialConfig) { c.Device = "" }, connection.ErrEmptyDevice},
+		{"invalid baud rate", func(c *connection.SerialConfig) { c.BaudRate = 9601 }, connection.ErrBaudRate},
+		{"zero baud rate", func(c *connection.SerialConfig) { c.BaudRate = 0 }, connection.ErrBaudRate},
+		{"data bits 4", func(c *connection.SerialConfig) { c.DataBits = 4 }, connection.ErrDataBits},
+		{"data bits 9", func(c *connection.SerialConfig) { c.DataBits = 9 }, connection.ErrDataBits},
+		{"stop bits 0", func(c *connection.SerialConfig) { c.StopBits = 0 }, connection.ErrStopBits},
+		{"stop bits 3", func(c *connection.SerialConfig) { c.StopBits = 3 }, connection.ErrStopBits},
+		{"invalid parity", func(c *connection.SerialConfig) { c.Parity = 'M' }, connection.ErrParity},
+		{"invalid flow control", func(c *connection.SerialConfig) { c.FlowControl = 9 }, connection.ErrFlowControl},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := connection.DefaultSerialConfig("/dev/ttyS0")
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v; want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
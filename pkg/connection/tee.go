@@ -0,0 +1,158 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Interface compliance checks
+var (
+	_ ReadWriteConnector = (*TeeConnector)(nil)
+	_ JobMarker          = (*TeeConnector)(nil)
+)
+
+// TeeConnector sends everything to a primary connector and mirrors the bytes it accepted to
+// a second connector, such as a FileConnector recording production printing.
+//
+// The mirror never affects printing: its errors are kept for MirrorErr instead of being
+// returned, and reads and deadlines only concern the primary connector.
+type TeeConnector struct {
+	primary Connector
+	mirror  Connector
+
+	mu        sync.Mutex // serializes mirror writes and guards mirrorErr
+	mirrorErr error
+}
+
+// NewTeeConnector mirrors the writes of primary to mirror
+func NewTeeConnector(primary, mirror Connector) (*TeeConnector, error) {
+	if primary == nil || mirror == nil {
+		return nil, errors.New("tee connectors cannot be nil")
+	}
+	return &TeeConnector{primary: primary, mirror: mirror}, nil
+}
+
+// Write sends data to the primary connector and mirrors the accepted bytes
+func (t *TeeConnector) Write(data []byte) (int, error) {
+	n, err := t.primary.Write(data)
+	t.mirrorWrite(data[:n])
+	return n, err
+}
+
+// WriteContext is like Write but aborts when ctx is done; the primary must be bidirectional
+// for ctx to interrupt a blocked write
+func (t *TeeConnector) WriteContext(ctx context.Context, data []byte) (int, error) {
+	rw, ok := AsReadWriter(t.primary)
+	if !ok {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		return t.Write(data)
+	}
+	n, err := rw.WriteContext(ctx, data)
+	t.mirrorWrite(data[:n])
+	return n, err
+}
+
+// Read receives data from the primary connector
+func (t *TeeConnector) Read(buf []byte) (int, error) {
+	return t.ReadContext(context.Background(), buf)
+}
+
+// ReadContext receives data from the primary connector; received bytes are not mirrored
+func (t *TeeConnector) ReadContext(ctx context.Context, buf []byte) (int, error) {
+	rw, ok := AsReadWriter(t.primary)
+	if !ok {
+		return 0, ErrNotBidirectional
+	}
+	return rw.ReadContext(ctx, buf)
+}
+
+// SetReadDeadline sets the read deadline of the primary connector
+func (t *TeeConnector) SetReadDeadline(deadline time.Time) error {
+	rw, ok := AsReadWriter(t.primary)
+	if !ok {
+		return ErrNotBidirectional
+	}
+	return rw.SetReadDeadline(deadline)
+}
+
+// SetWriteDeadline sets the write deadline of the primary connector
+func (t *TeeConnector) SetWriteDeadline(deadline time.Time) error {
+	rw, ok := AsReadWriter(t.primary)
+	if !ok {
+		return ErrNotBidirectional
+	}
+	return rw.SetWriteDeadline(deadline)
+}
+
+// CanRead reports whether the primary connector can receive data from the printer
+func (t *TeeConnector) CanRead() bool {
+	_, ok := AsReadWriter(t.primary)
+	return ok
+}
+
+// BeginJob forwards the job start to the connectors that record job boundaries
+func (t *TeeConnector) BeginJob(name string) error {
+	return t.forwardJob(func(m JobMarker) error { return m.BeginJob(name) })
+}
+
+// EndJob forwards the job end to the connectors that record job boundaries
+func (t *TeeConnector) EndJob() error {
+	return t.forwardJob(JobMarker.EndJob)
+}
+
+// MirrorErr returns the first error reported by the mirror, if any
+func (t *TeeConnector) MirrorErr() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.mirrorErr
+}
+
+// Close closes both connectors; only the primary's error is returned
+func (t *TeeConnector) Close() error {
+	t.setMirrorErr(t.mirror.Close())
+	return t.primary.Close()
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+// mirrorWrite copies data to the mirror, remembering the first failure
+func (t *TeeConnector) mirrorWrite(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, err := t.mirror.Write(data); err != nil && t.mirrorErr == nil {
+		t.mirrorErr = err
+	}
+}
+
+// forwardJob calls mark on the primary and, without failing, on the mirror
+func (t *TeeConnector) forwardJob(mark func(JobMarker) error) error {
+	if m, ok := t.mirror.(JobMarker); ok {
+		t.setMirrorErr(mark(m))
+	}
+	if m, ok := t.primary.(JobMarker); ok {
+		return mark(m)
+	}
+	return nil
+}
+
+// setMirrorErr remembers err if it is the first mirror failure
+func (t *TeeConnector) setMirrorErr(err error) {
+	if err == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.mirrorErr == nil {
+		t.mirrorErr = err
+	}
+}
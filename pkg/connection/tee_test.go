@@ -0,0 +1,91 @@
+package connection_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/connection"
+)
+
+func TestTeeConnector_MirrorsWrites(t *testing.T) {
+	printer := testutils.NewFakeConnector()
+	path := filepath.Join(t.TempDir(), "mirror.bin")
+	capture, err := connection.NewFileConnector(&connection.FileConfig{Path: path, Sidecar: true})
+	if err != nil {
+		t.Fatalf("NewFileConnector() error = %v", err)
+	}
+
+	tee, err := connection.NewTeeConnector(printer, capture)
+	if err != nil {
+		t.Fatalf("NewTeeConnector() error = %v", err)
+	}
+	if _, ok := connection.AsReadWriter(tee); !ok {
+		t.Error("AsReadWriter() = false for a tee over a bidirectional printer")
+	}
+
+	if err := tee.BeginJob("receipt"); err != nil {
+		t.Fatalf("BeginJob() error = %v", err)
+	}
+	if _, err := tee.Write([]byte("abc")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if _, err := tee.WriteContext(context.Background(), []byte("def")); err != nil {
+		t.Fatalf("WriteContext() error = %v", err)
+	}
+	if err := tee.EndJob(); err != nil {
+		t.Fatalf("EndJob() error = %v", err)
+	}
+
+	// Responses come from the printer only
+	printer.Feed([]byte{0x16})
+	buf := make([]byte, 4)
+	if n, err := tee.Read(buf); err != nil || n != 1 || buf[0] != 0x16 {
+		t.Errorf("Read() = %#v, %v; want [0x16]", buf[:n], err)
+	}
+
+	if err := tee.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := tee.MirrorErr(); err != nil {
+		t.Errorf("MirrorErr() = %v", err)
+	}
+
+	testutils.AssertBytes(t, printer.Written(), []byte("abcdef"), "printer")
+	got, _ := os.ReadFile(path)
+	testutils.AssertBytes(t, got, []byte("abcdef"), "mirror")
+	if log := readCaptureLog(t, filepath.Join(filepath.Dir(path), "mirror.json")); len(log.Jobs) != 1 {
+		t.Errorf("mirror sidecar jobs = %+v, want 1", log.Jobs)
+	}
+}
+
+func TestTeeConnector_MirrorFailureDoesNotStopPrinting(t *testing.T) {
+	printer := &testutils.WriteOnlyConnector{}
+	capture, err := connection.NewFileConnector(&connection.FileConfig{Path: filepath.Join(t.TempDir(), "c.bin")})
+	if err != nil {
+		t.Fatalf("NewFileConnector() error = %v", err)
+	}
+	_ = capture.Close()
+
+	tee, err := connection.NewTeeConnector(printer, capture)
+	if err != nil {
+		t.Fatalf("NewTeeConnector() error = %v", err)
+	}
+	if _, ok := connection.AsReadWriter(tee); ok {
+		t.Error("AsReadWriter() = true for a tee over a write-only printer")
+	}
+
+	if _, err := tee.Write([]byte("ticket")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(printer.Written(), []byte("ticket")) {
+		t.Errorf("printer received %q, want %q", printer.Written(), "ticket")
+	}
+	if !errors.Is(tee.MirrorErr(), connection.ErrConnectionClosed) {
+		t.Errorf("MirrorErr() = %v, want ErrConnectionClosed", tee.MirrorErr())
+	}
+}
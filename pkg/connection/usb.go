@@ -0,0 +1,101 @@
+package connection
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// DefaultUSBDevice is the device node created by the usblp kernel driver for the first printer
+const DefaultUSBDevice = "/dev/usb/lp0"
+
+var (
+	// ErrPrinterNotFound indicates that no attached printer matched the requested name
+	ErrPrinterNotFound = errors.New("printer not found")
+)
+
+// USBConfig holds the settings of a USB printer-class device
+type USBConfig struct {
+	// Device is the path of the printer node, e.g. /dev/usb/lp0
+	Device string
+	// WriteTimeout bounds each Write call while the printer is busy (0 waits forever)
+	WriteTimeout time.Duration
+	// ReadTimeout bounds each Read call (0 waits forever)
+	ReadTimeout time.Duration
+}
+
+// DefaultUSBConfig returns a configuration with sensible timeouts for the given device
+func DefaultUSBConfig(device string) *USBConfig {
+	return &USBConfig{
+		Device:       device,
+		WriteTimeout: DefaultWriteTimeout,
+		ReadTimeout:  DefaultReadTimeout,
+	}
+}
+
+// DeviceID holds the fields of an IEEE-1284 device ID string
+type DeviceID struct {
+	Manufacturer string // MFG / MANUFACTURER
+	Model        string // MDL / MODEL
+	CommandSet   string // CMD / COMMAND SET
+	Class        string // CLS / CLASS
+	Description  string // DES / DESCRIPTION
+	SerialNumber string // SN / SERN
+	Raw          string // Full device ID as reported by the printer
+}
+
+// USBDeviceInfo describes a printer attached through the usblp driver
+type USBDeviceInfo struct {
+	Device string // Device node, e.g. /dev/usb/lp0
+	ID     DeviceID
+}
+
+// Name returns a human-readable "manufacturer model" name
+func (d DeviceID) Name() string {
+	return strings.TrimSpace(d.Manufacturer + " " + d.Model)
+}
+
+// Matches reports whether name matches the manufacturer, the model or both (case-insensitive)
+func (d DeviceID) Matches(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return false
+	}
+	for _, candidate := range []string{d.Name(), d.Model, d.Description} {
+		if candidate != "" && strings.Contains(strings.ToLower(candidate), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseDeviceID parses an IEEE-1284 device ID such as
+// "MFG:EPSON;CMD:ESC/POS;MDL:TM-T20II;CLS:PRINTER;DES:EPSON TM-T20II;"
+func ParseDeviceID(raw string) DeviceID {
+	id := DeviceID{Raw: strings.TrimSpace(raw)}
+
+	for _, field := range strings.Split(id.Raw, ";") {
+		key, value, ok := strings.Cut(field, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.ToUpper(strings.TrimSpace(key)) {
+		case "MFG", "MANUFACTURER":
+			id.Manufacturer = value
+		case "MDL", "MODEL":
+			id.Model = value
+		case "CMD", "COMMAND SET":
+			id.CommandSet = value
+		case "CLS", "CLASS":
+			id.Class = value
+		case "DES", "DESCRIPTION":
+			id.Description = value
+		case "SN", "SERN", "SERIALNUMBER":
+			id.SerialNumber = value
+		}
+	}
+
+	return id
+}
@@ -0,0 +1,283 @@
+//go:build linux
+
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// usbRetryInterval is the pause before retrying an I/O call that returned EAGAIN
+const usbRetryInterval = 5 * time.Millisecond
+
+// deviceIDMaxLen is the buffer size used to fetch the IEEE-1284 device ID
+const deviceIDMaxLen = 1024
+
+// Paths used to enumerate usblp devices; variables so tests can point them to a fake tree
+var (
+	usbDevGlob = "/dev/usb/lp*"
+	usbSysDir  = "/sys/class/usbmisc"
+)
+
+// Interface compliance check
+var _ ReadWriteConnector = (*USBConnector)(nil)
+
+// USBConnector implements a connector for USB printer-class devices exposed by the usblp driver
+type USBConnector struct {
+	config USBConfig
+	file   *os.File
+	closed atomic.Bool
+
+	writeMu       sync.Mutex
+	writeDeadline deadline
+
+	readMu       sync.Mutex
+	readDeadline deadline
+}
+
+// NewUSBConnector opens the USB printer device described by config
+func NewUSBConnector(config *USBConfig) (*USBConnector, error) {
+	if config == nil {
+		return nil, errors.New("usb config cannot be nil")
+	}
+	if config.Device == "" {
+		return nil, ErrEmptyDevice
+	}
+
+	file, err := os.OpenFile(config.Device, os.O_RDWR|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, fmt.Errorf("open usb printer %s: %w", config.Device, err)
+	}
+
+	return &USBConnector{
+		config: *config,
+		file:   file,
+	}, nil
+}
+
+// NewUSBConnectorByName opens the first attached printer whose IEEE-1284 ID matches name
+func NewUSBConnectorByName(name string) (*USBConnector, error) {
+	info, err := FindUSBPrinter(name)
+	if err != nil {
+		return nil, err
+	}
+	return NewUSBConnector(DefaultUSBConfig(info.Device))
+}
+
+// Write sends all of data to the printer, retrying on EAGAIN and after short writes
+func (c *USBConnector) Write(data []byte) (int, error) {
+	return c.WriteContext(context.Background(), data)
+}
+
+// WriteContext is like Write but aborts when ctx is done
+func (c *USBConnector) WriteContext(ctx context.Context, data []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closed.Load() {
+		return 0, ErrConnectionClosed
+	}
+
+	until := effectiveDeadline(ctx, c.writeDeadline.get(), c.config.WriteTimeout)
+	written := 0
+	for written < len(data) {
+		n, err := c.retryAgain(ctx, until, c.file.SetWriteDeadline, func() (int, error) {
+			return c.file.Write(data[written:])
+		})
+		// A short write without error means the driver accepted part of the buffer
+		written += n
+		if err != nil {
+			return written, fmt.Errorf("write to usb printer: %w", err)
+		}
+	}
+
+	return written, nil
+}
+
+// Read receives status bytes sent back by the printer
+func (c *USBConnector) Read(buf []byte) (int, error) {
+	return c.ReadContext(context.Background(), buf)
+}
+
+// ReadContext is like Read but aborts when ctx is done
+func (c *USBConnector) ReadContext(ctx context.Context, buf []byte) (int, error) {
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+
+	if c.closed.Load() {
+		return 0, ErrConnectionClosed
+	}
+
+	until := effectiveDeadline(ctx, c.readDeadline.get(), c.config.ReadTimeout)
+	n, err := c.retryAgain(ctx, until, c.file.SetReadDeadline, func() (int, error) {
+		return c.file.Read(buf)
+	})
+	if err != nil {
+		return n, fmt.Errorf("read from usb printer: %w", err)
+	}
+	return n, nil
+}
+
+// SetReadDeadline sets an absolute deadline for subsequent reads (zero restores ReadTimeout)
+func (c *USBConnector) SetReadDeadline(t time.Time) error {
+	c.readDeadline.set(t)
+	return nil
+}
+
+// SetWriteDeadline sets an absolute deadline for subsequent writes (zero restores WriteTimeout)
+func (c *USBConnector) SetWriteDeadline(t time.Time) error {
+	c.writeDeadline.set(t)
+	return nil
+}
+
+// DeviceID queries the IEEE-1284 device ID of the open printer
+func (c *USBConnector) DeviceID() (DeviceID, error) {
+	if c.closed.Load() {
+		return DeviceID{}, ErrConnectionClosed
+	}
+	raw, err := c.file.SyscallConn()
+	if err != nil {
+		return DeviceID{}, err
+	}
+
+	var id DeviceID
+	var ioErr error
+	if err := raw.Control(func(fd uintptr) {
+		var s string
+		s, ioErr = readDeviceID(fd)
+		id = ParseDeviceID(s)
+	}); err != nil {
+		return DeviceID{}, err
+	}
+	if ioErr != nil {
+		return DeviceID{}, fmt.Errorf("get device id: %w", ioErr)
+	}
+	return id, nil
+}
+
+// Close closes the printer device
+func (c *USBConnector) Close() error {
+	if c.closed.Swap(true) {
+		return nil
+	}
+	if err := c.file.Close(); err != nil {
+		return fmt.Errorf("close usb printer: %w", err)
+	}
+	return nil
+}
+
+// ============================================================================
+// Enumeration
+// ============================================================================
+
+// ListUSBPrinters lists the printers attached through the usblp driver together with their device IDs
+func ListUSBPrinters() ([]USBDeviceInfo, error) {
+	devices, err := filepath.Glob(usbDevGlob)
+	if err != nil {
+		return nil, fmt.Errorf("list usb printers: %w", err)
+	}
+	sort.Strings(devices)
+
+	infos := make([]USBDeviceInfo, 0, len(devices))
+	for _, dev := range devices {
+		raw, err := deviceIDFor(dev)
+		if err != nil {
+			// A printer that cannot report its ID is still listed so it can be opened by path
+			raw = ""
+		}
+		infos = append(infos, USBDeviceInfo{Device: dev, ID: ParseDeviceID(raw)})
+	}
+
+	return infos, nil
+}
+
+// FindUSBPrinter returns the first attached printer whose manufacturer or model matches name
+func FindUSBPrinter(name string) (*USBDeviceInfo, error) {
+	infos, err := ListUSBPrinters()
+	if err != nil {
+		return nil, err
+	}
+	for i := range infos {
+		if infos[i].ID.Matches(name) {
+			return &infos[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrPrinterNotFound, name)
+}
+
+// deviceIDFor returns the raw device ID of dev, preferring sysfs and falling back to the ioctl
+func deviceIDFor(dev string) (string, error) {
+	sysPath := filepath.Join(usbSysDir, filepath.Base(dev), "device", "ieee1284_id")
+	if data, err := os.ReadFile(sysPath); err == nil { //nolint:gosec
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	file, err := os.OpenFile(dev, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+
+	return readDeviceID(file.Fd())
+}
+
+// readDeviceID issues LPIOC_GET_DEVICE_ID. The reply starts with a big-endian
+// length that includes the two length bytes themselves.
+func readDeviceID(fd uintptr) (string, error) {
+	buf := make([]byte, deviceIDMaxLen)
+	// _IOC(_IOC_READ, 'P', 1, len)
+	req := uintptr(2<<30 | deviceIDMaxLen<<16 | 'P'<<8 | 1)
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(unsafe.Pointer(&buf[0]))); errno != 0 { //nolint:gosec
+		return "", errno
+	}
+
+	length := int(buf[0])<<8 | int(buf[1])
+	if length < 2 || length > len(buf) {
+		return "", fmt.Errorf("invalid device id length %d", length)
+	}
+	return string(buf[2:length]), nil
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+// retryAgain runs op until it stops returning EAGAIN. Most usblp devices are handled by
+// the runtime poller, which never surfaces EAGAIN; devices it cannot register stay in
+// non-blocking mode and are polled here instead.
+func (c *USBConnector) retryAgain(ctx context.Context, until time.Time, set func(time.Time) error,
+	op func() (int, error)) (int, error) {
+	setDeadline := func(t time.Time) error {
+		if err := set(t); err != nil && !errors.Is(err, os.ErrNoDeadline) {
+			return err
+		}
+		return nil
+	}
+
+	for {
+		n, err := doWithContext(ctx, until, setDeadline, op)
+		if !errors.Is(err, syscall.EAGAIN) {
+			return n, err
+		}
+		if n > 0 {
+			return n, nil
+		}
+		if expired(until) {
+			return 0, os.ErrDeadlineExceeded
+		}
+		if err := sleepContext(ctx, usbRetryInterval); err != nil {
+			return 0, err
+		}
+	}
+}
@@ -0,0 +1,79 @@
+//go:build linux
+
+package connection
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeUSBTree builds a fake /dev/usb and /sys/class/usbmisc layout and points the enumerator to it
+func fakeUSBTree(t *testing.T, ids map[string]string) {
+	t.Helper()
+
+	root := t.TempDir()
+	devDir := filepath.Join(root, "dev", "usb")
+	sysDir := filepath.Join(root, "sys", "class", "usbmisc")
+
+	for name, id := range ids {
+		if err := os.MkdirAll(devDir, 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(devDir, name), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if id == "" {
+			continue
+		}
+		dir := filepath.Join(sysDir, name, "device")
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "ieee1284_id"), []byte(id+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldGlob, oldSys := usbDevGlob, usbSysDir
+	usbDevGlob, usbSysDir = filepath.Join(devDir, "lp*"), sysDir
+	t.Cleanup(func() { usbDevGlob, usbSysDir = oldGlob, oldSys })
+}
+
+func TestListUSBPrinters(t *testing.T) {
+	fakeUSBTree(t, map[string]string{
+		"lp0": "MFG:EPSON;CMD:ESC/POS;MDL:TM-T20II;CLS:PRINTER;",
+		"lp1": "MFG:Xprinter;CMD:ESC/POS;MDL:XP-58IIH;CLS:PRINTER;",
+		"lp2": "", // no sysfs entry and ioctl unsupported on a regular file
+	})
+
+	infos, err := ListUSBPrinters()
+	if err != nil {
+		t.Fatalf("ListUSBPrinters: %v", err)
+	}
+	if len(infos) != 3 {
+		t.Fatalf("found %d printers; want 3", len(infos))
+	}
+	if got := infos[0].ID.Name(); got != "EPSON TM-T20II" {
+		t.Errorf("lp0 name = %q; want EPSON TM-T20II", got)
+	}
+	if got := infos[1].ID.Model; got != "XP-58IIH" {
+		t.Errorf("lp1 model = %q; want XP-58IIH", got)
+	}
+	if infos[2].ID.Name() != "" {
+		t.Errorf("lp2 should have an empty ID, got %+v", infos[2].ID)
+	}
+
+	info, err := FindUSBPrinter("xp-58")
+	if err != nil {
+		t.Fatalf("FindUSBPrinter: %v", err)
+	}
+	if filepath.Base(info.Device) != "lp1" {
+		t.Errorf("FindUSBPrinter device = %s; want lp1", info.Device)
+	}
+
+	if _, err := FindUSBPrinter("Star"); !errors.Is(err, ErrPrinterNotFound) {
+		t.Errorf("FindUSBPrinter error = %v; want %v", err, ErrPrinterNotFound)
+	}
+}
@@ -0,0 +1,104 @@
+//go:build linux
+
+package connection_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/adcondev/pos-printer/pkg/connection"
+)
+
+func newUSBTestConfig(device string) *connection.USBConfig {
+	cfg := connection.DefaultUSBConfig(device)
+	cfg.WriteTimeout = 2 * time.Second
+	cfg.ReadTimeout = 200 * time.Millisecond
+	return cfg
+}
+
+// The usblp driver behaves like a non-blocking character device; a pty slave is a close stand-in
+
+func TestUSBConnector_WriteRead(t *testing.T) {
+	master, slave := openPTY(t)
+	// Make the pty transparent, as the usblp device is
+	if c, err := connection.NewSerialConnector(connection.DefaultSerialConfig(slave)); err == nil {
+		_ = c.Close()
+	}
+
+	c, err := connection.NewUSBConnector(newUSBTestConfig(slave))
+	if err != nil {
+		t.Fatalf("NewUSBConnector: %v", err)
+	}
+	defer c.Close()
+
+	payload := bytes.Repeat([]byte{0x1B, '@', 'A'}, 2000)
+	received := make(chan []byte, 1)
+	go func() {
+		got := make([]byte, len(payload))
+		_, _ = io.ReadFull(master, got)
+		received <- got
+	}()
+
+	n, err := c.Write(payload)
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if n != len(payload) {
+		t.Errorf("Write returned %d; want %d", n, len(payload))
+	}
+	select {
+	case got := <-received:
+		if !bytes.Equal(got, payload) {
+			t.Error("printer received corrupted payload")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for payload")
+	}
+
+	if _, err := master.Write([]byte{0x12}); err != nil {
+		t.Fatalf("write master: %v", err)
+	}
+	buf := make([]byte, 4)
+	n, err = c.Read(buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if n != 1 || buf[0] != 0x12 {
+		t.Errorf("Read = %#v; want [0x12]", buf[:n])
+	}
+
+	if _, err := c.Read(buf); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("Read error = %v; want %v", err, os.ErrDeadlineExceeded)
+	}
+}
+
+func TestUSBConnector_Close(t *testing.T) {
+	_, slave := openPTY(t)
+
+	c, err := connection.NewUSBConnector(newUSBTestConfig(slave))
+	if err != nil {
+		t.Fatalf("NewUSBConnector: %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := c.Write([]byte("x")); !errors.Is(err, connection.ErrConnectionClosed) {
+		t.Errorf("Write after Close error = %v; want %v", err, connection.ErrConnectionClosed)
+	}
+}
+
+func TestNewUSBConnector_Errors(t *testing.T) {
+	if _, err := connection.NewUSBConnector(nil); err == nil {
+		t.Error("expected error for nil config")
+	}
+	if _, err := connection.NewUSBConnector(connection.DefaultUSBConfig("")); !errors.Is(err, connection.ErrEmptyDevice) {
+		t.Errorf("error = %v; want %v", err, connection.ErrEmptyDevice)
+	}
+	if _, err := connection.NewUSBConnector(connection.DefaultUSBConfig("/dev/usb/lp-missing")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("error = %v; want %v", err, os.ErrNotExist)
+	}
+}
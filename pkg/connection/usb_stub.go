@@ -0,0 +1,53 @@
+//go:build !linux
+
+package connection
+
+import (
+	"errors"
+)
+
+// errUSBUnsupported is returned by the USB connector on non-Linux systems
+var errUSBUnsupported = errors.New("USBConnector no está disponible en este sistema operativo")
+
+// USBConnector es un stub para sistemas no-Linux
+type USBConnector struct{}
+
+// NewUSBConnector devuelve un error en sistemas no-Linux
+func NewUSBConnector(_ *USBConfig) (*USBConnector, error) {
+	return nil, errUSBUnsupported
+}
+
+// NewUSBConnectorByName devuelve un error en sistemas no-Linux
+func NewUSBConnectorByName(_ string) (*USBConnector, error) {
+	return nil, errUSBUnsupported
+}
+
+// ListUSBPrinters devuelve un error en sistemas no-Linux
+func ListUSBPrinters() ([]USBDeviceInfo, error) {
+	return nil, errUSBUnsupported
+}
+
+// FindUSBPrinter devuelve un error en sistemas no-Linux
+func FindUSBPrinter(_ string) (*USBDeviceInfo, error) {
+	return nil, errUSBUnsupported
+}
+
+// Write implementación para sistemas no-Linux
+func (c *USBConnector) Write(_ []byte) (int, error) {
+	return 0, errUSBUnsupported
+}
+
+// Read implementación para sistemas no-Linux
+func (c *USBConnector) Read(_ []byte) (int, error) {
+	return 0, errUSBUnsupported
+}
+
+// DeviceID implementación para sistemas no-Linux
+func (c *USBConnector) DeviceID() (DeviceID, error) {
+	return DeviceID{}, errUSBUnsupported
+}
+
+// Close implementación para sistemas no-Linux
+func (c *USBConnector) Close() error {
+	return errUSBUnsupported
+}
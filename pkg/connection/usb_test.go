@@ -0,0 +1,81 @@
+package connection_test
+
+import (
+	"testing"
+
+	"github.com/adcondev/pos-printer/pkg/connection"
+)
+
+func TestParseDeviceID(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want connection.DeviceID
+	}{
+		{
+			name: "epson short keys",
+			raw:  "MFG:EPSON;CMD:ESC/POS;MDL:TM-T20II;CLS:PRINTER;DES:EPSON TM-T20II Receipt;",
+			want: connection.DeviceID{
+				Manufacturer: "EPSON",
+				Model:        "TM-T20II",
+				CommandSet:   "ESC/POS",
+				Class:        "PRINTER",
+				Description:  "EPSON TM-T20II Receipt",
+			},
+		},
+		{
+			name: "long keys and serial number",
+			raw:  "MANUFACTURER:Xprinter;MODEL:XP-58IIH;COMMAND SET:ESC/POS;SN:A1B2C3;",
+			want: connection.DeviceID{
+				Manufacturer: "Xprinter",
+				Model:        "XP-58IIH",
+				CommandSet:   "ESC/POS",
+				SerialNumber: "A1B2C3",
+			},
+		},
+		{
+			name: "missing trailing semicolon and spaces",
+			raw:  " MFG: Star ; MDL: TSP100 ",
+			want: connection.DeviceID{
+				Manufacturer: "Star",
+				Model:        "TSP100",
+			},
+		},
+		{
+			name: "empty",
+			raw:  "",
+			want: connection.DeviceID{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := connection.ParseDeviceID(tt.raw)
+			got.Raw = ""
+			if got != tt.want {
+				t.Errorf("ParseDeviceID(%q) = %+v; want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeviceID_Matches(t *testing.T) {
+	id := connection.ParseDeviceID("MFG:EPSON;MDL:TM-T20II;DES:EPSON TM-T20II Receipt;")
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"EPSON TM-T20II", true},
+		{"epson", true},
+		{"tm-t20", true},
+		{"receipt", true},
+		{"Star", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := id.Matches(tt.name); got != tt.want {
+			t.Errorf("Matches(%q) = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
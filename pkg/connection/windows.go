@@ -197,7 +197,8 @@ func writePrinter(handle syscall.Handle, data []byte) (uint32, error) {
 	return bytesWritten, nil
 }
 
-func (c *WindowsPrintConnector) Read(buf []byte) (int, error) {
-	l := len(buf) // No implementado, ya que Spooler no soporta lectura de estado de impresora directamente
-	return l, errors.New("spooler no soporta lectura de estado de impresora directamente")
+// Read is not supported: the spooler does not pass printer responses back to the application.
+// The connector therefore does not implement ReadWriteConnector.
+func (c *WindowsPrintConnector) Read(_ []byte) (int, error) {
+	return 0, fmt.Errorf("%w: el spooler no soporta lectura de estado de impresora", ErrNotBidirectional)
 }
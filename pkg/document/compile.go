@@ -0,0 +1,105 @@
+package document
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/adcondev/pos-printer/pkg/printer"
+)
+
+// memoryConnector graba en memoria los comandos de un documento compilado
+type memoryConnector struct {
+	bytes.Buffer
+}
+
+// Close no hace nada: el buffer sigue disponible después de compilar
+func (m *memoryConnector) Close() error {
+	return nil
+}
+
+// SetBuffered activa el modo transaccional: Execute compila el documento completo en memoria,
+// validando todos los comandos, y sólo lo envía si la compilación tuvo éxito (ver ExecuteBuffered)
+func (e *Executor) SetBuffered(buffered bool) {
+	e.buffered = buffered
+}
+
+// Compile genera los bytes ESC/POS del documento para la impresora que lo imprimiría, sin
+// enviar nada. Los cambios de perfil del documento se aplican a una copia del perfil.
+func (e *Executor) Compile(doc *Document) ([]byte, error) {
+	printer, err := e.target(doc)
+	if err != nil {
+		return nil, err
+	}
+	return e.compile(context.Background(), printer, doc)
+}
+
+// ExecuteBuffered compila el documento completo y lo envía en una sola escritura, o en bloques
+// de Profile.BufferSize bytes si la impresora lo define. Si algún comando falla no se envía nada.
+// Devuelve los bytes compilados para registro, también cuando el envío falla.
+func (e *Executor) ExecuteBuffered(doc *Document) ([]byte, error) {
+	return e.ExecuteBufferedContext(context.Background(), doc)
+}
+
+// ExecuteBufferedContext es como ExecuteBuffered pero se interrumpe cuando ctx termina, también
+// entre bloques; si ya se envió parte del documento, la impresora recibe CAN y ESC @
+func (e *Executor) ExecuteBufferedContext(ctx context.Context, doc *Document) ([]byte, error) {
+	printer, err := e.target(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	err = printer.WithJobContext(ctx, jobName, func(job *service.Printer) error {
+		var err error
+		data, err = e.runBuffered(ctx, job, doc)
+		return err
+	})
+	return data, err
+}
+
+// runBuffered compila el documento y lo envía a printer. Debe llamarse dentro de un trabajo.
+func (e *Executor) runBuffered(ctx context.Context, printer *service.Printer, doc *Document) ([]byte, error) {
+	data, err := e.compile(ctx, printer, doc)
+	if err != nil {
+		return nil, err
+	}
+	if err := send(ctx, printer, data); err != nil {
+		if ctx.Err() != nil {
+			e.abort(printer)
+		}
+		return data, fmt.Errorf("send compiled document: %w", err)
+	}
+	return data, nil
+}
+
+// compile ejecuta el documento sobre una copia de printer que escribe en memoria
+func (e *Executor) compile(ctx context.Context, printer *service.Printer, doc *Document) ([]byte, error) {
+	buf := &memoryConnector{}
+	prof := printer.Profile
+	recorder, err := service.NewPrinter(&printer.Protocol, &prof, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.render(ctx, recorder, doc); err != nil {
+		return nil, fmt.Errorf("compile document: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// send escribe data en bloques del tamaño del buffer de la impresora hasta que ctx termine
+func send(ctx context.Context, printer *service.Printer, data []byte) error {
+	size := printer.Profile.BufferSize
+	if size <= 0 {
+		size = len(data)
+	}
+	for len(data) > 0 {
+		n := min(size, len(data))
+		if err := printer.WriteContext(ctx, data[:n]); err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return nil
+}
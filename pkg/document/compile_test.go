@@ -0,0 +1,121 @@
+package document_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/composer"
+	"github.com/adcondev/pos-printer/pkg/document"
+	service "github.com/adcondev/pos-printer/pkg/printer"
+	"github.com/adcondev/pos-printer/pkg/profile"
+)
+
+func newFakeExecutor(t *testing.T, prof *profile.Escpos) (*document.Executor, *testutils.FakeConnector) {
+	t.Helper()
+	fake := testutils.NewFakeConnector()
+	p, err := service.NewPrinter(composer.NewEscpos(), prof, fake)
+	if err != nil {
+		t.Fatalf("NewPrinter: %v", err)
+	}
+	return document.NewExecutor(p), fake
+}
+
+func ticket() *document.Document {
+	return document.NewBuilder().
+		SetProfile("", 58, "").
+		AddText("Ticket 0042", &document.TextStyle{Bold: true, Align: "center"}).
+		AddSeparator("-", 32).
+		AddText("Total: $125.00", &document.TextStyle{Size: "2x2"}).
+		AddCut("partial", 3).
+		Build()
+}
+
+func TestExecutor_ExecuteBuffered(t *testing.T) {
+	// Unbuffered execution is the reference output
+	direct, directConn := newFakeExecutor(t, profile.CreateProfile80mm())
+	if err := direct.Execute(ticket()); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	executor, fake := newFakeExecutor(t, profile.CreateProfile80mm())
+	data, err := executor.ExecuteBuffered(ticket())
+	if err != nil {
+		t.Fatalf("ExecuteBuffered() error = %v", err)
+	}
+
+	testutils.AssertBytes(t, data, directConn.Written())
+	testutils.AssertBytes(t, fake.Written(), data)
+	if got := len(fake.Writes()); got != 1 {
+		t.Errorf("ExecuteBuffered() made %d writes, want 1", got)
+	}
+}
+
+func TestExecutor_ExecuteBuffered_Chunks(t *testing.T) {
+	prof := profile.CreateProfile80mm()
+	prof.BufferSize = 16
+	executor, fake := newFakeExecutor(t, prof)
+
+	data, err := executor.ExecuteBuffered(ticket())
+	if err != nil {
+		t.Fatalf("ExecuteBuffered() error = %v", err)
+	}
+
+	writes := fake.Writes()
+	if want := (len(data) + 15) / 16; len(writes) != want {
+		t.Errorf("ExecuteBuffered() made %d writes, want %d", len(writes), want)
+	}
+	for i, w := range writes {
+		if len(w) > 16 {
+			t.Errorf("write %d has %d bytes, want at most 16", i, len(w))
+		}
+	}
+	testutils.AssertBytes(t, bytes.Join(writes, nil), data)
+}
+
+func TestExecutor_ExecuteBuffered_InvalidCommandSendsNothing(t *testing.T) {
+	executor, fake := newFakeExecutor(t, profile.CreateProfile80mm())
+
+	doc := ticket()
+	doc.Commands = append(doc.Commands, document.Command{Type: "hologram"})
+	data, err := executor.ExecuteBuffered(doc)
+	if err == nil {
+		t.Fatal("ExecuteBuffered() expected error for an unknown command")
+	}
+	if data != nil {
+		t.Errorf("ExecuteBuffered() returned %d bytes for a failed compilation", len(data))
+	}
+	if got := fake.Written(); len(got) != 0 {
+		t.Errorf("printer received %d bytes, want 0", len(got))
+	}
+}
+
+func TestExecutor_SetBuffered(t *testing.T) {
+	fake := testutils.NewFakeConnector()
+	p, err := service.NewPrinter(composer.NewEscpos(), profile.CreateProfile80mm(), fake)
+	if err != nil {
+		t.Fatalf("NewPrinter: %v", err)
+	}
+	executor := document.NewExecutor(p)
+	executor.SetBuffered(true)
+
+	if err := executor.Execute(ticket()); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got := len(fake.Writes()); got != 1 {
+		t.Errorf("buffered Execute() made %d writes, want 1", got)
+	}
+
+	// Document profile changes apply to the compiled copy only
+	if p.Profile.PaperWidth != 80 {
+		t.Errorf("Profile.PaperWidth = %v after buffered Execute, want 80", p.Profile.PaperWidth)
+	}
+	compiled, err := executor.Compile(ticket())
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	testutils.AssertBytes(t, compiled, fake.Written())
+	if got := len(fake.Writes()); got != 1 {
+		t.Errorf("Compile() sent data to the printer")
+	}
+}
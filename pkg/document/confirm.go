@@ -0,0 +1,137 @@
+package document
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adcondev/pos-printer/pkg/commands/processid"
+	"github.com/adcondev/pos-printer/pkg/commands/realtime"
+	"github.com/adcondev/pos-printer/pkg/connection"
+	"github.com/adcondev/pos-printer/pkg/printer"
+)
+
+// confirmStatusTimeout es el tiempo máximo para consultar el estado en tiempo real
+// cuando la confirmación no llega
+const confirmStatusTimeout = 500 * time.Millisecond
+
+var (
+	// ErrPrintFailed indica que la impresora reportó un error antes de confirmar el trabajo
+	ErrPrintFailed = errors.New("print job failed")
+	// ErrPrintTimeout indica que la confirmación del trabajo no llegó a tiempo
+	ErrPrintTimeout = errors.New("print confirmation timed out")
+)
+
+// ConfirmStatus resultado de la confirmación de impresión de un trabajo
+type ConfirmStatus int
+
+const (
+	// ConfirmNone indica que el trabajo no llegó a solicitar confirmación
+	ConfirmNone ConfirmStatus = iota
+	// Confirmed indica que la impresora respondió el process ID del trabajo
+	Confirmed
+	// ConfirmFailed indica que la impresora reportó un error o la consulta falló
+	ConfirmFailed
+	// ConfirmTimeout indica que la respuesta no llegó antes del deadline
+	ConfirmTimeout
+)
+
+// String devuelve el nombre del resultado
+func (s ConfirmStatus) String() string {
+	switch s {
+	case ConfirmNone:
+		return "none"
+	case Confirmed:
+		return "confirmed"
+	case ConfirmFailed:
+		return "failed"
+	case ConfirmTimeout:
+		return "timeout"
+	default:
+		return fmt.Sprintf("ConfirmStatus(%d)", int(s))
+	}
+}
+
+// JobResult resultado de la ejecución confirmada de un documento
+type JobResult struct {
+	ProcessID     processid.ID
+	Status        ConfirmStatus
+	PrinterStatus *realtime.Status // Estado en tiempo real cuando la confirmación no llegó
+	Err           error
+}
+
+// ExecuteConfirmed ejecuta doc, agrega un process ID (GS ( H) al final y espera hasta el
+// deadline de ctx a que la impresora lo responda, lo que confirma que el trabajo se imprimió.
+// Requiere un conector bidireccional. Si la confirmación no llega, el estado en tiempo real
+// distingue un error de la impresora (ConfirmFailed) de un simple retraso (ConfirmTimeout).
+func (e *Executor) ExecuteConfirmed(ctx context.Context, doc *Document) (JobResult, error) {
+	return e.executeConfirmed(ctx, doc, 0)
+}
+
+// executeConfirmed ejecuta doc y espera su confirmación; un timeout mayor que 0 limita
+// sólo la espera de la confirmación
+func (e *Executor) executeConfirmed(ctx context.Context, doc *Document, timeout time.Duration) (JobResult, error) {
+	printer, err := e.target(doc)
+	if err != nil {
+		return JobResult{}, err
+	}
+	if !printer.IsBidirectional() {
+		return JobResult{}, fmt.Errorf("print confirmation: %w", connection.ErrNotBidirectional)
+	}
+
+	var result JobResult
+	err = printer.WithJobContext(ctx, jobName, func(job *service.Printer) error {
+		if err := e.run(ctx, job, doc); err != nil {
+			return err
+		}
+		// La confirmación forma parte del trabajo para que el process ID siga al documento
+		confirmCtx := ctx
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			confirmCtx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		result = e.confirm(confirmCtx, job)
+		return result.Err
+	})
+	return result, err
+}
+
+// confirm espera la confirmación del trabajo recién enviado
+func (e *Executor) confirm(ctx context.Context, printer *service.Printer) JobResult {
+	result := JobResult{ProcessID: e.nextProcessID()}
+
+	err := printer.ConfirmPrint(ctx, result.ProcessID)
+	switch {
+	case err == nil:
+		result.Status = Confirmed
+	case ctx.Err() != nil:
+		// Sin respuesta: consultar el estado en tiempo real, que la impresora responde aunque esté offline
+		sctx, cancel := context.WithTimeout(context.Background(), confirmStatusTimeout)
+		status, serr := printer.Status(sctx)
+		cancel()
+		if serr == nil {
+			result.PrinterStatus = &status
+		}
+		if serr == nil && !status.Ready() {
+			result.Status = ConfirmFailed
+			result.Err = fmt.Errorf("%w: process ID %s: printer not ready (%+v)", ErrPrintFailed, result.ProcessID, status)
+		} else {
+			result.Status = ConfirmTimeout
+			result.Err = fmt.Errorf("%w: process ID %s: %w", ErrPrintTimeout, result.ProcessID, ctx.Err())
+		}
+	default:
+		result.Status = ConfirmFailed
+		result.Err = fmt.Errorf("%w: %w", ErrPrintFailed, err)
+	}
+	return result
+}
+
+// nextProcessID devuelve un process ID distinto para cada trabajo, de 1 a 9999
+func (e *Executor) nextProcessID() processid.ID {
+	e.idMu.Lock()
+	defer e.idMu.Unlock()
+	e.nextID = e.nextID%processid.MaxID + 1
+	return e.nextID
+}
@@ -127,6 +127,43 @@ func (b *Builder) AddCut(mode string, feed int) *Builder {
 	return b
 }
 
+// AddPulse agrega un pulso para abrir el cajón de dinero (pin 2 o 5, tiempos en ms)
+func (b *Builder) AddPulse(pin, onMs, offMs int) *Builder {
+	cmd := PulseCommand{
+		Pin:     pin,
+		OnTime:  onMs,
+		OffTime: offMs,
+	}
+
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		log.Printf("Error marshaling pulse command: %v", err)
+	}
+	b.doc.Commands = append(b.doc.Commands, Command{
+		Type: "pulse",
+		Data: data,
+	})
+	return b
+}
+
+// AddBeep agrega pitidos del zumbador (0 usa los valores por defecto)
+func (b *Builder) AddBeep(times, durationMs int) *Builder {
+	cmd := BeepCommand{
+		Times:    times,
+		Duration: durationMs,
+	}
+
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		log.Printf("Error marshaling beep command: %v", err)
+	}
+	b.doc.Commands = append(b.doc.Commands, Command{
+		Type: "beep",
+		Data: data,
+	})
+	return b
+}
+
 // AddQR agrega un comando QR al documento
 func (b *Builder) AddQR(data, text string, pixelWidth int, correction string, align, logo64 string, circle bool) *Builder {
 	cmd := QRCommand{
@@ -152,6 +189,57 @@ func (b *Builder) AddQR(data, text string, pixelWidth int, correction string, al
 	return b
 }
 
+// AddBarcode agrega un código de barras al documento
+func (b *Builder) AddBarcode(symbology, data string, height, moduleWidth int, hriPosition, hriFont, align string) *Builder {
+	cmd := BarcodeCommand{
+		Symbology:   symbology,
+		Data:        data,
+		Height:      height,
+		ModuleWidth: moduleWidth,
+		HRIPosition: hriPosition,
+		HRIFont:     hriFont,
+		Align:       align,
+	}
+
+	barcodeData, err := json.Marshal(cmd)
+	if err != nil {
+		log.Printf("Error marshaling barcode command: %v", err)
+		return b
+	}
+
+	b.doc.Commands = append(b.doc.Commands, Command{
+		Type: "barcode",
+		Data: barcodeData,
+	})
+	return b
+}
+
+// AddPDF417 agrega un código PDF417 al documento
+func (b *Builder) AddPDF417(data string, columns, rows, moduleWidth, rowHeight int, correction string, truncated bool, align string) *Builder {
+	cmd := PDF417Command{
+		Data:        data,
+		Columns:     columns,
+		Rows:        rows,
+		ModuleWidth: moduleWidth,
+		RowHeight:   rowHeight,
+		Correction:  correction,
+		Truncated:   truncated,
+		Align:       align,
+	}
+
+	pdfData, err := json.Marshal(cmd)
+	if err != nil {
+		log.Printf("Error marshaling PDF417 command: %v", err)
+		return b
+	}
+
+	b.doc.Commands = append(b.doc.Commands, Command{
+		Type: "pdf417",
+		Data: pdfData,
+	})
+	return b
+}
+
 // AddTable adds a table command to the document
 func (b *Builder) AddTable(definition tables.Definition, rows [][]string, showHeaders bool) *Builder {
 	if len(definition.Columns) == 0 {
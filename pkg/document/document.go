@@ -84,6 +84,21 @@ type CutCommand struct {
 	Feed int    `json:"feed,omitempty"` // Líneas antes del corte
 }
 
+// PulseCommand represents a cash drawer kick-out pulse
+type PulseCommand struct {
+	Pin     int `json:"pin,omitempty"`    // Pin del conector: 2 o 5 (default: 2)
+	OnTime  int `json:"on_ms,omitempty"`  // Tiempo ON en ms (default: 50)
+	OffTime int `json:"off_ms,omitempty"` // Tiempo OFF en ms (default: 500)
+
+	Realtime bool `json:"realtime,omitempty"` // Usa DLE DC4: inmediato, ON = OFF = on_ms redondeado a 100 ms
+}
+
+// BeepCommand represents a buzzer command
+type BeepCommand struct {
+	Times    int `json:"times,omitempty"`       // Número de pitidos (default: 1)
+	Duration int `json:"duration_ms,omitempty"` // Duración de cada pitido en ms (default: 200)
+}
+
 // QRCommand actualizado para soportar todas las opciones
 type QRCommand struct {
 	Data      string `json:"data"`                 // Datos del QR (URL, texto, etc.)
@@ -99,6 +114,29 @@ type QRCommand struct {
 	CircleShape bool   `json:"circle_shape,omitempty"` // Usar bloques circulares
 }
 
+// BarcodeCommand represents a 1D barcode command
+type BarcodeCommand struct {
+	Symbology   string `json:"symbology"`              // ean13, ean8, upca, upce, code39, code93, code128, gs1-128, itf, codabar...
+	Data        string `json:"data"`                   // Contenido sin prefijos de code set; GS1 admite "(01)..."
+	Height      int    `json:"height,omitempty"`       // Alto en puntos (default: 162)
+	ModuleWidth int    `json:"module_width,omitempty"` // Ancho del módulo en puntos, 2-6 (default: 3)
+	HRIPosition string `json:"hri_position,omitempty"` // none, above, below, both (default: below)
+	HRIFont     string `json:"hri_font,omitempty"`     // A, B (default: A)
+	Align       string `json:"align,omitempty"`        // left, center, right
+}
+
+// PDF417Command represents a PDF417 command
+type PDF417Command struct {
+	Data        string `json:"data"`                   // Datos del símbolo
+	Columns     int    `json:"columns,omitempty"`      // Columnas de datos, 1-30 (default: automático)
+	Rows        int    `json:"rows,omitempty"`         // Filas, 3-90 (default: automático)
+	ModuleWidth int    `json:"module_width,omitempty"` // Ancho del módulo en puntos, 2-8 (default: 3)
+	RowHeight   int    `json:"row_height,omitempty"`   // Alto de fila en múltiplos del módulo, 2-8 (default: 3)
+	Correction  string `json:"correction,omitempty"`   // Nivel de corrección 0-8 (default: 10% de los datos)
+	Truncated   bool   `json:"truncated,omitempty"`    // PDF417 truncado, más angosto
+	Align       string `json:"align,omitempty"`        // left, center, right
+}
+
 // TODO: Consider upper_separator y lower_separator for tables
 
 // TableCommand represents a table command in the document
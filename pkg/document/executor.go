@@ -1,18 +1,43 @@
 package document
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"sync"
+	"time"
 
 	"github.com/adcondev/pos-printer/pkg/commands/character"
+	"github.com/adcondev/pos-printer/pkg/commands/processid"
 	"github.com/adcondev/pos-printer/pkg/printer"
 )
 
-// Executor ejecuta documentos de impresión
+// jobName nombre con el que los documentos se marcan como trabajo en la impresora
+const jobName = "document"
+
+// abortTimeout tiempo máximo para enviar CAN y ESC @ tras interrumpir un trabajo
+const abortTimeout = 2 * time.Second
+
+// Executor ejecuta documentos de impresión. Es seguro para uso concurrente: cada documento
+// se envía dentro de un trabajo de la impresora (service.Printer.WithJob), así que los bytes
+// de dos documentos nunca se mezclan.
 type Executor struct {
 	printer  *service.Printer
 	handlers map[string]CommandHandler
+
+	confirmTimeout time.Duration // 0 desactiva la confirmación en Execute
+	idMu           sync.Mutex
+	nextID         processid.ID // Process ID del siguiente trabajo confirmado
+
+	resolver PrinterResolver // Impresoras registradas que profile.model puede seleccionar
+	buffered bool            // Execute compila el documento completo antes de enviarlo
+}
+
+// PrinterResolver obtiene impresoras registradas por nombre lógico (ver registry.Registry)
+type PrinterResolver interface {
+	Has(name string) bool
+	Get(name string) (*service.Printer, error)
 }
 
 // CommandHandler a command handler function
@@ -29,13 +54,17 @@ func NewExecutor(printer *service.Printer) *Executor {
 	e.RegisterHandler("text", e.handleText)
 	e.RegisterHandler("feed", e.handleFeed)
 	e.RegisterHandler("cut", e.handleCut)
+	e.RegisterHandler("pulse", e.handlePulse)
+	e.RegisterHandler("beep", e.handleBeep)
 
 	// Registrar handlers avanzados
 	e.RegisterHandler("image", e.handleImage)
 	e.RegisterHandler("separator", e.handleSeparator)
 
-	// Handlers para QR y tablas
+	// Handlers para QR, PDF417, códigos de barras y tablas
 	e.RegisterHandler("qr", e.handleQR)
+	e.RegisterHandler("pdf417", e.handlePDF417)
+	e.RegisterHandler("barcode", e.handleBarcode)
 	e.RegisterHandler("table", e.handleTable)
 
 	return e
@@ -46,26 +75,120 @@ func (e *Executor) RegisterHandler(cmdType string, handler CommandHandler) {
 	e.handlers[cmdType] = handler
 }
 
-// Execute ejecuta un documento completo
+// SetConfirmTimeout hace que Execute espere hasta timeout la confirmación de impresión
+// de cada documento (ver ExecuteConfirmed). Un timeout de 0 la desactiva.
+func (e *Executor) SetConfirmTimeout(timeout time.Duration) {
+	e.confirmTimeout = timeout
+}
+
+// SetResolver hace que profile.model seleccione la impresora registrada con ese nombre.
+// Los documentos cuyo model no está registrado se imprimen en la impresora del ejecutor.
+func (e *Executor) SetResolver(resolver PrinterResolver) {
+	e.resolver = resolver
+}
+
+// Execute ejecuta un documento completo. Con SetBuffered lo compila entero antes de enviarlo;
+// con SetConfirmTimeout también espera la confirmación de impresión y devuelve
+// ErrPrintFailed o ErrPrintTimeout si no llega.
 func (e *Executor) Execute(doc *Document) error {
+	return e.ExecuteContext(context.Background(), doc)
+}
+
+// ExecuteContext es como Execute pero se interrumpe cuando ctx termina: mientras espera a
+// otro trabajo, entre comandos y durante las escrituras. Si el documento ya empezó a
+// enviarse, la impresora recibe CAN y ESC @ para descartar el ticket a medias.
+func (e *Executor) ExecuteContext(ctx context.Context, doc *Document) error {
+	if e.confirmTimeout > 0 {
+		_, err := e.executeConfirmed(ctx, doc, e.confirmTimeout)
+		return err
+	}
+
+	printer, err := e.target(doc)
+	if err != nil {
+		return err
+	}
+	return printer.WithJobContext(ctx, jobName, func(job *service.Printer) error {
+		return e.run(ctx, job, doc)
+	})
+}
+
+// target devuelve la impresora registrada que selecciona profile.model o, si no hay,
+// la impresora del ejecutor
+func (e *Executor) target(doc *Document) (*service.Printer, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("document is nil")
+	}
+	if e.isRegistered(doc.Profile.Model) {
+		printer, err := e.resolver.Get(doc.Profile.Model)
+		if err != nil {
+			return nil, fmt.Errorf("printer %q: %w", doc.Profile.Model, err)
+		}
+		return printer, nil
+	}
+	if e.printer == nil {
+		return nil, fmt.Errorf("no printer for model %q", doc.Profile.Model)
+	}
+	return e.printer, nil
+}
+
+// isRegistered indica si model nombra una impresora del resolver
+func (e *Executor) isRegistered(model string) bool {
+	return e.resolver != nil && model != "" && e.resolver.Has(model)
+}
+
+// run envía los comandos del documento a la impresora, compilándolo antes en modo buffered.
+// Debe llamarse dentro de un trabajo de printer.
+func (e *Executor) run(ctx context.Context, printer *service.Printer, doc *Document) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if e.buffered {
+		_, err := e.runBuffered(ctx, printer, doc)
+		return err
+	}
+
+	err := e.render(ctx, printer, doc)
+	if err != nil && ctx.Err() != nil {
+		e.abort(printer)
+	}
+	return err
+}
+
+// abort descarta en la impresora los datos de un trabajo interrumpido
+func (e *Executor) abort(printer *service.Printer) {
+	ctx, cancel := context.WithTimeout(context.Background(), abortTimeout)
+	defer cancel()
+	if err := printer.AbortJob(ctx); err != nil {
+		log.Printf("Warning: failed to abort interrupted job: %v", err)
+	}
+}
+
+// render ejecuta los handlers del documento sobre printer, que puede ser la impresora
+// real o una copia que graba en memoria (ver compile). Revisa ctx antes de cada comando.
+func (e *Executor) render(ctx context.Context, printer *service.Printer, doc *Document) error {
 	// Inicializar impresora
-	if err := e.printer.Initialize(); err != nil {
+	if err := printer.Initialize(); err != nil {
 		return fmt.Errorf("failed to initialize printer: %w", err)
 	}
 
-	// Aplicar configuración del profile desde JSON
-	if err := e.applyProfileFromDocument(doc); err != nil {
+	// Aplicar configuración del profile desde JSON; las impresoras registradas conservan su perfil
+	if e.isRegistered(doc.Profile.Model) {
+		log.Printf("Profile: using registered printer '%s'", doc.Profile.Model)
+	} else if err := e.applyProfileFromDocument(printer, doc); err != nil {
 		log.Printf("Warning: failed to apply profile settings: %v", err)
 	}
 
 	// Execute commands
 	for i, cmd := range doc.Commands {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("command %d (%s) not sent: %w", i, cmd.Type, err)
+		}
 		handler, exists := e.handlers[cmd.Type]
 		if !exists {
 			return fmt.Errorf("unknown command type at position %d: %s", i, cmd.Type)
 		}
 
-		if err := handler(e.printer, cmd.Data); err != nil {
+		if err := handler(printer, cmd.Data); err != nil {
 			return fmt.Errorf("command %d (%s) failed: %w", i, cmd.Type, err)
 		}
 	}
@@ -74,7 +197,7 @@ func (e *Executor) Execute(doc *Document) error {
 }
 
 // setCodeTable configura la tabla de caracteres con fallback
-func (e *Executor) setCodeTable(tableName string) error {
+func (e *Executor) setCodeTable(printer *service.Printer, tableName string) error {
 	// Mapa de nombres a constantes
 	tables := map[string]character.CodeTable{
 		"PC437":   character.PC437,
@@ -87,23 +210,28 @@ func (e *Executor) setCodeTable(tableName string) error {
 	table, ok := tables[tableName]
 	if !ok {
 		log.Printf("warning: unsupported code table %s, falling back to Windows-1252", tableName)
-		return e.printer.SetCodeTable(character.WPC1252)
+		return printer.SetCodeTable(character.WPC1252)
 	}
 
-	return e.printer.SetCodeTable(table)
+	return printer.SetCodeTable(table)
 }
 
 // ExecuteJSON ejecuta un documento desde JSON
 func (e *Executor) ExecuteJSON(data []byte) error {
+	return e.ExecuteJSONContext(context.Background(), data)
+}
+
+// ExecuteJSONContext ejecuta un documento desde JSON hasta que ctx termine (ver ExecuteContext)
+func (e *Executor) ExecuteJSONContext(ctx context.Context, data []byte) error {
 	doc, err := ParseDocument(data)
 	if err != nil {
 		return err
 	}
-	return e.Execute(doc)
+	return e.ExecuteContext(ctx, doc)
 }
 
 // applyProfileFromDocument aplica la configuración del profile desde el documento JSON
-func (e *Executor) applyProfileFromDocument(doc *Document) error {
+func (e *Executor) applyProfileFromDocument(printer *service.Printer, doc *Document) error {
 	if doc == nil {
 		return fmt.Errorf("document is nil")
 	}
@@ -114,17 +242,17 @@ func (e *Executor) applyProfileFromDocument(doc *Document) error {
 
 	// Aplicar Model si se especifica
 	if doc.Profile.Model != "" {
-		e.printer.Profile.Model = doc.Profile.Model
+		printer.Profile.Model = doc.Profile.Model
 		log.Printf("Profile: Model set to %s from JSON", doc.Profile.Model)
 	}
 
 	// Aplicar HasQR
-	e.printer.Profile.HasQR = doc.Profile.HasQR
+	printer.Profile.HasQR = doc.Profile.HasQR
 	log.Printf("Profile: HasQR set to %v from JSON", doc.Profile.HasQR)
 
 	// Aplicar CodeTable si se especifica
 	if doc.Profile.CodeTable != "" {
-		if err := e.setCodeTable(doc.Profile.CodeTable); err != nil {
+		if err := e.setCodeTable(printer, doc.Profile.CodeTable); err != nil {
 			log.Printf("Warning: failed to set code table %s: %v", doc.Profile.CodeTable, err)
 		}
 	}
@@ -133,18 +261,18 @@ func (e *Executor) applyProfileFromDocument(doc *Document) error {
 	if doc.Profile.PaperWidth > 0 {
 		// Calcular DotsPerLine basado en PaperWidth y DPI
 		if doc.Profile.DPI > 0 {
-			e.printer.Profile.DPI = doc.Profile.DPI
+			printer.Profile.DPI = doc.Profile.DPI
 		}
-		e.printer.Profile.PaperWidth = float64(doc.Profile.PaperWidth)
+		printer.Profile.PaperWidth = float64(doc.Profile.PaperWidth)
 		// Recalcular DotsPerLine: (PaperWidth_mm * DPI) / 25.4
-		e.printer.Profile.DotsPerLine = int(float64(doc.Profile.PaperWidth) * float64(e.printer.Profile.DPI) / 25.4)
+		printer.Profile.DotsPerLine = int(float64(doc.Profile.PaperWidth) * float64(printer.Profile.DPI) / 25.4)
 		log.Printf("Profile: Updated PaperWidth=%dmm, DotsPerLine=%d",
-			doc.Profile.PaperWidth, e.printer.Profile.DotsPerLine)
+			doc.Profile.PaperWidth, printer.Profile.DotsPerLine)
 	}
 
 	// Model name (útil para debugging)
 	if doc.Profile.Model != "" {
-		e.printer.Profile.Model = doc.Profile.Model
+		printer.Profile.Model = doc.Profile.Model
 		log.Printf("Profile: Model set to '%s'", doc.Profile.Model)
 	}
 
@@ -0,0 +1,624 @@
+package document_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/commands/buzzer"
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+	"github.com/adcondev/pos-printer/pkg/commands/processid"
+	"github.com/adcondev/pos-printer/pkg/composer"
+	"github.com/adcondev/pos-printer/pkg/connection"
+	"github.com/adcondev/pos-printer/pkg/document"
+	service "github.com/adcondev/pos-printer/pkg/printer"
+	"github.com/adcondev/pos-printer/pkg/profile"
+	"github.com/adcondev/pos-printer/pkg/registry"
+)
+
+func newTestExecutor(t *testing.T) (*document.Executor, *testutils.WriteOnlyConnector) {
+	t.Helper()
+	conn := &testutils.WriteOnlyConnector{}
+	p, err := service.NewPrinter(composer.NewEscpos(), profile.CreateProfile80mm(), conn)
+	if err != nil {
+		t.Fatalf("NewPrinter: %v", err)
+	}
+	return document.NewExecutor(p), conn
+}
+
+func TestExecutor_Pulse(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *document.Document
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			doc:  document.NewBuilder().AddPulse(0, 0, 0).Build(),
+			want: []byte{common.ESC, 'p', 0x00, 25, 250},
+		},
+		{
+			name: "pin 5 custom timing",
+			doc:  document.NewBuilder().AddPulse(5, 100, 100).Build(),
+			want: []byte{common.ESC, 'p', 0x01, 50, 50},
+		},
+		{
+			name:    "invalid pin",
+			doc:     document.NewBuilder().AddPulse(3, 100, 100).Build(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor, conn := newTestExecutor(t)
+
+			err := executor.Execute(tt.doc)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Execute() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			testutils.AssertContains(t, conn.Written(), tt.want, "pulse command")
+		})
+	}
+}
+
+func TestExecutor_PulseJSON(t *testing.T) {
+	executor, conn := newTestExecutor(t)
+
+	err := executor.ExecuteJSON([]byte(`{"commands":[{"type":"pulse","data":{"pin":2,"on_ms":60,"off_ms":120}}]}`))
+	if err != nil {
+		t.Fatalf("ExecuteJSON() error = %v", err)
+	}
+	testutils.AssertContains(t, conn.Written(), []byte{common.ESC, 'p', 0x00, 30, 60}, "pulse command")
+
+	err = executor.ExecuteJSON([]byte(`{"commands":[{"type":"pulse","data":{"pin":5,"on_ms":200,"realtime":true}}]}`))
+	if err != nil {
+		t.Fatalf("ExecuteJSON() realtime error = %v", err)
+	}
+	testutils.AssertContains(t, conn.Written(), []byte{common.DLE, common.DC4, 1, 1, 2}, "real-time pulse command")
+}
+
+func TestExecutor_Pulse_NoDrawer(t *testing.T) {
+	conn := &testutils.WriteOnlyConnector{}
+	prof := profile.CreateProfile58mm()
+	prof.SupportsDrawer = false
+	p, err := service.NewPrinter(composer.NewEscpos(), prof, conn)
+	if err != nil {
+		t.Fatalf("NewPrinter: %v", err)
+	}
+
+	doc := document.NewBuilder().AddPulse(0, 0, 0).AddText("after pulse", nil).Build()
+	if err := document.NewExecutor(p).Execute(doc); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if bytes.Contains(conn.Written(), []byte{common.ESC, 'p'}) {
+		t.Error("printer without drawer received ESC p")
+	}
+	testutils.AssertContains(t, conn.Written(), []byte("after pulse"), "text after the skipped pulse")
+}
+
+// confirmingPrinter returns a fake bidirectional printer that answers GS ( H with its
+// process ID while online, and DLE EOT with the given paper sensor status byte
+func confirmingPrinter(t *testing.T, online bool, paperStatus byte) (*document.Executor, *testutils.FakeConnector) {
+	t.Helper()
+	fake := testutils.NewFakeConnector()
+	fake.Responder = func(written []byte) []byte {
+		switch {
+		case bytes.HasPrefix(written, []byte{common.GS, '(', 'H'}) && online:
+			return append([]byte{common.BlockHeader, processid.ResponseIdentifier}, append(written[7:11], 0x00)...)
+		case len(written) == 3 && written[0] == common.DLE && written[1] == common.EOT:
+			if written[2] == 4 {
+				return []byte{paperStatus}
+			}
+			return []byte{0x12}
+		}
+		return nil
+	}
+	p, err := service.NewPrinter(composer.NewEscpos(), profile.CreateProfile80mm(), fake)
+	if err != nil {
+		t.Fatalf("NewPrinter: %v", err)
+	}
+	return document.NewExecutor(p), fake
+}
+
+func TestExecutor_ExecuteConfirmed(t *testing.T) {
+	tests := []struct {
+		name        string
+		online      bool
+		paperStatus byte
+		want        document.ConfirmStatus
+		wantErr     error
+	}{
+		{"confirmed", true, 0x12, document.Confirmed, nil},
+		{"paper end", false, 0x72, document.ConfirmFailed, document.ErrPrintFailed},
+		{"printer ready but silent", false, 0x12, document.ConfirmTimeout, document.ErrPrintTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor, fake := confirmingPrinter(t, tt.online, tt.paperStatus)
+			doc := document.NewBuilder().AddPulse(0, 0, 0).Build()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			result, err := executor.ExecuteConfirmed(ctx, doc)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+			} else if err != nil {
+				t.Fatalf("ExecuteConfirmed() error = %v", err)
+			}
+
+			if result.Status != tt.want {
+				t.Errorf("Status = %v, want %v", result.Status, tt.want)
+			}
+			if result.ProcessID != 1 {
+				t.Errorf("ProcessID = %v, want 0001", result.ProcessID)
+			}
+			testutils.AssertContains(t, fake.Written(), []byte{common.GS, '(', 'H', 6, 0, 48, 48, '0', '0', '0', '1'},
+				"process ID request")
+		})
+	}
+}
+
+func TestExecutor_SetConfirmTimeout(t *testing.T) {
+	executor, _ := confirmingPrinter(t, true, 0x12)
+	executor.SetConfirmTimeout(time.Second)
+
+	for i := 0; i < 2; i++ {
+		if err := executor.Execute(document.NewBuilder().AddPulse(0, 0, 0).Build()); err != nil {
+			t.Fatalf("Execute() job %d error = %v", i, err)
+		}
+	}
+}
+
+func TestExecutor_ExecuteConfirmed_NotBidirectional(t *testing.T) {
+	executor, _ := newTestExecutor(t)
+
+	_, err := executor.ExecuteConfirmed(context.Background(), document.NewBuilder().Build())
+	testutils.AssertError(t, err, connection.ErrNotBidirectional)
+}
+
+func TestExecutor_BeepJSON(t *testing.T) {
+	conn := &testutils.WriteOnlyConnector{}
+	prof := profile.CreateProfile80mm()
+	prof.Buzzer = buzzer.VariantESCB
+	p, err := service.NewPrinter(composer.NewEscpos(), prof, conn)
+	if err != nil {
+		t.Fatalf("NewPrinter: %v", err)
+	}
+	executor := document.NewExecutor(p)
+
+	err = executor.ExecuteJSON([]byte(`{"commands":[{"type":"beep","data":{"times":3}}]}`))
+	if err != nil {
+		t.Fatalf("ExecuteJSON() error = %v", err)
+	}
+	testutils.AssertContains(t, conn.Written(), []byte{common.ESC, 'B', 3, 4}, "beep command")
+}
+
+func TestExecutor_Barcode(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     *document.Document
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name: "ean13 defaults",
+			doc:  document.NewBuilder().AddBarcode("EAN13", "4006381333931", 0, 0, "", "", "center").Build(),
+			want: append([]byte{common.GS, 'H', 2, common.GS, 'f', 0, common.GS, 'h', 162, common.GS, 'w', 3,
+				common.GS, 'k', 67, 13}, "4006381333931"...),
+		},
+		{
+			name: "gs1-128 with hri above in font B",
+			doc:  document.NewBuilder().AddBarcode("gs1-128", "0112345", 80, 2, "above", "b", "").Build(),
+			want: []byte{common.GS, 'H', 1, common.GS, 'f', 1, common.GS, 'h', 80, common.GS, 'w', 2,
+				common.GS, 'k', 74, 8, '{', 'B', '0', '{', 'C', 11, 23, 45},
+		},
+		{
+			name: "gs1-128 from human readable AIs",
+			doc:  document.NewBuilder().AddBarcode("gs1-128", "(01)09501101530003(10)AB", 0, 0, "none", "", "").Build(),
+			want: []byte{common.GS, 'k', 74, 15, '{', 'C', 1, 9, 50, 11, 1, 53, 0, 3, 10, '{', 'B', 'A', 'B'},
+		},
+		{
+			name: "gs1 databar expanded from human readable AIs",
+			doc:  document.NewBuilder().AddBarcode("gs1-databar-expanded", "(10)AB(3103)001250", 0, 0, "none", "", "").Build(),
+			want: append([]byte{common.GS, 'k', 78, 16}, "10AB{13103001250"...),
+		},
+		{
+			name:    "gs1 wrong check digit",
+			doc:     document.NewBuilder().AddBarcode("gs1-128", "(01)09501101530004", 0, 0, "", "", "").Build(),
+			wantErr: true,
+		},
+		{
+			name:    "unknown symbology",
+			doc:     document.NewBuilder().AddBarcode("maxicode", "123", 0, 0, "", "", "").Build(),
+			wantErr: true,
+		},
+		{
+			name:    "invalid hri position",
+			doc:     document.NewBuilder().AddBarcode("code128", "123", 0, 0, "left", "", "").Build(),
+			wantErr: true,
+		},
+		{
+			name: "widest module",
+			doc:  document.NewBuilder().AddBarcode("ean8", "96385074", 0, 6, "", "", "").Build(),
+			want: []byte{common.GS, 'w', 6},
+		},
+		{
+			name:    "module too narrow",
+			doc:     document.NewBuilder().AddBarcode("ean8", "96385074", 0, 1, "", "", "").Build(),
+			wantErr: true,
+		},
+		{
+			name:    "module too wide",
+			doc:     document.NewBuilder().AddBarcode("ean8", "96385074", 0, 7, "", "", "").Build(),
+			wantErr: true,
+		},
+		{
+			name:    "extended module width code",
+			doc:     document.NewBuilder().AddBarcode("ean8", "96385074", 0, 68, "", "", "").Build(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor, conn := newTestExecutor(t)
+
+			err := executor.Execute(tt.doc)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Execute() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			testutils.AssertContains(t, conn.Written(), tt.want, "barcode command")
+		})
+	}
+}
+
+func TestExecutor_PDF417(t *testing.T) {
+	pdf := func(fn byte, params ...byte) []byte {
+		return append([]byte{common.GS, '(', 'k', byte(len(params) + 2), 0, 0x30, fn}, params...)
+	}
+	store := append([]byte{common.GS, '(', 'k', 13, 0, 0x30, 80, 0x30}, "TRACK-0042"...)
+
+	tests := []struct {
+		name    string
+		doc     *document.Document
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			doc:  document.NewBuilder().AddPDF417("TRACK-0042", 0, 0, 0, 0, "", false, "center").Build(),
+			want: slices.Concat([]byte{common.ESC, 'a', 1},
+				pdf(65, 0), pdf(66, 0), pdf(67, 3), pdf(68, 3), pdf(69, 49, 1), pdf(70, 0), store, pdf(81, 0x30),
+				[]byte{common.ESC, 'a', 0}),
+		},
+		{
+			name: "truncated with fixed layout and level",
+			doc:  document.NewBuilder().AddPDF417("TRACK-0042", 3, 12, 2, 4, "5", true, "").Build(),
+			want: slices.Concat(pdf(65, 3), pdf(66, 12), pdf(67, 2), pdf(68, 4), pdf(69, 48, 53), pdf(70, 1), store),
+		},
+		{
+			name:    "empty data",
+			doc:     document.NewBuilder().AddPDF417("", 0, 0, 0, 0, "", false, "").Build(),
+			wantErr: true,
+		},
+		{
+			name:    "invalid rows",
+			doc:     document.NewBuilder().AddPDF417("TRACK-0042", 0, 2, 0, 0, "", false, "").Build(),
+			wantErr: true,
+		},
+		{
+			name:    "columns outside a byte",
+			doc:     document.NewBuilder().AddPDF417("TRACK-0042", 259, 0, 0, 0, "", false, "").Build(),
+			wantErr: true,
+		},
+		{
+			name:    "invalid module width",
+			doc:     document.NewBuilder().AddPDF417("TRACK-0042", 0, 0, 9, 0, "", false, "").Build(),
+			wantErr: true,
+		},
+		{
+			name:    "invalid correction level",
+			doc:     document.NewBuilder().AddPDF417("TRACK-0042", 0, 0, 0, 0, "H", false, "").Build(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &testutils.WriteOnlyConnector{}
+			p, err := service.NewPrinter(composer.NewEscpos(), profile.CreateTMT20(), conn)
+			if err != nil {
+				t.Fatalf("NewPrinter: %v", err)
+			}
+
+			err = document.NewExecutor(p).Execute(tt.doc)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Execute() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			testutils.AssertContains(t, conn.Written(), tt.want, "pdf417 command")
+		})
+	}
+}
+
+func TestExecutor_PDF417_ImageFallback(t *testing.T) {
+	// The generic 80mm profile has no native PDF417
+	executor, conn := newTestExecutor(t)
+
+	doc := document.NewBuilder().AddPDF417("TRACK-0042", 0, 0, 0, 0, "", false, "center").Build()
+	if err := executor.Execute(doc); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	written := conn.Written()
+	if bytes.Contains(written, []byte{common.GS, '(', 'k'}) {
+		t.Error("printer without PDF417 support received GS ( k")
+	}
+	if !bytes.Contains(written, []byte{common.GS, 'v', '0'}) {
+		t.Fatal("raster PDF417 (GS v 0) not written")
+	}
+}
+
+func TestExecutor_Barcode_ImageFallback(t *testing.T) {
+	conn := &testutils.WriteOnlyConnector{}
+	prof := profile.CreateProfile58mm()
+	prof.SupportsBarcode = false
+	p, err := service.NewPrinter(composer.NewEscpos(), prof, conn)
+	if err != nil {
+		t.Fatalf("NewPrinter: %v", err)
+	}
+
+	doc := document.NewBuilder().AddBarcode("code128", "A-0042", 60, 2, "below", "", "center").Build()
+	if err := document.NewExecutor(p).Execute(doc); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	written := conn.Written()
+	if bytes.Contains(written, []byte{common.GS, 'k'}) {
+		t.Error("printer without barcode support received GS k")
+	}
+	raster := bytes.Index(written, []byte{common.GS, 'v', '0'})
+	if raster < 0 {
+		t.Fatal("raster barcode (GS v 0) not written")
+	}
+	// The HRI text is drawn in the image under the 60 dot bars
+	if rows := int(written[raster+6]) | int(written[raster+7])<<8; rows <= 60 {
+		t.Errorf("barcode image has %d rows, want bars and HRI text", rows)
+	}
+	if bytes.Contains(written, []byte("A-0042")) {
+		t.Error("HRI printed as a text line")
+	}
+}
+
+func TestExecutor_SetResolver(t *testing.T) {
+	executor, fallback := newTestExecutor(t)
+
+	kitchen := &testutils.WriteOnlyConnector{}
+	reg := registry.New()
+	if err := reg.Register("kitchen-1", profile.CreateProfile58mm(), func() (connection.Connector, error) {
+		return kitchen, nil
+	}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	executor.SetResolver(reg)
+
+	// A registered model selects its printer and keeps the registered profile
+	doc := document.NewBuilder().SetProfile("kitchen-1", 80, "").AddText("Comanda 12", nil).Build()
+	if err := executor.Execute(doc); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(kitchen.Written()) == 0 {
+		t.Error("registered printer received no data")
+	}
+	if len(fallback.Written()) != 0 {
+		t.Errorf("executor printer received %d bytes, want 0", len(fallback.Written()))
+	}
+	p, err := reg.Get("kitchen-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if p.Profile.PaperWidth != 58 || p.Profile.Model != "Generic 58mm" {
+		t.Errorf("registered profile changed to %vmm %q", p.Profile.PaperWidth, p.Profile.Model)
+	}
+
+	// Unregistered models still print on the executor printer
+	doc = document.NewBuilder().SetProfile("TM-T20", 0, "").AddText("Ticket", nil).Build()
+	if err := executor.Execute(doc); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(fallback.Written()) == 0 {
+		t.Error("executor printer received no data for an unregistered model")
+	}
+}
+
+func TestExecutor_SetResolver_Unavailable(t *testing.T) {
+	reg := registry.New()
+	if err := reg.Register("bar", profile.CreateProfile80mm(), func() (connection.Connector, error) {
+		return nil, errors.New("connection refused")
+	}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	executor := document.NewExecutor(nil)
+	executor.SetResolver(reg)
+
+	err := executor.Execute(document.NewBuilder().SetProfile("bar", 0, "").Build())
+	testutils.AssertError(t, err, registry.ErrUnavailable)
+}
+
+// slowConnector records writes and pauses on every call, so that unsynchronized
+// writers interleave
+type slowConnector struct {
+	testutils.WriteOnlyConnector
+}
+
+func (s *slowConnector) Write(data []byte) (int, error) {
+	time.Sleep(50 * time.Microsecond)
+	return s.WriteOnlyConnector.Write(data)
+}
+
+func TestExecutor_ConcurrentExecuteDoesNotInterleave(t *testing.T) {
+	docs := []*document.Document{
+		document.NewBuilder().
+			AddText("Mesa 4", &document.TextStyle{Bold: true, Align: "center"}).
+			AddText("2x Tacos al pastor", nil).
+			AddSeparator("-", 20).
+			AddFeed(2).Build(),
+		document.NewBuilder().
+			AddText("Mesa 9", &document.TextStyle{Size: "2x2"}).
+			AddText("1x Agua de horchata", nil).
+			AddSeparator("=", 20).
+			AddFeed(3).Build(),
+	}
+
+	// Bytes of each document executed alone
+	var alone [][]byte
+	for _, doc := range docs {
+		conn := &testutils.WriteOnlyConnector{}
+		p, err := service.NewPrinter(composer.NewEscpos(), profile.CreateProfile80mm(), conn)
+		if err != nil {
+			t.Fatalf("NewPrinter: %v", err)
+		}
+		if err := document.NewExecutor(p).Execute(doc); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		alone = append(alone, conn.Written())
+	}
+	if bytes.Equal(alone[0], alone[1]) {
+		t.Fatal("test documents must produce different bytes")
+	}
+
+	conn := &slowConnector{}
+	p, err := service.NewPrinter(composer.NewEscpos(), profile.CreateProfile80mm(), conn)
+	if err != nil {
+		t.Fatalf("NewPrinter: %v", err)
+	}
+	executor := document.NewExecutor(p)
+
+	const rounds = 25
+	var wg sync.WaitGroup
+	for _, doc := range docs {
+		wg.Add(1)
+		go func(doc *document.Document) {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				if err := executor.Execute(doc); err != nil {
+					t.Errorf("Execute() error = %v", err)
+					return
+				}
+			}
+		}(doc)
+	}
+	wg.Wait()
+
+	// The stream must be a sequence of whole documents
+	stream := conn.Written()
+	counts := make([]int, len(docs))
+	for len(stream) > 0 {
+		matched := false
+		for i, want := range alone {
+			if bytes.HasPrefix(stream, want) {
+				stream = stream[len(want):]
+				counts[i]++
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			t.Fatalf("documents interleaved; unexpected bytes at % x", stream[:min(len(stream), 32)])
+		}
+	}
+	for i, n := range counts {
+		if n != rounds {
+			t.Errorf("document %d printed %d times, want %d", i, n, rounds)
+		}
+	}
+}
+
+// cancelingConnector cancels a context after a number of writes, like a client that gives up
+// while the ticket is printing
+type cancelingConnector struct {
+	testutils.WriteOnlyConnector
+	after  int
+	cancel context.CancelFunc
+	writes int
+}
+
+func (c *cancelingConnector) Write(data []byte) (int, error) {
+	n, err := c.WriteOnlyConnector.Write(data)
+	c.writes++
+	if c.writes == c.after {
+		c.cancel()
+	}
+	return n, err
+}
+
+func TestExecutor_ExecuteContext(t *testing.T) {
+	t.Run("canceled before start sends nothing", func(t *testing.T) {
+		executor, conn := newTestExecutor(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := executor.ExecuteContext(ctx, ticket())
+		testutils.AssertError(t, err, context.Canceled)
+		if got := conn.Written(); len(got) != 0 {
+			t.Errorf("printer received %d bytes, want 0", len(got))
+		}
+	})
+
+	abort := []byte{0x18, common.ESC, '@'}
+	for _, buffered := range []bool{false, true} {
+		name := "canceled mid-stream aborts the job"
+		if buffered {
+			name += " (buffered)"
+		}
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			conn := &cancelingConnector{after: 2, cancel: cancel}
+			prof := profile.CreateProfile80mm()
+			prof.BufferSize = 8
+			p, err := service.NewPrinter(composer.NewEscpos(), prof, conn)
+			if err != nil {
+				t.Fatalf("NewPrinter: %v", err)
+			}
+			executor := document.NewExecutor(p)
+			executor.SetBuffered(buffered)
+
+			err = executor.ExecuteContext(ctx, ticket())
+			testutils.AssertError(t, err, context.Canceled)
+
+			written := conn.Written()
+			if !bytes.HasSuffix(written, abort) {
+				t.Errorf("written ends with % x, want CAN ESC @", written[max(0, len(written)-8):])
+			}
+			if conn.writes != 3 {
+				t.Errorf("printer received %d writes, want 2 before the cancellation and the abort", conn.writes)
+			}
+		})
+	}
+}
@@ -4,12 +4,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 
 	"github.com/adcondev/pos-printer/internal/load"
+	"github.com/adcondev/pos-printer/pkg/commands/barcode"
+	"github.com/adcondev/pos-printer/pkg/commands/buzzer"
 	"github.com/adcondev/pos-printer/pkg/commands/character"
+	"github.com/adcondev/pos-printer/pkg/commands/drawer"
+	"github.com/adcondev/pos-printer/pkg/commands/pdf417"
 	posqr "github.com/adcondev/pos-printer/pkg/commands/qrcode"
 	"github.com/adcondev/pos-printer/pkg/graphics"
+	"github.com/adcondev/pos-printer/pkg/gs1"
 	"github.com/adcondev/pos-printer/pkg/printer"
 	"github.com/adcondev/pos-printer/pkg/tables"
 )
@@ -271,7 +277,7 @@ func (e *Executor) handleSeparator(printer *service.Printer, data json.RawMessag
 	}
 	if cmd.Length == 0 {
 		// Usar ancho del papel en caracteres (aproximado)
-		cmd.Length = e.printer.Profile.DotsPerLine / 12 // Aproximación para Font A
+		cmd.Length = printer.Profile.DotsPerLine / 12 // Aproximación para Font A
 	}
 
 	// Construir línea separadora
@@ -318,6 +324,55 @@ func (e *Executor) handleCut(printer *service.Printer, data json.RawMessage) err
 	}
 }
 
+// handlePulse manages cash drawer pulse commands
+func (e *Executor) handlePulse(printer *service.Printer, data json.RawMessage) error {
+	var cmd PulseCommand
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		return fmt.Errorf("failed to parse pulse command: %w", err)
+	}
+
+	var pin drawer.Pin
+	switch cmd.Pin {
+	case 0, 2:
+		pin = drawer.Pin2
+	case 5:
+		pin = drawer.Pin5
+	default:
+		return fmt.Errorf("invalid drawer pin %d (use 2 or 5)", cmd.Pin)
+	}
+
+	// Valores por defecto
+	if cmd.OnTime == 0 {
+		cmd.OnTime = int(drawer.DefaultOnTime.Milliseconds())
+	}
+	if cmd.OffTime == 0 {
+		cmd.OffTime = int(drawer.DefaultOffTime.Milliseconds())
+	}
+
+	if cmd.Realtime {
+		return printer.OpenDrawerRealtime(pin, cmd.OnTime)
+	}
+	return printer.OpenDrawer(pin, cmd.OnTime, cmd.OffTime)
+}
+
+// handleBeep manages buzzer commands
+func (e *Executor) handleBeep(printer *service.Printer, data json.RawMessage) error {
+	var cmd BeepCommand
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		return fmt.Errorf("failed to parse beep command: %w", err)
+	}
+
+	// Valores por defecto
+	if cmd.Times == 0 {
+		cmd.Times = buzzer.DefaultTimes
+	}
+	if cmd.Duration == 0 {
+		cmd.Duration = int(buzzer.DefaultDuration.Milliseconds())
+	}
+
+	return printer.Beep(cmd.Times, cmd.Duration)
+}
+
 // TODO: Manage text_under and text_above options instead of human_text
 
 // handleQR manges QR code commands
@@ -342,7 +397,7 @@ func (e *Executor) handleQR(printer *service.Printer, data json.RawMessage) erro
 		opts.PixelWidth = cmd.PixelWidth
 	} else {
 		// Usar 50% del ancho del papel por defecto
-		opts.PixelWidth = e.printer.Profile.DotsPerLine / 2
+		opts.PixelWidth = printer.Profile.DotsPerLine / 2
 	}
 
 	// Mapear corrección de errores
@@ -411,6 +466,189 @@ func (e *Executor) handleQR(printer *service.Printer, data json.RawMessage) erro
 	return nil
 }
 
+// barcodeSymbologies mapea los nombres de simbología del JSON (sin guiones ni mayúsculas)
+var barcodeSymbologies = map[string]barcode.Symbology{
+	"upca":                barcode.UPCAB,
+	"upce":                barcode.UPCEB,
+	"ean13":               barcode.EAN13,
+	"jan13":               barcode.EAN13,
+	"ean8":                barcode.EAN8,
+	"jan8":                barcode.EAN8,
+	"code39":              barcode.CODE39B,
+	"itf":                 barcode.ITFB,
+	"codabar":             barcode.CODABARB,
+	"code93":              barcode.CODE93,
+	"code128":             barcode.CODE128,
+	"code128auto":         barcode.CODE128Auto,
+	"gs1128":              barcode.GS1128,
+	"gs1databar":          barcode.GS1DataBarOmni,
+	"gs1databaromni":      barcode.GS1DataBarOmni,
+	"gs1databartruncated": barcode.GS1DataBarTrunc,
+	"gs1databarlimited":   barcode.GS1DataBarLim,
+	"gs1databarexpanded":  barcode.GS1DataBarExp,
+}
+
+// symbologyName normaliza un nombre de simbología: "GS1-128" -> "gs1128"
+var symbologyName = strings.NewReplacer("-", "", "_", "", " ", "")
+
+// handleBarcode manages barcode commands
+func (e *Executor) handleBarcode(printer *service.Printer, data json.RawMessage) error {
+	var cmd BarcodeCommand
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		return fmt.Errorf("failed to parse barcode command: %w", err)
+	}
+
+	// Validación de datos
+	if cmd.Data == "" {
+		return fmt.Errorf("barcode data cannot be empty")
+	}
+	symbology, ok := barcodeSymbologies[symbologyName.Replace(strings.ToLower(cmd.Symbology))]
+	if !ok {
+		return fmt.Errorf("unknown barcode symbology: %q", cmd.Symbology)
+	}
+
+	// Construir opciones
+	opts := graphics.DefaultBarcodeOptions()
+	if cmd.Height > 0 {
+		opts.Height = cmd.Height
+	}
+	if cmd.ModuleWidth != 0 {
+		if cmd.ModuleWidth < int(barcode.MinWidth) || cmd.ModuleWidth > int(barcode.MaxWidth) {
+			return fmt.Errorf("invalid barcode module width: %d (try %d-%d)", cmd.ModuleWidth, barcode.MinWidth, barcode.MaxWidth)
+		}
+		opts.ModuleWidth = cmd.ModuleWidth
+	}
+
+	switch strings.ToLower(cmd.HRIPosition) {
+	case "", "below":
+		opts.HRIPosition = barcode.HRIBelow
+	case "above":
+		opts.HRIPosition = barcode.HRIAbove
+	case "both":
+		opts.HRIPosition = barcode.HRIBoth
+	case "none":
+		opts.HRIPosition = barcode.HRINotPrinted
+	default:
+		return fmt.Errorf("invalid HRI position: %q", cmd.HRIPosition)
+	}
+
+	switch strings.ToUpper(cmd.HRIFont) {
+	case "", "A":
+		opts.HRIFont = barcode.HRIFontA
+	case "B":
+		opts.HRIFont = barcode.HRIFontB
+	default:
+		return fmt.Errorf("invalid HRI font: %q", cmd.HRIFont)
+	}
+
+	// Aplicar alineación
+	var err error
+	switch strings.ToLower(cmd.Align) {
+	case center:
+		err = printer.AlignCenter()
+	case right:
+		err = printer.AlignRight()
+	default:
+		err = printer.AlignLeft()
+	}
+	if err != nil {
+		return err
+	}
+
+	// Los datos GS1 en forma legible "(01)..." se validan y codifican como element string
+	payload := []byte(cmd.Data)
+	if (symbology == barcode.GS1128 || symbology == barcode.GS1DataBarExp) && strings.HasPrefix(cmd.Data, "(") {
+		es, err := gs1.ParseHRI(cmd.Data)
+		if err != nil {
+			return fmt.Errorf("invalid GS1 data: %w", err)
+		}
+		if symbology == barcode.GS1128 {
+			if payload, err = es.Code128(); err != nil {
+				return fmt.Errorf("invalid GS1 data: %w", err)
+			}
+		} else {
+			payload = es.DataBarExpanded()
+		}
+	}
+
+	// Imprimir código de barras
+	if err := printer.PrintBarcode(symbology, payload, opts); err != nil {
+		return err
+	}
+
+	// Restaurar alineación
+	return printer.AlignLeft()
+}
+
+// handlePDF417 manages PDF417 commands
+func (e *Executor) handlePDF417(printer *service.Printer, data json.RawMessage) error {
+	var cmd PDF417Command
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		return fmt.Errorf("failed to parse PDF417 command: %w", err)
+	}
+
+	// Validación de datos
+	if cmd.Data == "" {
+		return fmt.Errorf("PDF417 data cannot be empty")
+	}
+	if len(cmd.Data) > pdf417.MaxDataLength {
+		return fmt.Errorf("PDF417 data too long: %d bytes (maximum %d)", len(cmd.Data), pdf417.MaxDataLength)
+	}
+
+	// Los valores fuera de un byte no se truncan, se rechazan
+	for _, field := range []struct {
+		name  string
+		value int
+	}{
+		{"columns", cmd.Columns},
+		{"rows", cmd.Rows},
+		{"module_width", cmd.ModuleWidth},
+		{"row_height", cmd.RowHeight},
+	} {
+		if field.value < 0 || field.value > 0xFF {
+			return fmt.Errorf("invalid PDF417 %s: %d", field.name, field.value)
+		}
+	}
+
+	// Construir opciones
+	opts := &service.PDF417Options{
+		Columns:     pdf417.Columns(cmd.Columns),
+		Rows:        pdf417.Rows(cmd.Rows),
+		ModuleWidth: pdf417.ModuleWidth(cmd.ModuleWidth),
+		RowHeight:   pdf417.RowHeight(cmd.RowHeight),
+		Truncated:   cmd.Truncated,
+	}
+	if cmd.Correction != "" {
+		level, err := strconv.Atoi(cmd.Correction)
+		if err != nil || level < 0 || level > 8 {
+			return fmt.Errorf("invalid PDF417 correction level: %q (try 0-8)", cmd.Correction)
+		}
+		opts.Level = pdf417.Level0 + pdf417.ErrorCorrection(level)
+	}
+
+	// Aplicar alineación
+	var err error
+	switch strings.ToLower(cmd.Align) {
+	case center:
+		err = printer.AlignCenter()
+	case right:
+		err = printer.AlignRight()
+	default:
+		err = printer.AlignLeft()
+	}
+	if err != nil {
+		return err
+	}
+
+	// Imprimir PDF417
+	if err := printer.PrintPDF417([]byte(cmd.Data), opts); err != nil {
+		return err
+	}
+
+	// Restaurar alineación
+	return printer.AlignLeft()
+}
+
 // TODO: Consider a title fields for tables
 
 // handleTable manages table commands
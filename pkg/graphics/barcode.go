@@ -0,0 +1,242 @@
+package graphics
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/adcondev/pos-printer/pkg/commands/barcode"
+)
+
+// DefaultQuietZone is the blank margin on each side of a barcode image, in modules
+const DefaultQuietZone = 10
+
+// hriGap is the space between the bars and the HRI text, in dots
+const hriGap = 2
+
+var (
+	// ErrBarcodeSymbology indicates a symbology that cannot be rendered as an image
+	ErrBarcodeSymbology = errors.New("barcode symbology not supported as image")
+	// ErrBarcodeData indicates data the symbology cannot encode
+	ErrBarcodeData = errors.New("invalid barcode data")
+	// ErrCheckDigit indicates a check digit in the data that does not match the computed one
+	ErrCheckDigit = errors.New("barcode check digit mismatch")
+)
+
+// BarcodeOptions configures barcodes printed natively (GS k) or rendered as images
+type BarcodeOptions struct {
+	// === Common options ===
+	Height      int                 // Bar height in dots
+	ModuleWidth int                 // Width of the narrowest bar in dots
+	HRIPosition barcode.HRIPosition // Human readable text position
+	HRIFont     barcode.HRIFont     // Human readable text font (native only)
+
+	// === Image options ===
+	QuietZone int // Blank margin on each side in modules (0 = DefaultQuietZone)
+}
+
+// DefaultBarcodeOptions returns the printer defaults: 162 dots high, modules of 3 dots, no HRI
+func DefaultBarcodeOptions() *BarcodeOptions {
+	return &BarcodeOptions{
+		Height:      int(barcode.DefaultHeight),
+		ModuleWidth: int(barcode.DefaultWidth),
+		HRIPosition: barcode.HRINotPrinted,
+		HRIFont:     barcode.HRIFontA,
+	}
+}
+
+// EncodedBarcode is a 1D barcode as a sequence of modules
+type EncodedBarcode struct {
+	Modules []bool // true is a bar; quiet zones are not included
+	Text    string // Human readable text, with computed check digits where they are shown
+}
+
+// Pattern returns the modules as a string of '1' (bar) and '0' (space)
+func (b *EncodedBarcode) Pattern() string {
+	var sb strings.Builder
+	sb.Grow(len(b.Modules))
+	for _, m := range b.Modules {
+		if m {
+			sb.WriteByte('1')
+		} else {
+			sb.WriteByte('0')
+		}
+	}
+	return sb.String()
+}
+
+// EncodeBarcode encodes data in a 1D symbology. The Function A and Function B forms of a
+// symbology are equivalent. Check digits are computed; EAN/UPC data that already ends in
+// a check digit must match it.
+//
+// Supported: UPC-A, UPC-E, EAN-13, EAN-8, CODE39, ITF, CODABAR, CODE93, CODE128 and GS1-128.
+// CODE128 and GS1-128 data may use the ESC/POS escapes of GS k ({A, {B, {C, {S, {1-{4, {{).
+func EncodeBarcode(symbology barcode.Symbology, data []byte) (*EncodedBarcode, error) {
+	if len(data) == 0 {
+		return nil, barcode.ErrDataTooShort
+	}
+	if symbology <= barcode.CODABAR {
+		symbology += barcode.UPCAB
+	}
+
+	switch symbology {
+	case barcode.UPCAB:
+		return encodeUPCA(data)
+	case barcode.UPCEB:
+		return encodeUPCE(data)
+	case barcode.EAN13:
+		return encodeEAN13(data)
+	case barcode.EAN8:
+		return encodeEAN8(data)
+	case barcode.CODE39B:
+		return encodeCode39(data)
+	case barcode.ITFB:
+		return encodeITF(data)
+	case barcode.CODABARB:
+		return encodeCodabar(data)
+	case barcode.CODE93:
+		return encodeCode93(data)
+	case barcode.CODE128, barcode.CODE128Auto:
+		return encodeCode128(data, false)
+	case barcode.GS1128:
+		return encodeCode128(data, true)
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrBarcodeSymbology, symbology)
+	}
+}
+
+// GenerateBarcode renders a barcode as a bitmap with modules of opts.ModuleWidth dots and
+// bars of opts.Height dots. The HRI text is drawn above and/or below the bars as set by
+// opts.HRIPosition.
+func GenerateBarcode(symbology barcode.Symbology, data []byte, opts *BarcodeOptions) (*MonochromeBitmap, error) {
+	if opts == nil {
+		opts = DefaultBarcodeOptions()
+	}
+	if opts.Height <= 0 || opts.ModuleWidth <= 0 {
+		return nil, fmt.Errorf("invalid barcode size: height %d, module width %d", opts.Height, opts.ModuleWidth)
+	}
+	if err := barcode.ValidateHRIPosition(opts.HRIPosition); err != nil {
+		return nil, err
+	}
+
+	encoded, err := EncodeBarcode(symbology, data)
+	if err != nil {
+		return nil, err
+	}
+	return encoded.Render(opts), nil
+}
+
+// Render draws the barcode with the size, quiet zone and HRI position of opts
+func (b *EncodedBarcode) Render(opts *BarcodeOptions) *MonochromeBitmap {
+	quiet := opts.QuietZone
+	if quiet <= 0 {
+		quiet = DefaultQuietZone
+	}
+
+	// The ASCII positions ('0'-'3') are equivalent to the numeric ones
+	hri := opts.HRIPosition & 0x03
+	above := b.Text != "" && (hri == barcode.HRIAbove || hri == barcode.HRIBoth)
+	below := b.Text != "" && (hri == barcode.HRIBelow || hri == barcode.HRIBoth)
+
+	face := basicfont.Face7x13
+	textHeight := face.Height + hriGap
+	textWidth := font.MeasureString(face, b.Text).Ceil()
+
+	width := max((len(b.Modules)+2*quiet)*opts.ModuleWidth, textWidth)
+	height := opts.Height
+	top := 0
+	if above {
+		height += textHeight
+		top = textHeight
+	}
+	if below {
+		height += textHeight
+	}
+
+	bitmap := NewMonochromeBitmap(width, height)
+	x := (width - len(b.Modules)*opts.ModuleWidth) / 2
+	for _, bar := range b.Modules {
+		if bar {
+			for dx := 0; dx < opts.ModuleWidth; dx++ {
+				for y := top; y < top+opts.Height; y++ {
+					bitmap.SetPixel(x+dx, y, true)
+				}
+			}
+		}
+		x += opts.ModuleWidth
+	}
+
+	if above {
+		drawText(bitmap, face, b.Text, textWidth, 0)
+	}
+	if below {
+		drawText(bitmap, face, b.Text, textWidth, top+opts.Height+hriGap)
+	}
+	return bitmap
+}
+
+// drawText draws text centered horizontally with its top at y
+func drawText(bitmap *MonochromeBitmap, face *basicfont.Face, text string, textWidth, y int) {
+	img := image.NewGray(image.Rect(0, 0, textWidth, face.Height))
+	d := font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(color.White),
+		Face: face,
+		Dot:  fixed.P(0, face.Ascent),
+	}
+	d.DrawString(text)
+
+	x0 := (bitmap.Width - textWidth) / 2
+	for ty := 0; ty < face.Height; ty++ {
+		for tx := 0; tx < textWidth; tx++ {
+			if img.GrayAt(tx, ty).Y >= 128 {
+				bitmap.SetPixel(x0+tx, y+ty, true)
+			}
+		}
+	}
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+// modules appends a pattern of '1' (bar) and '0' (space) to dst
+func modules(dst []bool, pattern string) []bool {
+	for _, c := range pattern {
+		dst = append(dst, c == '1')
+	}
+	return dst
+}
+
+// widths appends alternating bars and spaces to dst, starting with a bar; widths are
+// digits in modules
+func widths(dst []bool, pattern string) []bool {
+	for i, c := range pattern {
+		for n := 0; n < int(c-'0'); n++ {
+			dst = append(dst, i%2 == 0)
+		}
+	}
+	return dst
+}
+
+// wideToWidths converts a pattern of narrow (N) and wide (W) elements to module widths,
+// with wide elements three modules wide
+var wideToWidths = strings.NewReplacer("N", "1", "W", "3").Replace
+
+// digits returns the numeric values of data, or ErrBarcodeData if it is not all digits
+func digits(data []byte) ([]int, error) {
+	if !barcode.ValidateNumericData(data) {
+		return nil, fmt.Errorf("%w: %q is not numeric", ErrBarcodeData, data)
+	}
+	d := make([]int, len(data))
+	for i, c := range data {
+		d[i] = int(c - '0')
+	}
+	return d, nil
+}
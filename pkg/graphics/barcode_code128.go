@@ -0,0 +1,196 @@
+package graphics
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adcondev/pos-printer/pkg/commands/barcode"
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+	"github.com/adcondev/pos-printer/pkg/gs1"
+)
+
+// ============================================================================
+// Code 128 and GS1-128
+// ============================================================================
+
+// Code 128 special symbol values
+const (
+	code128FNC3   = 96
+	code128FNC2   = 97
+	code128Shift  = 98
+	code128CodeC  = 99
+	code128CodeB  = 100 // FNC4 in code set B
+	code128CodeA  = 101 // FNC4 in code set A
+	code128FNC1   = 102
+	code128StartA = 103
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+// code128Patterns holds the bar/space widths of the Code 128 values 0-106 (106 is the stop)
+var code128Patterns = [...]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+// encodeCode128 encodes data in the ESC/POS CODE128 format: '{' and a code set (A, B or C)
+// followed by the data, where set C bytes are values 0-99 and '{' escapes select sets
+// ({A {B {C), shift ({S), functions ({1-{4) or a literal brace ({{). Data without the
+// leading code set is encoded with the code sets chosen by barcode.EncodeCode128.
+//
+// GS1-128 (gs1128 = true) starts with FNC1; spaces and parentheses are only shown as text.
+// The text of a valid GS1 element string shows its AIs in parentheses.
+func encodeCode128(data []byte, gs1128 bool) (*EncodedBarcode, error) {
+	if data[0] != '{' {
+		encode := barcode.EncodeCode128
+		if gs1128 {
+			encode = barcode.EncodeGS1128
+		}
+		payload, err := encode(data)
+		if err != nil {
+			return nil, err
+		}
+		data = payload
+	}
+	if len(data) < 2 || data[1] < byte(barcode.Code128SetA) || data[1] > byte(barcode.Code128SetC) {
+		return nil, barcode.ErrCode128NoCodeSet
+	}
+
+	set := data[1]
+	values := []int{code128StartA + int(set-'A')}
+	if gs1128 {
+		values = append(values, code128FNC1)
+	}
+	// raw keeps the GS1 element string, with FNC1 as GS, to show its AIs in the text
+	var text, raw strings.Builder
+	shifted := false
+
+	for i := 2; i < len(data); i++ {
+		c := data[i]
+		if c == '{' {
+			if i+1 == len(data) {
+				return nil, fmt.Errorf("%w: CODE128 data ends in an incomplete escape", ErrBarcodeData)
+			}
+			i++
+			// "{{" is a literal brace; any other escape is a control symbol
+			if data[i] != '{' {
+				v, next, err := code128Escape(data[i], set)
+				if err != nil {
+					return nil, err
+				}
+				if v >= 0 {
+					values = append(values, v)
+				}
+				if v == code128FNC1 {
+					raw.WriteByte(common.GS)
+				}
+				shifted = data[i] == 'S'
+				set = next
+				continue
+			}
+		}
+
+		current := set
+		if shifted {
+			current = 'A' + 'B' - set
+			shifted = false
+		}
+		if gs1128 && current != 'C' && (c == ' ' || c == '(' || c == ')') {
+			text.WriteByte(c)
+			continue
+		}
+		v, err := code128Value(c, current)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		if current == 'C' {
+			fmt.Fprintf(&text, "%02d", c)
+			fmt.Fprintf(&raw, "%02d", c)
+		} else if c >= ' ' && c < 0x7F {
+			text.WriteByte(c)
+			raw.WriteByte(c)
+		}
+	}
+	if shifted {
+		return nil, fmt.Errorf("%w: CODE128 shift without a character", ErrBarcodeData)
+	}
+
+	// Check symbol: start value plus each symbol value times its position, mod 103
+	sum := values[0]
+	for i := 1; i < len(values); i++ {
+		sum += i * values[i]
+	}
+	values = append(values, sum%103, code128Stop)
+
+	var m []bool
+	for _, v := range values {
+		m = widths(m, code128Patterns[v])
+	}
+	if gs1128 {
+		if es, err := gs1.Parse([]byte(raw.String())); err == nil {
+			return &EncodedBarcode{Modules: m, Text: es.HRI()}, nil
+		}
+	}
+	return &EncodedBarcode{Modules: m, Text: text.String()}, nil
+}
+
+// code128Escape returns the symbol value of the escape "{e" in code set set, or -1 when
+// it has none, and the code set that follows it
+func code128Escape(e, set byte) (value int, next byte, err error) {
+	switch e {
+	case 'A', 'B', 'C':
+		if e == set {
+			return -1, set, nil
+		}
+		// CODE A, CODE B and CODE C are consecutive values in reverse order
+		return code128CodeA - int(e-'A'), e, nil
+	case 'S':
+		if set == 'C' {
+			return 0, 0, fmt.Errorf("%w: CODE128 shift is not allowed in code set C", ErrBarcodeData)
+		}
+		return code128Shift, set, nil
+	case '1':
+		return code128FNC1, set, nil
+	case '2':
+		if set != 'C' {
+			return code128FNC2, set, nil
+		}
+	case '3':
+		if set != 'C' {
+			return code128FNC3, set, nil
+		}
+	case '4':
+		// FNC4 shares its value with the code of the current set
+		if set == 'A' {
+			return code128CodeA, set, nil
+		}
+		if set == 'B' {
+			return code128CodeB, set, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("%w: invalid CODE128 escape {%c in code set %c", ErrBarcodeData, e, set)
+}
+
+// code128Value returns the symbol value of a data byte in a code set
+func code128Value(c, set byte) (int, error) {
+	switch {
+	case set == 'A' && c < 32:
+		return int(c) + 64, nil
+	case set == 'A' && c < 96, set == 'B' && c >= 32 && c < 128:
+		return int(c) - 32, nil
+	case set == 'C' && c < 100:
+		return int(c), nil
+	}
+	return 0, fmt.Errorf("%w: byte 0x%02X cannot be encoded in CODE128 code set %c", ErrBarcodeData, c, set)
+}
@@ -0,0 +1,108 @@
+package graphics
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/adcondev/pos-printer/pkg/commands/barcode"
+)
+
+// ============================================================================
+// Code 39 and Code 93
+// ============================================================================
+
+// code39Chars is the character set shared by Code 39 and Code 93, in check digit order
+const code39Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ-. $/+%"
+
+// code39Patterns holds the narrow/wide bar-space sequence of each character of code39Chars
+var code39Patterns = [...]string{
+	"NNNWWNWNN", "WNNWNNNNW", "NNWWNNNNW", "WNWWNNNNN", "NNNWWNNNW",
+	"WNNWWNNNN", "NNWWWNNNN", "NNNWNNWNW", "WNNWNNWNN", "NNWWNNWNN",
+	"WNNNNWNNW", "NNWNNWNNW", "WNWNNWNNN", "NNNNWWNNW", "WNNNWWNNN",
+	"NNWNWWNNN", "NNNNNWWNW", "WNNNNWWNN", "NNWNNWWNN", "NNNNWWWNN",
+	"WNNNNNNWW", "NNWNNNNWW", "WNWNNNNWN", "NNNNWNNWW", "WNNNWNNWN",
+	"NNWNWNNWN", "NNNNNNWWW", "WNNNNNWWN", "NNWNNNWWN", "NNNNWNWWN",
+	"WWNNNNNNW", "NWWNNNNNW", "WWWNNNNNN", "NWNNWNNNW", "WWNNWNNNN",
+	"NWWNWNNNN", "NWNNNNWNW", "WWNNNNWNN", "NWWNNNWNN", "NWNWNWNNN",
+	"NWNWNNNWN", "NWNNNWNWN", "NNNWNWNWN",
+}
+
+// code39StartStop is the pattern of the '*' start/stop character
+const code39StartStop = "NWNNWNWNN"
+
+// encodeCode39 encodes data between '*' start/stop characters, which are added if the
+// data does not include them. Like GS k, no check digit is added.
+func encodeCode39(data []byte) (*EncodedBarcode, error) {
+	if !barcode.ValidateCode39Data(data) {
+		return nil, fmt.Errorf("%w: %q has characters outside CODE39", ErrBarcodeData, data)
+	}
+	content := bytes.TrimSuffix(bytes.TrimPrefix(data, []byte{'*'}), []byte{'*'})
+	if bytes.IndexByte(content, '*') >= 0 {
+		return nil, fmt.Errorf("%w: '*' is only allowed as start/stop character", ErrBarcodeData)
+	}
+
+	m := widths(nil, wideToWidths(code39StartStop))
+	for _, c := range content {
+		// Characters are separated by a narrow space
+		m = append(m, false)
+		m = widths(m, wideToWidths(code39Patterns[strings.IndexByte(code39Chars, c)]))
+	}
+	m = append(m, false)
+	m = widths(m, wideToWidths(code39StartStop))
+	return &EncodedBarcode{Modules: m, Text: "*" + string(content) + "*"}, nil
+}
+
+// code93Patterns holds the 9 module patterns of the Code 93 values 0-46: the characters
+// of code39Chars followed by the shift characters ($) (%) (/) (+)
+var code93Patterns = [...]string{
+	"100010100", "101001000", "101000100", "101000010", "100101000",
+	"100100100", "100100010", "101010000", "100010010", "100001010",
+	"110101000", "110100100", "110100010", "110010100", "110010010",
+	"110001010", "101101000", "101100100", "101100010", "100110100",
+	"100011010", "101011000", "101001100", "101000110", "100101100",
+	"100010110", "110110100", "110110010", "110101100", "110100110",
+	"110010110", "110011010", "101101100", "101100110", "100110110",
+	"100111010", "100101110", "111010100", "111010010", "111001010",
+	"101101110", "101110110", "110101110", "100100110", "111011010",
+	"111010110", "100110010",
+}
+
+// Code 93 start/stop pattern and termination bar
+const (
+	code93StartStop   = "101011110"
+	code93Termination = "1"
+)
+
+// encodeCode93 encodes data with the two mandatory check characters C and K
+func encodeCode93(data []byte) (*EncodedBarcode, error) {
+	values := make([]int, 0, len(data)+2)
+	for _, c := range data {
+		v := strings.IndexByte(code39Chars, c)
+		if v < 0 {
+			return nil, fmt.Errorf("%w: %q has characters outside CODE93", ErrBarcodeData, data)
+		}
+		values = append(values, v)
+	}
+	values = append(values, code93Check(values, 20))
+	values = append(values, code93Check(values, 15))
+
+	m := modules(nil, code93StartStop)
+	for _, v := range values {
+		m = modules(m, code93Patterns[v])
+	}
+	m = modules(m, code93StartStop)
+	m = modules(m, code93Termination)
+	return &EncodedBarcode{Modules: m, Text: string(data)}, nil
+}
+
+// code93Check computes a check character: weights count from 1 at the rightmost value up
+// to maxWeight and then restart
+func code93Check(values []int, maxWeight int) int {
+	sum := 0
+	for i := range values {
+		weight := (len(values)-1-i)%maxWeight + 1
+		sum += weight * values[i]
+	}
+	return sum % 47
+}
@@ -0,0 +1,239 @@
+package graphics
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// EAN-13, EAN-8, UPC-A and UPC-E
+// ============================================================================
+
+// Guard patterns
+const (
+	eanGuard       = "101"
+	eanCenterGuard = "01010"
+	upcEEndGuard   = "010101"
+)
+
+// eanLCodes holds the odd parity (L) codes of the digits; R codes are their complement
+// and G codes the reverse of the R codes
+var eanLCodes = [10]string{
+	"0001101", "0011001", "0010011", "0111101", "0100011",
+	"0110001", "0101111", "0111011", "0110111", "0001011",
+}
+
+// eanParity holds the L/G pattern of the left half of EAN-13 for each first digit
+var eanParity = [10]string{
+	"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG",
+	"LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL",
+}
+
+// upcEParity holds the parity of the UPC-E digits for each check digit with number
+// system 0 (E = even, G code; O = odd, L code); number system 1 uses the inverse
+var upcEParity = [10]string{
+	"EEEOOO", "EEOEOO", "EEOOEO", "EEOOOE", "EOEEOO",
+	"EOOEEO", "EOOOEE", "EOEOEO", "EOEOOE", "EOOEOE",
+}
+
+// eanCode returns the L, G or R code of a digit
+func eanCode(digit int, set byte) string {
+	l := eanLCodes[digit]
+	if set == 'L' {
+		return l
+	}
+	r := strings.Map(func(c rune) rune { return '0' + '1' - c }, l)
+	if set == 'R' {
+		return r
+	}
+	g := []byte(r)
+	for i, j := 0, len(g)-1; i < j; i, j = i+1, j-1 {
+		g[i], g[j] = g[j], g[i]
+	}
+	return string(g)
+}
+
+// eanCheckDigit computes the mod 10 check digit of an EAN/UPC number without its check digit
+func eanCheckDigit(d []int) int {
+	sum := 0
+	for i := range d {
+		// Weights 3 and 1 alternate starting with 3 at the rightmost digit
+		if (len(d)-i)%2 == 1 {
+			sum += 3 * d[i]
+		} else {
+			sum += d[i]
+		}
+	}
+	return (10 - sum%10) % 10
+}
+
+// eanDigits parses data of n-1 digits, appending the check digit, or of n digits whose
+// last digit must be the check digit
+func eanDigits(name string, data []byte, n int) ([]int, error) {
+	if len(data) != n-1 && len(data) != n {
+		return nil, fmt.Errorf("%w: %s needs %d or %d digits, got %d", ErrBarcodeData, name, n-1, n, len(data))
+	}
+	d, err := digits(data)
+	if err != nil {
+		return nil, err
+	}
+	check := eanCheckDigit(d[:n-1])
+	if len(d) == n {
+		if d[n-1] != check {
+			return nil, fmt.Errorf("%w: %s %s ends in %d, want %d", ErrCheckDigit, name, data, d[n-1], check)
+		}
+		return d, nil
+	}
+	return append(d, check), nil
+}
+
+// digitString formats digits as text
+func digitString(d []int) string {
+	b := make([]byte, len(d))
+	for i, v := range d {
+		b[i] = byte('0' + v)
+	}
+	return string(b)
+}
+
+func encodeEAN13(data []byte) (*EncodedBarcode, error) {
+	d, err := eanDigits("EAN-13", data, 13)
+	if err != nil {
+		return nil, err
+	}
+	return &EncodedBarcode{Modules: ean13Modules(d), Text: digitString(d)}, nil
+}
+
+// ean13Modules encodes 13 digits; the first one is carried by the parity of the left half
+func ean13Modules(d []int) []bool {
+	m := modules(make([]bool, 0, 95), eanGuard)
+	parity := eanParity[d[0]]
+	for i := 1; i <= 6; i++ {
+		m = modules(m, eanCode(d[i], parity[i-1]))
+	}
+	m = modules(m, eanCenterGuard)
+	for i := 7; i <= 12; i++ {
+		m = modules(m, eanCode(d[i], 'R'))
+	}
+	return modules(m, eanGuard)
+}
+
+func encodeEAN8(data []byte) (*EncodedBarcode, error) {
+	d, err := eanDigits("EAN-8", data, 8)
+	if err != nil {
+		return nil, err
+	}
+
+	m := modules(make([]bool, 0, 67), eanGuard)
+	for i := 0; i < 4; i++ {
+		m = modules(m, eanCode(d[i], 'L'))
+	}
+	m = modules(m, eanCenterGuard)
+	for i := 4; i < 8; i++ {
+		m = modules(m, eanCode(d[i], 'R'))
+	}
+	m = modules(m, eanGuard)
+	return &EncodedBarcode{Modules: m, Text: digitString(d)}, nil
+}
+
+func encodeUPCA(data []byte) (*EncodedBarcode, error) {
+	d, err := eanDigits("UPC-A", data, 12)
+	if err != nil {
+		return nil, err
+	}
+	// UPC-A is EAN-13 with a leading zero
+	return &EncodedBarcode{Modules: ean13Modules(append([]int{0}, d...)), Text: digitString(d)}, nil
+}
+
+// encodeUPCE accepts the 6 compressed digits (number system 0), the number system and the
+// 6 digits, those plus the check digit, or the 11 or 12 digit UPC-A form of the number
+func encodeUPCE(data []byte) (*EncodedBarcode, error) {
+	d, err := digits(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var upcA []int
+	switch len(d) {
+	case 6:
+		upcA = upcEExpand(0, d)
+	case 7, 8:
+		upcA = upcEExpand(d[0], d[1:7])
+	case 11, 12:
+		upcA = d[:11]
+	default:
+		return nil, fmt.Errorf("%w: UPC-E needs 6-8, 11 or 12 digits, got %d", ErrBarcodeData, len(d))
+	}
+	if upcA[0] > 1 {
+		return nil, fmt.Errorf("%w: UPC-E number system must be 0 or 1, got %d", ErrBarcodeData, upcA[0])
+	}
+
+	check := eanCheckDigit(upcA)
+	if (len(d) == 8 || len(d) == 12) && d[len(d)-1] != check {
+		return nil, fmt.Errorf("%w: UPC-E %s ends in %d, want %d", ErrCheckDigit, data, d[len(d)-1], check)
+	}
+	compressed, ok := upcECompress(upcA)
+	if !ok {
+		return nil, fmt.Errorf("%w: UPC-A %s cannot be compressed to UPC-E", ErrBarcodeData, digitString(upcA))
+	}
+
+	parity := upcEParity[check]
+	m := modules(make([]bool, 0, 51), eanGuard)
+	for i, digit := range compressed {
+		even := parity[i] == 'E'
+		if upcA[0] == 1 {
+			even = !even
+		}
+		if even {
+			m = modules(m, eanCode(digit, 'G'))
+		} else {
+			m = modules(m, eanCode(digit, 'L'))
+		}
+	}
+	m = modules(m, upcEEndGuard)
+
+	text := digitString(append(append([]int{upcA[0]}, compressed...), check))
+	return &EncodedBarcode{Modules: m, Text: text}, nil
+}
+
+// upcEExpand returns the 11 UPC-A digits, without check digit, of a UPC-E number
+func upcEExpand(system int, e []int) []int {
+	a := []int{system}
+	switch e[5] {
+	case 0, 1, 2:
+		a = append(a, e[0], e[1], e[5], 0, 0, 0, 0, e[2], e[3], e[4])
+	case 3:
+		a = append(a, e[0], e[1], e[2], 0, 0, 0, 0, 0, e[3], e[4])
+	case 4:
+		a = append(a, e[0], e[1], e[2], e[3], 0, 0, 0, 0, 0, e[4])
+	default:
+		a = append(a, e[0], e[1], e[2], e[3], e[4], 0, 0, 0, 0, e[5])
+	}
+	return a
+}
+
+// upcECompress returns the 6 UPC-E digits of an 11 digit UPC-A number, if it has one
+func upcECompress(a []int) ([]int, bool) {
+	m, p := a[1:6], a[6:11]
+	var e []int
+	switch {
+	case m[2] <= 2 && m[3] == 0 && m[4] == 0 && p[0] == 0 && p[1] == 0:
+		e = []int{m[0], m[1], p[2], p[3], p[4], m[2]}
+	case m[3] == 0 && m[4] == 0 && p[0] == 0 && p[1] == 0 && p[2] == 0:
+		e = []int{m[0], m[1], m[2], p[3], p[4], 3}
+	case m[4] == 0 && p[0] == 0 && p[1] == 0 && p[2] == 0 && p[3] == 0:
+		e = []int{m[0], m[1], m[2], m[3], p[4], 4}
+	case p[0] == 0 && p[1] == 0 && p[2] == 0 && p[3] == 0 && p[4] >= 5:
+		e = []int{m[0], m[1], m[2], m[3], m[4], p[4]}
+	default:
+		return nil, false
+	}
+	// Round trip to reject numbers whose compressed form expands differently
+	expanded := upcEExpand(a[0], e)
+	for i := range expanded {
+		if expanded[i] != a[i] {
+			return nil, false
+		}
+	}
+	return e, true
+}
@@ -0,0 +1,81 @@
+package graphics
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/adcondev/pos-printer/pkg/commands/barcode"
+)
+
+// ============================================================================
+// ITF and Codabar
+// ============================================================================
+
+// itfPatterns holds the narrow/wide sequence of each digit in Interleaved 2 of 5
+var itfPatterns = [10]string{
+	"NNWWN", "WNNNW", "NWNNW", "WWNNN", "NNWNW",
+	"WNWNN", "NWWNN", "NNNWW", "WNNWN", "NWNWN",
+}
+
+// ITF start and stop bar-space sequences
+const (
+	itfStart = "NNNN"
+	itfStop  = "WNN"
+)
+
+// encodeITF encodes pairs of digits: the first digit of each pair in the bars and the
+// second one in the spaces
+func encodeITF(data []byte) (*EncodedBarcode, error) {
+	if len(data)%2 != 0 {
+		return nil, barcode.ErrOddITFLength
+	}
+	d, err := digits(data)
+	if err != nil {
+		return nil, err
+	}
+
+	m := widths(nil, wideToWidths(itfStart))
+	pair := make([]byte, 10)
+	for i := 0; i < len(d); i += 2 {
+		bars, spaces := itfPatterns[d[i]], itfPatterns[d[i+1]]
+		for j := 0; j < 5; j++ {
+			pair[2*j], pair[2*j+1] = bars[j], spaces[j]
+		}
+		m = widths(m, wideToWidths(string(pair)))
+	}
+	m = widths(m, wideToWidths(itfStop))
+	return &EncodedBarcode{Modules: m, Text: string(data)}, nil
+}
+
+// codabarChars is the Codabar character set
+const codabarChars = "0123456789-$:/.+ABCD"
+
+// codabarPatterns holds the narrow/wide bar-space sequence of each character of codabarChars
+var codabarPatterns = [...]string{
+	"NNNNNWW", "NNNNWWN", "NNNWNNW", "WWNNNNN", "NNWNNWN",
+	"WNNNNWN", "NWNNNNW", "NWNNWNN", "NWWNNNN", "WNNWNNN",
+	"NNNWWNN", "NNWWNNN", "WNNNWNW", "WNWNNNW", "WNWNWNN",
+	"NNWNWNW", "NNWWNWN", "NWNWNNW", "NNNWNWW", "NNNWWWN",
+}
+
+// encodeCodabar encodes data that starts and ends with one of the A-D start/stop characters
+func encodeCodabar(data []byte) (*EncodedBarcode, error) {
+	if !barcode.ValidateCodabarData(data) {
+		return nil, fmt.Errorf("%w: CODABAR %q needs A-D start/stop characters and 0-9 - $ : / . +", ErrBarcodeData, data)
+	}
+	content := bytes.ToUpper(data)
+
+	var m []bool
+	for i, c := range content {
+		v := bytes.IndexByte([]byte(codabarChars), c)
+		if v < 0 || (v >= 16 && i > 0 && i < len(content)-1) {
+			return nil, fmt.Errorf("%w: CODABAR %q has an invalid character %q", ErrBarcodeData, data, c)
+		}
+		if i > 0 {
+			// Characters are separated by a narrow space
+			m = append(m, false)
+		}
+		m = widths(m, wideToWidths(codabarPatterns[v]))
+	}
+	return &EncodedBarcode{Modules: m, Text: string(content)}, nil
+}
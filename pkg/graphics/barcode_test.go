@@ -0,0 +1,281 @@
+package graphics_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/adcondev/pos-printer/pkg/commands/barcode"
+	"github.com/adcondev/pos-printer/pkg/graphics"
+)
+
+// ============================================================================
+// Encoding Tests
+// ============================================================================
+
+func TestEncodeBarcode_ReferencePatterns(t *testing.T) {
+	// Reference module patterns; CODE39 and CODABAR use wide elements of three modules
+	tests := []struct {
+		name      string
+		symbology barcode.Symbology
+		data      string
+		want      string
+		wantText  string
+	}{
+		{
+			name:      "EAN-13 with check digit",
+			symbology: barcode.EAN13,
+			data:      "4006381333931",
+			want:      "10100011010100111010111101111010001001011001101010100001010000101000010111010010000101100110101",
+			wantText:  "4006381333931",
+		},
+		{
+			name:      "EAN-13 computes check digit",
+			symbology: barcode.JAN13,
+			data:      "400638133393",
+			want:      "10100011010100111010111101111010001001011001101010100001010000101000010111010010000101100110101",
+			wantText:  "4006381333931",
+		},
+		{
+			name:      "EAN-8",
+			symbology: barcode.EAN8,
+			data:      "9638507",
+			want:      "1010001011010111101111010110111010101001110111001010001001011100101",
+			wantText:  "96385074",
+		},
+		{
+			name:      "UPC-A",
+			symbology: barcode.UPCA,
+			data:      "03600029145",
+			want:      "10100011010111101010111100011010001101000110101010110110011101001100110101110010011101101100101",
+			wantText:  "036000291452",
+		},
+		{
+			name:      "UPC-E",
+			symbology: barcode.UPCEB,
+			data:      "0123456",
+			want:      "101011001100100110111101001110101110010101111010101",
+			wantText:  "01234565",
+		},
+		{
+			name:      "UPC-E from UPC-A",
+			symbology: barcode.UPCE,
+			data:      "012345000065",
+			want:      "101011001100100110111101001110101110010101111010101",
+			wantText:  "01234565",
+		},
+		{
+			name:      "CODE39",
+			symbology: barcode.CODE39,
+			data:      "CODE 39",
+			want:      "10001011101110101110111010001010111010111010001010101110001011101110101110001010100011101011101011101110001010101011100010111010100010111011101",
+			wantText:  "*CODE 39*",
+		},
+		{
+			name:      "CODE93 with check characters",
+			symbology: barcode.CODE93,
+			data:      "TEST93",
+			want:      "1010111101101001101100100101101011001101001101000010101010000101011101101001000101010111101",
+			wantText:  "TEST93",
+		},
+		{
+			name:      "CODE128 set B",
+			symbology: barcode.CODE128,
+			data:      "{BHello",
+			want:      "110100100001100010100010110010000110010100001100101000010001111010110010100001100011101011",
+			wantText:  "Hello",
+		},
+		{
+			name:      "CODE128 plain digits use set C",
+			symbology: barcode.CODE128Auto,
+			data:      "123456",
+			want:      "11010011100101100111001000101100011100010110100011011101100011101011",
+			wantText:  "123456",
+		},
+		{
+			name:      "CODE128 code set change",
+			symbology: barcode.CODE128,
+			data:      "{BNo.{C\x0c\x22\x38",
+			want:      "1101001000010111000110100011110101001100111010111011110101100111001000101100011100010110101001100001100011101011",
+			wantText:  "No.123456",
+		},
+		{
+			name:      "GS1-128 starts with FNC1",
+			symbology: barcode.GS1128,
+			data:      "{C\x01\x09\x32\x0b\x01\x35\x00\x03",
+			want:      "11010011100111101011101100110110011001001000110001011101100010010011001101100110111011101101100110010010011000100110100001100011101011",
+			wantText:  "(01)09501101530003",
+		},
+		{
+			name:      "ITF",
+			symbology: barcode.ITF,
+			data:      "12345670",
+			want:      "101011101000101011100011101110100010100011101000111000101010101000111000111011101",
+			wantText:  "12345670",
+		},
+		{
+			name:      "CODABAR",
+			symbology: barcode.CODABARB,
+			data:      "a40156b",
+			want:      "101110001000101011101000101010100011101010111000101110101000101000101011101000100010111",
+			wantText:  "A40156B",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := graphics.EncodeBarcode(tt.symbology, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("EncodeBarcode(%q) error = %v", tt.data, err)
+			}
+			if got.Pattern() != tt.want {
+				t.Errorf("EncodeBarcode(%q) pattern =\n%s\nwant\n%s", tt.data, got.Pattern(), tt.want)
+			}
+			if got.Text != tt.wantText {
+				t.Errorf("EncodeBarcode(%q) text = %q, want %q", tt.data, got.Text, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestEncodeBarcode_GS1Text(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantText string
+	}{
+		{"element string with FNC1", "10ABC\x1d3103001250", "(10)ABC(3103)001250"},
+		{"payload with FNC1 escape", "{B10ABC{1{C\x1f\x03\x00\x0c\x32", "(10)ABC(3103)001250"},
+		{"not an element string", "{BHELLO", "HELLO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := graphics.EncodeBarcode(barcode.GS1128, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("EncodeBarcode(%q) error = %v", tt.data, err)
+			}
+			if got.Text != tt.wantText {
+				t.Errorf("EncodeBarcode(%q) text = %q, want %q", tt.data, got.Text, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestEncodeBarcode_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		symbology barcode.Symbology
+		data      string
+		wantErr   error
+	}{
+		{"empty data", barcode.EAN13, "", barcode.ErrDataTooShort},
+		{"EAN-13 wrong check digit", barcode.EAN13, "4006381333932", graphics.ErrCheckDigit},
+		{"EAN-8 wrong check digit", barcode.EAN8, "96385070", graphics.ErrCheckDigit},
+		{"UPC-A wrong check digit", barcode.UPCA, "036000291453", graphics.ErrCheckDigit},
+		{"UPC-E wrong check digit", barcode.UPCE, "01234564", graphics.ErrCheckDigit},
+		{"UPC-E not compressible", barcode.UPCE, "01234567890", graphics.ErrBarcodeData},
+		{"EAN-13 wrong length", barcode.EAN13, "12345", graphics.ErrBarcodeData},
+		{"EAN-13 not numeric", barcode.EAN13, "40063813339A", graphics.ErrBarcodeData},
+		{"CODE39 lowercase", barcode.CODE39, "abc", graphics.ErrBarcodeData},
+		{"CODE93 invalid character", barcode.CODE93, "A#B", graphics.ErrBarcodeData},
+		{"ITF odd length", barcode.ITF, "12345", barcode.ErrOddITFLength},
+		{"CODABAR without start/stop", barcode.CODABAR, "40156", graphics.ErrBarcodeData},
+		{"CODE128 set C value over 99", barcode.CODE128, "{C\x64", graphics.ErrBarcodeData},
+		{"CODE128 lowercase in set A", barcode.CODE128, "{Aabc", graphics.ErrBarcodeData},
+		{"CODE128 invalid escape", barcode.CODE128, "{BA{X", graphics.ErrBarcodeData},
+		{"CODE128 incomplete escape", barcode.CODE128, "{BA{", graphics.ErrBarcodeData},
+		{"CODE128 without code set", barcode.CODE128, "{", barcode.ErrCode128NoCodeSet},
+		{"GS1 DataBar", barcode.GS1DataBarOmni, "0123456789012", graphics.ErrBarcodeSymbology},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := graphics.EncodeBarcode(tt.symbology, []byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("EncodeBarcode(%q) error = %v, want %v", tt.data, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// ============================================================================
+// Rendering Tests
+// ============================================================================
+
+func TestGenerateBarcode_Size(t *testing.T) {
+	const height, moduleWidth = 50, 2
+	encoded, err := graphics.EncodeBarcode(barcode.EAN13, []byte("4006381333931"))
+	if err != nil {
+		t.Fatalf("EncodeBarcode() error = %v", err)
+	}
+	wantWidth := (len(encoded.Modules) + 2*graphics.DefaultQuietZone) * moduleWidth
+
+	tests := []struct {
+		name       string
+		hri        barcode.HRIPosition
+		wantHeight func(int) bool
+	}{
+		{"no HRI", barcode.HRINotPrinted, func(h int) bool { return h == height }},
+		{"HRI below", barcode.HRIBelow, func(h int) bool { return h > height }},
+		{"HRI both", barcode.HRIBothASCII, func(h int) bool { return h > height+13 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &graphics.BarcodeOptions{Height: height, ModuleWidth: moduleWidth, HRIPosition: tt.hri}
+			bitmap, err := graphics.GenerateBarcode(barcode.EAN13, []byte("4006381333931"), opts)
+			if err != nil {
+				t.Fatalf("GenerateBarcode() error = %v", err)
+			}
+			if bitmap.Width != wantWidth {
+				t.Errorf("width = %d, want %d", bitmap.Width, wantWidth)
+			}
+			if !tt.wantHeight(bitmap.Height) {
+				t.Errorf("height = %d for HRI %d", bitmap.Height, tt.hri)
+			}
+		})
+	}
+}
+
+func TestGenerateBarcode_Modules(t *testing.T) {
+	opts := &graphics.BarcodeOptions{Height: 4, ModuleWidth: 3, QuietZone: 1}
+	bitmap, err := graphics.GenerateBarcode(barcode.EAN8, []byte("96385074"), opts)
+	if err != nil {
+		t.Fatalf("GenerateBarcode() error = %v", err)
+	}
+	encoded, _ := graphics.EncodeBarcode(barcode.EAN8, []byte("96385074"))
+
+	// Every row repeats each module ModuleWidth times after the quiet zone
+	for y := 0; y < bitmap.Height; y++ {
+		for i, bar := range encoded.Modules {
+			for dx := 0; dx < opts.ModuleWidth; dx++ {
+				x := (i+opts.QuietZone)*opts.ModuleWidth + dx
+				if bitmap.GetPixel(x, y) != bar {
+					t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, !bar, bar)
+				}
+			}
+		}
+	}
+	if bitmap.GetPixel(0, 0) || bitmap.GetPixel(bitmap.Width-1, 0) {
+		t.Error("quiet zone has bars")
+	}
+}
+
+func TestGenerateBarcode_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *graphics.BarcodeOptions
+	}{
+		{"zero height", &graphics.BarcodeOptions{Height: 0, ModuleWidth: 2}},
+		{"zero module width", &graphics.BarcodeOptions{Height: 50, ModuleWidth: 0}},
+		{"invalid HRI position", &graphics.BarcodeOptions{Height: 50, ModuleWidth: 2, HRIPosition: 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := graphics.GenerateBarcode(barcode.EAN8, []byte("9638507"), tt.opts); err == nil {
+				t.Error("GenerateBarcode() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package graphics
+
+import (
+	"fmt"
+	"image/color"
+
+	bpdf417 "github.com/boombuler/barcode/pdf417"
+)
+
+const (
+	// pdf417SourceRowHeight is the height in pixels of each row in the encoder image
+	pdf417SourceRowHeight = 2
+	// pdf417QuietZone is the blank margin around the symbol, in modules
+	pdf417QuietZone = 2
+	// pdf417MaxLevel is the highest error correction level (512 codewords)
+	pdf417MaxLevel = 8
+)
+
+// GeneratePDF417 renders data as a PDF417 symbol with error correction level (0-8), modules
+// of moduleWidth dots and rows of rowHeight modules, as set by GS ( k <Function 067> and
+// <Function 068> on printers with native support. The encoder picks the number of columns
+// and rows, and the symbol is always standard (not truncated).
+func GeneratePDF417(data []byte, level, moduleWidth, rowHeight int) (*MonochromeBitmap, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("PDF417 data cannot be empty")
+	}
+	if level < 0 || level > pdf417MaxLevel {
+		return nil, fmt.Errorf("invalid PDF417 error correction level: %d", level)
+	}
+	if moduleWidth <= 0 || rowHeight <= 0 {
+		return nil, fmt.Errorf("invalid PDF417 size: module width %d, row height %d", moduleWidth, rowHeight)
+	}
+
+	symbol, err := bpdf417.Encode(string(data), byte(level))
+	if err != nil {
+		return nil, fmt.Errorf("encode PDF417: %w", err)
+	}
+
+	bounds := symbol.Bounds()
+	columns := bounds.Dx()
+	rows := bounds.Dy() / pdf417SourceRowHeight
+	quiet := pdf417QuietZone * moduleWidth
+	dotsPerRow := moduleWidth * rowHeight
+
+	bitmap := NewMonochromeBitmap(columns*moduleWidth+2*quiet, rows*dotsPerRow+2*quiet)
+	for row := 0; row < rows; row++ {
+		y := bounds.Min.Y + row*pdf417SourceRowHeight
+		for col := 0; col < columns; col++ {
+			if !isDark(symbol.At(bounds.Min.X+col, y)) {
+				continue
+			}
+			for dy := 0; dy < dotsPerRow; dy++ {
+				for dx := 0; dx < moduleWidth; dx++ {
+					bitmap.SetPixel(quiet+col*moduleWidth+dx, quiet+row*dotsPerRow+dy, true)
+				}
+			}
+		}
+	}
+	return bitmap, nil
+}
+
+// isDark reports whether c is closer to black than to white
+func isDark(c color.Color) bool {
+	gray, _, _, _ := color.GrayModel.Convert(c).RGBA()
+	return gray < 0x8000
+}
@@ -0,0 +1,77 @@
+package graphics_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/adcondev/pos-printer/pkg/graphics"
+)
+
+func TestGeneratePDF417_Layout(t *testing.T) {
+	const (
+		moduleWidth = 2
+		rowHeight   = 3
+		quiet       = 2 * moduleWidth
+		start       = "11111111010101000"
+		stop        = "111111101000101001"
+	)
+
+	bitmap, err := graphics.GeneratePDF417([]byte("TRACK-0042"), 2, moduleWidth, rowHeight)
+	if err != nil {
+		t.Fatalf("GeneratePDF417() error = %v", err)
+	}
+
+	// Every row is a start pattern, row indicators and data codewords of 17 modules and a stop pattern
+	modules := (bitmap.Width - 2*quiet) / moduleWidth
+	if (modules-len(start)-len(stop))%17 != 0 {
+		t.Fatalf("symbol is %d modules wide, want start + n×17 + stop", modules)
+	}
+	dotsPerRow := moduleWidth * rowHeight
+	if (bitmap.Height-2*quiet)%dotsPerRow != 0 || bitmap.Height-2*quiet < 3*dotsPerRow {
+		t.Fatalf("symbol is %d dots high, want at least 3 rows of %d dots", bitmap.Height, dotsPerRow)
+	}
+
+	for y := quiet; y < bitmap.Height-quiet; y += dotsPerRow {
+		var row strings.Builder
+		for x := quiet; x < bitmap.Width-quiet; x += moduleWidth {
+			if bitmap.GetPixel(x, y) {
+				row.WriteByte('1')
+			} else {
+				row.WriteByte('0')
+			}
+		}
+		if got := row.String(); !strings.HasPrefix(got, start) || !strings.HasSuffix(got, stop) {
+			t.Fatalf("row at y=%d = %s, want start and stop patterns", y, got)
+		}
+	}
+
+	for x := 0; x < bitmap.Width; x++ {
+		if bitmap.GetPixel(x, 0) || bitmap.GetPixel(x, bitmap.Height-1) {
+			t.Fatalf("quiet zone has a black dot at x=%d", x)
+		}
+	}
+}
+
+func TestGeneratePDF417_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		level       int
+		moduleWidth int
+		rowHeight   int
+	}{
+		{name: "empty data", data: "", level: 1, moduleWidth: 3, rowHeight: 3},
+		{name: "level too high", data: "A", level: 9, moduleWidth: 3, rowHeight: 3},
+		{name: "negative level", data: "A", level: -1, moduleWidth: 3, rowHeight: 3},
+		{name: "zero module width", data: "A", level: 1, moduleWidth: 0, rowHeight: 3},
+		{name: "zero row height", data: "A", level: 1, moduleWidth: 3, rowHeight: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := graphics.GeneratePDF417([]byte(tt.data), tt.level, tt.moduleWidth, tt.rowHeight); err == nil {
+				t.Error("GeneratePDF417() expected error")
+			}
+		})
+	}
+}
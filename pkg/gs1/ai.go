@@ -0,0 +1,171 @@
+package gs1
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnknownAI indicates an application identifier without a definition
+	ErrUnknownAI = errors.New("unknown GS1 application identifier")
+	// ErrLength indicates data too short or too long for its AI
+	ErrLength = errors.New("invalid GS1 data length")
+	// ErrCharset indicates characters the AI does not allow
+	ErrCharset = errors.New("invalid GS1 data characters")
+	// ErrCheckDigit indicates a GS1 check digit that does not match the data
+	ErrCheckDigit = errors.New("GS1 check digit mismatch")
+	// ErrDate indicates an invalid YYMMDD date
+	ErrDate = errors.New("invalid GS1 date")
+	// ErrTooLong indicates an element string longer than the symbology allows
+	ErrTooLong = errors.New("GS1 element string too long")
+)
+
+// Definition describes the data of an application identifier
+type Definition struct {
+	AI        string // Application identifier; a trailing 'n' is the decimal point position digit
+	Title     string // GS1 data title
+	Numeric   bool   // Digits only; otherwise CSET 82
+	MinLength int    // Minimum data length
+	MaxLength int    // Maximum data length; equal to MinLength for fixed length data
+	Check     bool   // The last digit is a GS1 check digit
+	Date      bool   // The data is a YYMMDD date
+}
+
+// Fixed reports whether the data has a fixed length
+func (d *Definition) Fixed() bool {
+	return d.MinLength == d.MaxLength
+}
+
+// definitions holds the supported application identifiers
+var definitions = []Definition{
+	{AI: "00", Title: "SSCC", Numeric: true, MinLength: 18, MaxLength: 18, Check: true},
+	{AI: "01", Title: "GTIN", Numeric: true, MinLength: 14, MaxLength: 14, Check: true},
+	{AI: "02", Title: "CONTENT", Numeric: true, MinLength: 14, MaxLength: 14, Check: true},
+	{AI: "10", Title: "BATCH/LOT", MinLength: 1, MaxLength: 20},
+	{AI: "11", Title: "PROD DATE", Numeric: true, MinLength: 6, MaxLength: 6, Date: true},
+	{AI: "12", Title: "DUE DATE", Numeric: true, MinLength: 6, MaxLength: 6, Date: true},
+	{AI: "13", Title: "PACK DATE", Numeric: true, MinLength: 6, MaxLength: 6, Date: true},
+	{AI: "15", Title: "BEST BEFORE", Numeric: true, MinLength: 6, MaxLength: 6, Date: true},
+	{AI: "16", Title: "SELL BY", Numeric: true, MinLength: 6, MaxLength: 6, Date: true},
+	{AI: "17", Title: "USE BY OR EXPIRY", Numeric: true, MinLength: 6, MaxLength: 6, Date: true},
+	{AI: "20", Title: "VARIANT", Numeric: true, MinLength: 2, MaxLength: 2},
+	{AI: "21", Title: "SERIAL", MinLength: 1, MaxLength: 20},
+	{AI: "22", Title: "CPV", MinLength: 1, MaxLength: 20},
+	{AI: "30", Title: "VAR. COUNT", Numeric: true, MinLength: 1, MaxLength: 8},
+	{AI: "310n", Title: "NET WEIGHT (kg)", Numeric: true, MinLength: 6, MaxLength: 6},
+	{AI: "320n", Title: "NET WEIGHT (lb)", Numeric: true, MinLength: 6, MaxLength: 6},
+	{AI: "330n", Title: "GROSS WEIGHT (kg)", Numeric: true, MinLength: 6, MaxLength: 6},
+	{AI: "37", Title: "COUNT", Numeric: true, MinLength: 1, MaxLength: 8},
+	{AI: "390n", Title: "AMOUNT", Numeric: true, MinLength: 1, MaxLength: 15},
+	{AI: "391n", Title: "AMOUNT", Numeric: true, MinLength: 4, MaxLength: 18},
+	{AI: "392n", Title: "PRICE", Numeric: true, MinLength: 1, MaxLength: 15},
+	{AI: "393n", Title: "PRICE", Numeric: true, MinLength: 4, MaxLength: 18},
+	{AI: "400", Title: "ORDER NUMBER", MinLength: 1, MaxLength: 30},
+	{AI: "401", Title: "GINC", MinLength: 1, MaxLength: 30},
+	{AI: "402", Title: "GSIN", Numeric: true, MinLength: 17, MaxLength: 17, Check: true},
+	{AI: "410", Title: "SHIP TO LOC", Numeric: true, MinLength: 13, MaxLength: 13, Check: true},
+	{AI: "414", Title: "LOC No.", Numeric: true, MinLength: 13, MaxLength: 13, Check: true},
+	{AI: "420", Title: "SHIP TO POST", MinLength: 1, MaxLength: 20},
+	{AI: "8020", Title: "REF No.", MinLength: 1, MaxLength: 25},
+	{AI: "90", Title: "INTERNAL", MinLength: 1, MaxLength: 30},
+	{AI: "91", Title: "INTERNAL", MinLength: 1, MaxLength: 90},
+}
+
+// predefinedLength holds the AI prefixes whose data length is predefined by the GS1
+// General Specifications; all other element strings end in FNC1 unless they are last
+var predefinedLength = []string{
+	"00", "01", "02", "03", "04", "11", "12", "13", "14", "15", "16", "17", "18", "19",
+	"20", "31", "32", "33", "34", "35", "36", "41",
+}
+
+// Lookup returns the definition of an application identifier, such as "01" or "3103"
+func Lookup(ai string) (*Definition, error) {
+	for i := range definitions {
+		d := &definitions[i]
+		if len(d.AI) != len(ai) {
+			continue
+		}
+		if d.AI == ai || (strings.HasSuffix(d.AI, "n") && d.AI[:len(d.AI)-1] == ai[:len(ai)-1] && isDigit(ai[len(ai)-1])) {
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownAI, ai)
+}
+
+// lookupPrefix returns the definition of the AI at the start of data
+func lookupPrefix(data string) (string, *Definition, error) {
+	for n := 2; n <= 4 && n <= len(data); n++ {
+		if d, err := Lookup(data[:n]); err == nil {
+			return data[:n], d, nil
+		}
+	}
+	return "", nil, fmt.Errorf("%w at %q", ErrUnknownAI, data)
+}
+
+// Validate checks data against the definition of its application identifier
+func (d *Definition) Validate(data string) error {
+	if len(data) < d.MinLength || len(data) > d.MaxLength {
+		if d.Fixed() {
+			return fmt.Errorf("%w: AI %s needs %d characters, got %d", ErrLength, d.AI, d.MaxLength, len(data))
+		}
+		return fmt.Errorf("%w: AI %s needs %d-%d characters, got %d", ErrLength, d.AI, d.MinLength, d.MaxLength, len(data))
+	}
+
+	for i := 0; i < len(data); i++ {
+		if (d.Numeric && !isDigit(data[i])) || (!d.Numeric && !isCSET82(data[i])) {
+			return fmt.Errorf("%w: AI %s does not allow %q", ErrCharset, d.AI, data[i])
+		}
+	}
+
+	if d.Check {
+		if want := CheckDigit(data[:len(data)-1]); data[len(data)-1] != want {
+			return fmt.Errorf("%w: AI %s %s ends in %c, want %c", ErrCheckDigit, d.AI, data, data[len(data)-1], want)
+		}
+	}
+	if d.Date && !validDate(data) {
+		return fmt.Errorf("%w: AI %s %s", ErrDate, d.AI, data)
+	}
+	return nil
+}
+
+// CheckDigit returns the GS1 mod 10 check digit of digits: weights 3 and 1 alternate
+// from the rightmost digit
+func CheckDigit(digits string) byte {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		v := int(digits[i] - '0')
+		if (len(digits)-i)%2 == 1 {
+			v *= 3
+		}
+		sum += v
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// validDate checks a YYMMDD date; day 00 means the last day of the month
+func validDate(data string) bool {
+	year := 2000 + int(data[0]-'0')*10 + int(data[1]-'0')
+	month := int(data[2]-'0')*10 + int(data[3]-'0')
+	day := int(data[4]-'0')*10 + int(data[5]-'0')
+	if month < 1 || month > 12 {
+		return false
+	}
+	// Day 0 of the next month is the last day of this one
+	last := time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return day <= last
+}
+
+// isCSET82 reports whether c is in GS1 character set 82
+func isCSET82(c byte) bool {
+	switch {
+	case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		return true
+	}
+	return strings.IndexByte(`!"%&'()*+,-./:;<=>?_`, c) >= 0
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,100 @@
+package gs1
+
+import "time"
+
+// Builder builds an element string, keeping the first error
+type Builder struct {
+	elements ElementString
+	err      error
+}
+
+// NewBuilder creates an empty Builder
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// add appends an element unless a previous one failed
+func (b *Builder) add(e Element, err error) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if err != nil {
+		b.err = err
+		return b
+	}
+	b.elements = append(b.elements, e)
+	return b
+}
+
+// Add adds an element for any supported AI
+func (b *Builder) Add(ai, data string) *Builder {
+	return b.add(New(ai, data))
+}
+
+// GTIN adds AI (01)
+func (b *Builder) GTIN(gtin string) *Builder {
+	return b.add(GTIN(gtin))
+}
+
+// SSCC adds AI (00)
+func (b *Builder) SSCC(sscc string) *Builder {
+	return b.add(SSCC(sscc))
+}
+
+// Batch adds AI (10)
+func (b *Builder) Batch(lot string) *Builder {
+	return b.add(Batch(lot))
+}
+
+// Serial adds AI (21)
+func (b *Builder) Serial(serial string) *Builder {
+	return b.add(Serial(serial))
+}
+
+// ProductionDate adds AI (11)
+func (b *Builder) ProductionDate(t time.Time) *Builder {
+	return b.add(ProductionDate(t), nil)
+}
+
+// BestBefore adds AI (15)
+func (b *Builder) BestBefore(t time.Time) *Builder {
+	return b.add(BestBefore(t), nil)
+}
+
+// Expiry adds AI (17)
+func (b *Builder) Expiry(t time.Time) *Builder {
+	return b.add(Expiry(t), nil)
+}
+
+// Count adds AI (37)
+func (b *Builder) Count(n int) *Builder {
+	return b.add(Count(n))
+}
+
+// NetWeightKg adds AI (310n)
+func (b *Builder) NetWeightKg(value int64, decimals int) *Builder {
+	return b.add(NetWeightKg(value, decimals))
+}
+
+// NetWeightLb adds AI (320n)
+func (b *Builder) NetWeightLb(value int64, decimals int) *Builder {
+	return b.add(NetWeightLb(value, decimals))
+}
+
+// Price adds AI (392n)
+func (b *Builder) Price(amount int64, decimals int) *Builder {
+	return b.add(Price(amount, decimals))
+}
+
+// PriceWithCurrency adds AI (393n)
+func (b *Builder) PriceWithCurrency(currency string, amount int64, decimals int) *Builder {
+	return b.add(PriceWithCurrency(currency, amount, decimals))
+}
+
+// Build returns the element string or the first error
+func (b *Builder) Build() (ElementString, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.elements, nil
+}
@@ -0,0 +1,21 @@
+// Package gs1 builds and validates GS1 element strings: the application
+// identifier (AI) and data pairs carried by GS1-128 and GS1 DataBar Expanded
+// barcodes.
+//
+// Elements are created from typed values and checked against the AI
+// definitions: fixed and variable lengths, numeric and alphanumeric (CSET 82)
+// data, GS1 check digits and YYMMDD dates.
+//
+//	es, err := gs1.NewBuilder().
+//		GTIN("9501101530003").
+//		Expiry(time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)).
+//		NetWeightKg(1250, 3).
+//		Batch("A-42").
+//		Build()
+//
+// ElementString.Data returns the element string with GS (FNC1) separators after
+// the variable length fields that are not last, ready for barcode.EncodeGS1128.
+// ElementString.Code128 and ElementString.DataBarExpanded return ESC/POS payloads
+// for the GS1128 and GS1DataBarExp symbologies, and ElementString.HRI the
+// human readable "(01)09501101530003(17)261231..." form.
+package gs1
@@ -0,0 +1,284 @@
+package gs1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adcondev/pos-printer/pkg/commands/barcode"
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+)
+
+// MaxCode128Length is the maximum number of data characters of a GS1-128 symbol,
+// FNC1 separators included
+const MaxCode128Length = 48
+
+// dateFormat is the YYMMDD layout of GS1 dates
+const dateFormat = "060102"
+
+// Element is an application identifier and its data
+type Element struct {
+	AI   string
+	Data string
+}
+
+// String returns the human readable form "(AI)data"
+func (e Element) String() string {
+	return "(" + e.AI + ")" + e.Data
+}
+
+// New validates data for an application identifier
+func New(ai, data string) (Element, error) {
+	d, err := Lookup(ai)
+	if err != nil {
+		return Element{}, err
+	}
+	if err := d.Validate(data); err != nil {
+		return Element{}, err
+	}
+	return Element{AI: ai, Data: data}, nil
+}
+
+// ============================================================================
+// Typed Elements
+// ============================================================================
+
+// GTIN returns AI (01) for a GTIN-8, GTIN-12, GTIN-13 or GTIN-14 that includes its
+// check digit; shorter GTINs are padded with zeros to 14 digits
+func GTIN(gtin string) (Element, error) {
+	switch len(gtin) {
+	case 8, 12, 13, 14:
+	default:
+		return Element{}, fmt.Errorf("%w: GTIN needs 8, 12, 13 or 14 digits, got %d", ErrLength, len(gtin))
+	}
+	return New("01", strings.Repeat("0", 14-len(gtin))+gtin)
+}
+
+// SSCC returns AI (00) for an 18 digit serial shipping container code
+func SSCC(sscc string) (Element, error) {
+	return New("00", sscc)
+}
+
+// Batch returns AI (10) for a batch or lot number of up to 20 characters
+func Batch(lot string) (Element, error) {
+	return New("10", lot)
+}
+
+// Serial returns AI (21) for a serial number of up to 20 characters
+func Serial(serial string) (Element, error) {
+	return New("21", serial)
+}
+
+// ProductionDate returns AI (11) for the date of t
+func ProductionDate(t time.Time) Element {
+	return Element{AI: "11", Data: t.Format(dateFormat)}
+}
+
+// BestBefore returns AI (15) for the date of t
+func BestBefore(t time.Time) Element {
+	return Element{AI: "15", Data: t.Format(dateFormat)}
+}
+
+// Expiry returns AI (17) for the date of t
+func Expiry(t time.Time) Element {
+	return Element{AI: "17", Data: t.Format(dateFormat)}
+}
+
+// Count returns AI (37) for a count of trade items of up to 8 digits
+func Count(n int) (Element, error) {
+	if n < 0 {
+		return Element{}, fmt.Errorf("%w: negative count %d", ErrCharset, n)
+	}
+	return New("37", strconv.Itoa(n))
+}
+
+// NetWeightKg returns AI (310n) for a net weight in kilograms with the given number
+// of decimals (0-5): NetWeightKg(1250, 3) is 1.250 kg, "3103001250"
+func NetWeightKg(value int64, decimals int) (Element, error) {
+	return measure("310", value, decimals)
+}
+
+// NetWeightLb returns AI (320n) for a net weight in pounds with the given number of
+// decimals (0-5)
+func NetWeightLb(value int64, decimals int) (Element, error) {
+	return measure("320", value, decimals)
+}
+
+// Price returns AI (392n) for a price in local currency with the given number of
+// decimals (0-9): Price(1999, 2) is 19.99, "392219999"
+func Price(amount int64, decimals int) (Element, error) {
+	return amountElement("392", "", amount, decimals)
+}
+
+// PriceWithCurrency returns AI (393n) for a price with the ISO 4217 numeric currency
+// code, such as "484" for MXN
+func PriceWithCurrency(currency string, amount int64, decimals int) (Element, error) {
+	if len(currency) != 3 {
+		return Element{}, fmt.Errorf("%w: currency code needs 3 digits, got %q", ErrLength, currency)
+	}
+	return amountElement("393", currency, amount, decimals)
+}
+
+// measure returns a six digit measure element, such as a weight
+func measure(prefix string, value int64, decimals int) (Element, error) {
+	if decimals < 0 || decimals > 5 {
+		return Element{}, fmt.Errorf("%w: AI %sn allows 0-5 decimals, got %d", ErrLength, prefix, decimals)
+	}
+	if value < 0 || value > 999999 {
+		return Element{}, fmt.Errorf("%w: AI %sn needs a value of 0-999999, got %d", ErrLength, prefix, value)
+	}
+	return New(prefix+strconv.Itoa(decimals), fmt.Sprintf("%06d", value))
+}
+
+// amountElement returns a variable length amount element, with an optional currency
+func amountElement(prefix, currency string, amount int64, decimals int) (Element, error) {
+	if decimals < 0 || decimals > 9 {
+		return Element{}, fmt.Errorf("%w: AI %sn allows 0-9 decimals, got %d", ErrLength, prefix, decimals)
+	}
+	if amount < 0 {
+		return Element{}, fmt.Errorf("%w: negative amount %d", ErrCharset, amount)
+	}
+	return New(prefix+strconv.Itoa(decimals), currency+strconv.FormatInt(amount, 10))
+}
+
+// ============================================================================
+// Element Strings
+// ============================================================================
+
+// ElementString is a sequence of elements encoded in one barcode
+type ElementString []Element
+
+// Data returns the element string with a GS (FNC1) separator after each element
+// of variable length that is not the last one
+func (es ElementString) Data() []byte {
+	var b []byte
+	for i, e := range es {
+		b = append(b, e.AI...)
+		b = append(b, e.Data...)
+		if i < len(es)-1 && needsSeparator(e.AI) {
+			b = append(b, common.GS)
+		}
+	}
+	return b
+}
+
+// HRI returns the human readable form, with each AI in parentheses
+func (es ElementString) HRI() string {
+	var sb strings.Builder
+	for _, e := range es {
+		sb.WriteString(e.String())
+	}
+	return sb.String()
+}
+
+// String returns the human readable form
+func (es ElementString) String() string {
+	return es.HRI()
+}
+
+// Code128 returns the ESC/POS payload for the GS1128 symbology, with the code sets
+// chosen by barcode.EncodeGS1128
+//
+// Returns ErrTooLong if the element string has more than MaxCode128Length characters.
+func (es ElementString) Code128() ([]byte, error) {
+	data := es.Data()
+	if len(data) == 0 {
+		return nil, barcode.ErrDataTooShort
+	}
+	if len(data) > MaxCode128Length {
+		return nil, fmt.Errorf("%w: %d characters, GS1-128 allows %d", ErrTooLong, len(data), MaxCode128Length)
+	}
+	return barcode.EncodeGS1128(data)
+}
+
+// DataBarExpanded returns the ESC/POS payload for the GS1DataBarExp symbology, where
+// FNC1 is written as "{1"
+func (es ElementString) DataBarExpanded() []byte {
+	var b []byte
+	for _, c := range es.Data() {
+		if c == common.GS {
+			b = append(b, '{', '1')
+		} else {
+			b = append(b, c)
+		}
+	}
+	return b
+}
+
+// needsSeparator reports whether an element needs FNC1 when others follow it
+func needsSeparator(ai string) bool {
+	for _, prefix := range predefinedLength {
+		if strings.HasPrefix(ai, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// ============================================================================
+// Parsing
+// ============================================================================
+
+// Parse reads an element string in which GS (FNC1) ends each variable length field
+// that is not last. A leading FNC1 is ignored.
+func Parse(data []byte) (ElementString, error) {
+	s := strings.TrimPrefix(string(data), string(rune(common.GS)))
+	var es ElementString
+	for s != "" {
+		ai, d, err := lookupPrefix(s)
+		if err != nil {
+			return nil, err
+		}
+		s = s[len(ai):]
+
+		// Variable length data runs to the next FNC1 or the end
+		n := min(d.MaxLength, len(s))
+		if !d.Fixed() {
+			n = len(s)
+			if end := strings.IndexByte(s, common.GS); end >= 0 {
+				n = end
+			}
+		}
+
+		e, err := New(ai, s[:n])
+		if err != nil {
+			return nil, err
+		}
+		es = append(es, e)
+		s = strings.TrimPrefix(s[n:], string(rune(common.GS)))
+	}
+	if len(es) == 0 {
+		return nil, fmt.Errorf("%w: empty element string", ErrLength)
+	}
+	return es, nil
+}
+
+// ParseHRI reads the human readable form "(01)09501101530003(10)ABC". Data that
+// contains parentheses cannot be read from this form; use Parse.
+func ParseHRI(s string) (ElementString, error) {
+	var es ElementString
+	for s != "" {
+		end := strings.IndexByte(s, ')')
+		if s[0] != '(' || end < 0 {
+			return nil, fmt.Errorf("%w: expected \"(AI)\" at %q", ErrUnknownAI, s)
+		}
+		ai := s[1:end]
+		s = s[end+1:]
+
+		n := strings.IndexByte(s, '(')
+		if n < 0 {
+			n = len(s)
+		}
+		e, err := New(ai, s[:n])
+		if err != nil {
+			return nil, err
+		}
+		es = append(es, e)
+		s = s[n:]
+	}
+	if len(es) == 0 {
+		return nil, fmt.Errorf("%w: empty element string", ErrLength)
+	}
+	return es, nil
+}
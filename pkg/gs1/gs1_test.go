@@ -0,0 +1,273 @@
+package gs1_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/commands/barcode"
+	"github.com/adcondev/pos-printer/pkg/gs1"
+)
+
+var expiry = time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// ============================================================================
+// Element Tests
+// ============================================================================
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   byte
+	}{
+		{"0950110153000", '3'},
+		{"400638133393", '1'},
+		{"9638507", '4'},
+		{"03600029145", '2'},
+		{"10614141234567890", '8'},
+	}
+
+	for _, tt := range tests {
+		if got := gs1.CheckDigit(tt.digits); got != tt.want {
+			t.Errorf("CheckDigit(%s) = %c, want %c", tt.digits, got, tt.want)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		ai      string
+		data    string
+		wantErr error
+	}{
+		{"GTIN", "01", "09501101530003", nil},
+		{"GTIN wrong check digit", "01", "09501101530004", gs1.ErrCheckDigit},
+		{"GTIN too short", "01", "9501101530003", gs1.ErrLength},
+		{"GTIN not numeric", "01", "0950110153000A", gs1.ErrCharset},
+		{"batch", "10", "LOT-42/b", nil},
+		{"batch too long", "10", strings.Repeat("A", 21), gs1.ErrLength},
+		{"batch empty", "10", "", gs1.ErrLength},
+		{"batch outside CSET 82", "10", "LOT 42", gs1.ErrCharset},
+		{"expiry", "17", "261231", nil},
+		{"expiry end of month", "17", "260200", nil},
+		{"expiry leap day", "17", "280229", nil},
+		{"expiry not a leap year", "17", "270229", gs1.ErrDate},
+		{"expiry month 13", "17", "261301", gs1.ErrDate},
+		{"net weight", "3103", "001250", nil},
+		{"net weight variable length", "3103", "1250", gs1.ErrLength},
+		{"price", "3922", "1999", nil},
+		{"price with currency", "3932", "4841999", nil},
+		{"SSCC", "00", "106141412345678908", nil},
+		{"unknown AI", "99", "ABC", gs1.ErrUnknownAI},
+		{"unknown decimal AI", "310A", "001250", gs1.ErrUnknownAI},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := gs1.New(tt.ai, tt.data)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("New(%s, %q) error = %v", tt.ai, tt.data, err)
+			}
+			if e.AI != tt.ai || e.Data != tt.data {
+				t.Errorf("New(%s, %q) = %+v", tt.ai, tt.data, e)
+			}
+		})
+	}
+}
+
+func TestTypedElements(t *testing.T) {
+	must := func(e gs1.Element, err error) gs1.Element {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return e
+	}
+
+	tests := []struct {
+		name string
+		got  gs1.Element
+		want string
+	}{
+		{"GTIN-13 padded", must(gs1.GTIN("9501101530003")), "(01)09501101530003"},
+		{"GTIN-8 padded", must(gs1.GTIN("96385074")), "(01)00000096385074"},
+		{"expiry", gs1.Expiry(expiry), "(17)261231"},
+		{"best before", gs1.BestBefore(expiry), "(15)261231"},
+		{"production date", gs1.ProductionDate(expiry), "(11)261231"},
+		{"net weight kg", must(gs1.NetWeightKg(1250, 3)), "(3103)001250"},
+		{"net weight lb", must(gs1.NetWeightLb(75, 1)), "(3201)000075"},
+		{"price", must(gs1.Price(1999, 2)), "(3922)1999"},
+		{"price with currency", must(gs1.PriceWithCurrency("484", 1999, 2)), "(3932)4841999"},
+		{"count", must(gs1.Count(12)), "(37)12"},
+		{"serial", must(gs1.Serial("SN-001")), "(21)SN-001"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypedElements_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     func() error
+		wantErr error
+	}{
+		{"GTIN length", func() error { _, err := gs1.GTIN("12345"); return err }, gs1.ErrLength},
+		{"GTIN check digit", func() error { _, err := gs1.GTIN("9501101530004"); return err }, gs1.ErrCheckDigit},
+		{"weight decimals", func() error { _, err := gs1.NetWeightKg(1250, 6); return err }, gs1.ErrLength},
+		{"weight too large", func() error { _, err := gs1.NetWeightKg(1000000, 3); return err }, gs1.ErrLength},
+		{"price decimals", func() error { _, err := gs1.Price(1999, 10); return err }, gs1.ErrLength},
+		{"negative price", func() error { _, err := gs1.Price(-1, 2); return err }, gs1.ErrCharset},
+		{"currency code", func() error { _, err := gs1.PriceWithCurrency("MXN", 1999, 2); return err }, gs1.ErrCharset},
+		{"count too large", func() error { _, err := gs1.Count(123456789); return err }, gs1.ErrLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutils.AssertError(t, tt.err(), tt.wantErr)
+		})
+	}
+}
+
+// ============================================================================
+// Element String Tests
+// ============================================================================
+
+func TestBuilder(t *testing.T) {
+	es, err := gs1.NewBuilder().
+		GTIN("9501101530003").
+		Batch("A42").
+		Expiry(expiry).
+		NetWeightKg(1250, 3).
+		Price(1999, 2).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	// FNC1 (GS) follows the variable length batch, not the fixed length fields,
+	// and the last field needs none
+	testutils.AssertBytes(t, es.Data(), []byte("0109501101530003"+"10A42\x1d"+"17261231"+"3103001250"+"39221999"), "Data")
+
+	wantHRI := "(01)09501101530003(10)A42(17)261231(3103)001250(3922)1999"
+	if got := es.HRI(); got != wantHRI {
+		t.Errorf("HRI() = %q, want %q", got, wantHRI)
+	}
+
+	testutils.AssertBytes(t, es.DataBarExpanded(),
+		[]byte("0109501101530003"+"10A42{1"+"17261231"+"3103001250"+"39221999"), "DataBarExpanded")
+}
+
+func TestBuilder_FirstError(t *testing.T) {
+	_, err := gs1.NewBuilder().
+		GTIN("9501101530004").
+		Batch(strings.Repeat("A", 30)).
+		Build()
+	testutils.AssertError(t, err, gs1.ErrCheckDigit)
+}
+
+func TestElementString_Code128(t *testing.T) {
+	es, err := gs1.NewBuilder().GTIN("9501101530003").Batch("AB").Count(5).Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	got, err := es.Code128()
+	if err != nil {
+		t.Fatalf("Code128() error = %v", err)
+	}
+	want := []byte{'{', 'C', 1, 9, 50, 11, 1, 53, 0, 3, 10, '{', 'B', 'A', 'B', '{', '1', '3', '7', '5'}
+	testutils.AssertBytes(t, got, want, "Code128")
+
+	// The payload prints as GS1-128
+	cmd, err := barcode.NewCommands().PrintBarcode(barcode.GS1128, got)
+	if err != nil {
+		t.Fatalf("PrintBarcode() error = %v", err)
+	}
+	testutils.AssertHasPrefix(t, cmd, []byte{0x1D, 'k', byte(barcode.GS1128), byte(len(want))}, "GS k")
+
+	long, _ := gs1.NewBuilder().GTIN("9501101530003").Batch(strings.Repeat("A", 20)).Serial(strings.Repeat("B", 20)).Build()
+	if _, err := long.Code128(); !errors.Is(err, gs1.ErrTooLong) {
+		t.Errorf("Code128() error = %v, want %v", err, gs1.ErrTooLong)
+	}
+	if _, err := gs1.ElementString(nil).Code128(); !errors.Is(err, barcode.ErrDataTooShort) {
+		t.Errorf("empty Code128() error = %v, want %v", err, barcode.ErrDataTooShort)
+	}
+}
+
+// ============================================================================
+// Parsing Tests
+// ============================================================================
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantHRI string
+		wantErr error
+	}{
+		{"fixed lengths", "010950110153000317261231", "(01)09501101530003(17)261231", nil},
+		{"separator after variable length", "10A42\x1d3103001250", "(10)A42(3103)001250", nil},
+		{"variable length last", "0109501101530003" + "21SN-001", "(01)09501101530003(21)SN-001", nil},
+		{"leading FNC1", "\x1d3922199", "(3922)199", nil},
+		{"separator after fixed length", "0109501101530003\x1d17261231", "(01)09501101530003(17)261231", nil},
+		{"unknown AI", "9912", "", gs1.ErrUnknownAI},
+		{"bad check digit", "0109501101530004", "", gs1.ErrCheckDigit},
+		{"truncated fixed field", "01095011", "", gs1.ErrLength},
+		{"variable field too long", "10" + strings.Repeat("A", 21), "", gs1.ErrLength},
+		{"empty", "", "", gs1.ErrLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es, err := gs1.Parse([]byte(tt.data))
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.data, err)
+			}
+			if got := es.HRI(); got != tt.wantHRI {
+				t.Errorf("Parse(%q).HRI() = %q, want %q", tt.data, got, tt.wantHRI)
+			}
+		})
+	}
+}
+
+func TestParseHRI(t *testing.T) {
+	const hri = "(01)09501101530003(10)A42(17)261231(3103)001250"
+	es, err := gs1.ParseHRI(hri)
+	if err != nil {
+		t.Fatalf("ParseHRI() error = %v", err)
+	}
+	if got := es.HRI(); got != hri {
+		t.Errorf("HRI() = %q, want %q", got, hri)
+	}
+
+	// Round trip through the encoded element string
+	parsed, err := gs1.Parse(es.Data())
+	if err != nil {
+		t.Fatalf("Parse(Data()) error = %v", err)
+	}
+	if got := parsed.HRI(); got != hri {
+		t.Errorf("Parse(Data()).HRI() = %q, want %q", got, hri)
+	}
+
+	for _, bad := range []string{"", "01)123", "(01", "(01)09501101530004", "(10)A42(99)X"} {
+		if _, err := gs1.ParseHRI(bad); err == nil {
+			t.Errorf("ParseHRI(%q) error = nil", bad)
+		}
+	}
+}
@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/adcondev/pos-printer/pkg/commands/realtime"
+	"github.com/adcondev/pos-printer/pkg/commands/status"
+	"github.com/adcondev/pos-printer/pkg/connection"
+)
+
+// ============================================================================
+// Status Events
+// ============================================================================
+
+// StatusEventType identifies a status change reported by Automatic Status Back
+type StatusEventType int
+
+const (
+	// EventPaperNearEnd is sent when the roll paper near-end sensor triggers
+	EventPaperNearEnd StatusEventType = iota + 1
+	// EventPaperOut is sent when the roll paper runs out
+	EventPaperOut
+	// EventPaperLoaded is sent when paper is detected again after a paper out
+	EventPaperLoaded
+	// EventCoverOpened is sent when the roll paper cover is opened
+	EventCoverOpened
+	// EventCoverClosed is sent when the roll paper cover is closed
+	EventCoverClosed
+	// EventDrawerOpened is sent when the cash drawer is opened
+	EventDrawerOpened
+	// EventDrawerClosed is sent when the cash drawer is closed
+	EventDrawerClosed
+	// EventOffline is sent when the printer goes offline
+	EventOffline
+	// EventOnline is sent when the printer comes back online
+	EventOnline
+	// EventError is sent when an error occurs (cutter, mechanical, unrecoverable...)
+	EventError
+	// EventErrorCleared is sent when the printer recovers from an error
+	EventErrorCleared
+)
+
+var eventNames = map[StatusEventType]string{
+	EventPaperNearEnd: "PaperNearEnd",
+	EventPaperOut:     "PaperOut",
+	EventPaperLoaded:  "PaperLoaded",
+	EventCoverOpened:  "CoverOpened",
+	EventCoverClosed:  "CoverClosed",
+	EventDrawerOpened: "DrawerOpened",
+	EventDrawerClosed: "DrawerClosed",
+	EventOffline:      "Offline",
+	EventOnline:       "Online",
+	EventError:        "Error",
+	EventErrorCleared: "ErrorCleared",
+}
+
+// String returns the name of the event type
+func (t StatusEventType) String() string {
+	if name, ok := eventNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("StatusEventType(%d)", int(t))
+}
+
+// StatusEvent is a status change together with the full status that caused it
+type StatusEvent struct {
+	Type   StatusEventType
+	Status realtime.Status
+	Time   time.Time
+}
+
+// ASBOptions configures the Automatic Status Back listener
+type ASBOptions struct {
+	// Flags selects the monitored status changes (default status.ASBAll)
+	Flags status.ASBFlag
+	// DrawerOpenLow reports the drawer as open when pin 3 is low (default: open when high)
+	DrawerOpenLow bool
+	// EventBuffer is the capacity of the events channel (default 16)
+	EventBuffer int
+}
+
+const (
+	// defaultEventBuffer is the default capacity of the events channel
+	defaultEventBuffer = 16
+	// asbFrameWait is how long an incomplete ASB frame is held before it is treated as a response
+	asbFrameWait = 100 * time.Millisecond
+	// asbReceiveTimeout bounds queries without a context deadline while the listener owns the reads
+	asbReceiveTimeout = 5 * time.Second
+)
+
+var (
+	// ErrStatusBackRunning indicates that the ASB listener was already started
+	ErrStatusBackRunning = errors.New("automatic status back listener already running")
+	// ErrStatusBackStopped indicates that the ASB listener stopped reading
+	ErrStatusBackStopped = errors.New("automatic status back listener stopped")
+)
+
+// ============================================================================
+// Automatic Status Back Methods
+// ============================================================================
+
+// StartStatusBack enables Automatic Status Back (GS a) and starts a background reader that
+// turns ASB frames into events. Other responses keep flowing to Query and its helpers.
+//
+// The events channel is closed when the listener stops, either through StopStatusBack,
+// Close, cancellation of ctx or a read error. It must be drained: the reader waits while
+// the channel is full. Once the listener has stopped on its own, queries fail with
+// ErrStatusBackStopped until StopStatusBack is called.
+func (p *Printer) StartStatusBack(ctx context.Context, opts ASBOptions) (<-chan StatusEvent, error) {
+	rw, ok := connection.AsReadWriter(p.Connection)
+	if !ok {
+		return nil, connection.ErrNotBidirectional
+	}
+	if opts.Flags == status.ASBDisabled {
+		opts.Flags = status.ASBAll
+	}
+	if opts.EventBuffer <= 0 {
+		opts.EventBuffer = defaultEventBuffer
+	}
+	cmd, err := p.Protocol.Status.EnableAutomaticStatusBack(opts.Flags)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := p.acquire(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	r := p.root()
+	r.queryMu.Lock()
+	defer r.queryMu.Unlock()
+
+	if r.asb != nil {
+		return nil, ErrStatusBackRunning
+	}
+	r.inbox = r.inbox[:0]
+	if err := r.writeContext(ctx, rw, cmd); err != nil {
+		return nil, fmt.Errorf("enable automatic status back: %w", err)
+	}
+
+	listenCtx, cancel := context.WithCancel(ctx)
+	l := &asbListener{
+		opts:   opts,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		events: make(chan StatusEvent, opts.EventBuffer),
+		notify: make(chan struct{}, 1),
+	}
+	r.asb = l
+	go l.run(listenCtx, rw)
+
+	return l.events, nil
+}
+
+// StopStatusBack disables Automatic Status Back and stops the background reader.
+// It returns the error that stopped the reader early, if any.
+func (p *Printer) StopStatusBack(ctx context.Context) error {
+	cmd, _ := p.Protocol.Status.EnableAutomaticStatusBack(status.ASBDisabled)
+	release, err := p.acquire(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	r := p.root()
+	r.queryMu.Lock()
+	defer r.queryMu.Unlock()
+
+	l := r.asb
+	if l == nil {
+		return nil
+	}
+	r.asb = nil
+
+	var writeErr error
+	if rw, ok := connection.AsReadWriter(p.Connection); ok {
+		if err := r.writeContext(ctx, rw, cmd); err != nil {
+			writeErr = fmt.Errorf("disable automatic status back: %w", err)
+		}
+	}
+
+	if err := l.stop(); err != nil {
+		return err
+	}
+	return writeErr
+}
+
+// ============================================================================
+// Listener
+// ============================================================================
+
+// asbListener owns the read side of the connection while ASB is enabled
+type asbListener struct {
+	opts   ASBOptions
+	cancel context.CancelFunc
+	done   chan struct{}
+	events chan StatusEvent
+
+	mu     sync.Mutex
+	inbox  []byte        // response bytes waiting for a query
+	notify chan struct{} // signals new inbox bytes
+	err    error         // read error that stopped the listener
+
+	demux   status.Demux
+	last    realtime.Status
+	started bool
+}
+
+// stop cancels the reader and waits for it to exit
+func (l *asbListener) stop() error {
+	l.cancel()
+	<-l.done
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// run reads from rw until ctx is done or a read fails
+func (l *asbListener) run(ctx context.Context, rw connection.ReadWriteConnector) {
+	defer close(l.done)
+	defer close(l.events)
+
+	buf := make([]byte, responseReadSize)
+	for {
+		readCtx, cancel := ctx, context.CancelFunc(func() {})
+		if l.demux.Pending() {
+			readCtx, cancel = context.WithTimeout(ctx, asbFrameWait)
+		}
+		n, err := rw.ReadContext(readCtx, buf)
+		cancel()
+
+		frames, other := l.demux.Write(buf[:n])
+		l.deliver(other)
+		for _, frame := range frames {
+			if !l.publish(ctx, frame) {
+				return
+			}
+		}
+
+		if err == nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) {
+			// The held bytes were not followed by the rest of a frame
+			if l.demux.Pending() {
+				l.deliver(l.demux.Flush())
+			}
+			continue
+		}
+
+		l.mu.Lock()
+		l.err = fmt.Errorf("%w: %w", ErrStatusBackStopped, err)
+		l.mu.Unlock()
+		return
+	}
+}
+
+// publish decodes frame and sends one event per status change
+func (l *asbListener) publish(ctx context.Context, frame []byte) bool {
+	current, err := status.ParseASB(frame)
+	if err != nil {
+		return true
+	}
+
+	previous := l.last
+	if !l.started {
+		// Compare the first frame against a healthy printer with the drawer closed
+		previous = realtime.Status{DrawerPinHigh: l.opts.DrawerOpenLow}
+		l.started = true
+	}
+	l.last = current
+
+	now := time.Now()
+	for _, t := range l.changes(previous, current) {
+		select {
+		case l.events <- StatusEvent{Type: t, Status: current, Time: now}:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
+
+// changes lists the events that lead from previous to current
+func (l *asbListener) changes(previous, current realtime.Status) []StatusEventType {
+	var events []StatusEventType
+	edge := func(was, is bool, on, off StatusEventType) {
+		switch {
+		case !was && is:
+			events = append(events, on)
+		case was && !is && off != 0:
+			events = append(events, off)
+		}
+	}
+
+	drawerOpen := func(s realtime.Status) bool { return s.DrawerPinHigh != l.opts.DrawerOpenLow }
+
+	edge(previous.Offline, current.Offline, EventOffline, EventOnline)
+	edge(previous.CoverOpen, current.CoverOpen, EventCoverOpened, EventCoverClosed)
+	edge(previous.ErrorOccurred, current.ErrorOccurred, EventError, EventErrorCleared)
+	edge(previous.PaperNearEnd, current.PaperNearEnd, EventPaperNearEnd, 0)
+	edge(previous.PaperEnd, current.PaperEnd, EventPaperOut, EventPaperLoaded)
+	edge(drawerOpen(previous), drawerOpen(current), EventDrawerOpened, EventDrawerClosed)
+	return events
+}
+
+// deliver queues response bytes for the next query
+func (l *asbListener) deliver(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	l.mu.Lock()
+	l.inbox = append(l.inbox, data...)
+	l.mu.Unlock()
+	l.wake()
+}
+
+// receive waits for response bytes, like ReadContext on the connector
+func (l *asbListener) receive(ctx context.Context, buf []byte) (int, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, asbReceiveTimeout)
+		defer cancel()
+	}
+
+	for {
+		l.mu.Lock()
+		if len(l.inbox) > 0 {
+			n := copy(buf, l.inbox)
+			l.inbox = append(l.inbox[:0], l.inbox[n:]...)
+			l.mu.Unlock()
+			return n, nil
+		}
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-l.done:
+			l.mu.Lock()
+			err := l.err
+			l.mu.Unlock()
+			if err == nil {
+				err = ErrStatusBackStopped
+			}
+			return 0, err
+		case <-l.notify:
+		}
+	}
+}
+
+// discard drops response bytes left over by abandoned queries
+func (l *asbListener) discard() {
+	l.mu.Lock()
+	l.inbox = l.inbox[:0]
+	l.mu.Unlock()
+}
+
+func (l *asbListener) wake() {
+	select {
+	case l.notify <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,134 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+	"github.com/adcondev/pos-printer/pkg/connection"
+	service "github.com/adcondev/pos-printer/pkg/printer"
+)
+
+// nextEvent waits for one event or fails the test
+func nextEvent(t *testing.T, events <-chan service.StatusEvent) service.StatusEvent {
+	t.Helper()
+	select {
+	case ev, ok := <-events:
+		if !ok {
+			t.Fatal("events channel closed")
+		}
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a status event")
+	}
+	return service.StatusEvent{}
+}
+
+func TestPrinter_StartStatusBack_Events(t *testing.T) {
+	fake := testutils.NewFakeConnector()
+	fake.ReadTimeout = 20 * time.Millisecond
+	p := newTestPrinter(t, fake)
+
+	events, err := p.StartStatusBack(context.Background(), service.ASBOptions{})
+	if err != nil {
+		t.Fatalf("StartStatusBack() error = %v", err)
+	}
+	testutils.AssertBytes(t, fake.Written(), []byte{common.GS, 'a', 0x0F}, "enable ASB")
+
+	// Healthy first frame produces no events; the following frames do
+	fake.Feed([]byte{0x10, 0x00, 0x00, 0x00})
+	fake.Feed([]byte{0x10, 0x00, 0x03, 0x00})
+	fake.Feed([]byte{0x38, 0x00, 0x03})
+	fake.Feed([]byte{0x00})
+	fake.Feed([]byte{0x14, 0x00, 0x03, 0x00})
+
+	want := []service.StatusEventType{
+		service.EventPaperNearEnd,
+		service.EventOffline,
+		service.EventCoverOpened,
+		service.EventOnline,
+		service.EventCoverClosed,
+		service.EventDrawerOpened,
+	}
+	for _, w := range want {
+		if ev := nextEvent(t, events); ev.Type != w {
+			t.Errorf("event = %v, want %v", ev.Type, w)
+		}
+	}
+
+	if err := p.StopStatusBack(context.Background()); err != nil {
+		t.Fatalf("StopStatusBack() error = %v", err)
+	}
+	if _, ok := <-events; ok {
+		t.Error("events channel still open after StopStatusBack")
+	}
+	writes := fake.Writes()
+	testutils.AssertBytes(t, writes[len(writes)-1], []byte{common.GS, 'a', 0x00}, "disable ASB")
+}
+
+func TestPrinter_StatusBack_InterleavedQuery(t *testing.T) {
+	fake := testutils.NewFakeConnector()
+	fake.ReadTimeout = 20 * time.Millisecond
+	p := newTestPrinter(t, fake)
+
+	events, err := p.StartStatusBack(context.Background(), service.ASBOptions{})
+	if err != nil {
+		t.Fatalf("StartStatusBack() error = %v", err)
+	}
+	defer func() { _ = p.StopStatusBack(context.Background()) }()
+
+	// The size information block arrives with ASB frames before, inside the same read, and after it
+	fake.Responder = func(_ []byte) []byte {
+		return []byte{
+			0x18, 0x00, 0x0F, 0x00,
+			common.BlockHeader, 0x36, '2', '1', 0x1F, '2', '1', 0x1F, '1', 0x1F, '0', common.NUL,
+			0x10, 0x00, 0x00, 0x00,
+		}
+	}
+
+	size, err := p.QRCodeSize(context.Background())
+	if err != nil {
+		t.Fatalf("QRCodeSize() error = %v", err)
+	}
+	if size.Width != 21 || size.Height != 21 {
+		t.Errorf("QRCodeSize() = %+v, want 21x21", size)
+	}
+
+	want := []service.StatusEventType{
+		service.EventOffline,
+		service.EventPaperNearEnd,
+		service.EventPaperOut,
+		service.EventOnline,
+		service.EventPaperLoaded,
+	}
+	for _, w := range want {
+		if ev := nextEvent(t, events); ev.Type != w {
+			t.Errorf("event = %v, want %v", ev.Type, w)
+		}
+	}
+}
+
+func TestPrinter_StartStatusBack_Errors(t *testing.T) {
+	p := newTestPrinter(t, &testutils.WriteOnlyConnector{})
+	if _, err := p.StartStatusBack(context.Background(), service.ASBOptions{}); !errors.Is(err, connection.ErrNotBidirectional) {
+		t.Errorf("StartStatusBack() error = %v, want ErrNotBidirectional", err)
+	}
+
+	fake := testutils.NewFakeConnector()
+	fake.ReadTimeout = 20 * time.Millisecond
+	p = newTestPrinter(t, fake)
+	if _, err := p.StartStatusBack(context.Background(), service.ASBOptions{}); err != nil {
+		t.Fatalf("StartStatusBack() error = %v", err)
+	}
+	if _, err := p.StartStatusBack(context.Background(), service.ASBOptions{}); !errors.Is(err, service.ErrStatusBackRunning) {
+		t.Errorf("second StartStatusBack() error = %v, want ErrStatusBackRunning", err)
+	}
+
+	// Closing the connection stops the listener
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+}
@@ -2,24 +2,77 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/adcondev/pos-printer/pkg/commands/barcode"
+	"github.com/adcondev/pos-printer/pkg/commands/buzzer"
 	"github.com/adcondev/pos-printer/pkg/commands/character"
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+	"github.com/adcondev/pos-printer/pkg/commands/drawer"
 	"github.com/adcondev/pos-printer/pkg/commands/mechanismcontrol"
+	"github.com/adcondev/pos-printer/pkg/commands/pdf417"
 	"github.com/adcondev/pos-printer/pkg/composer"
 	"github.com/adcondev/pos-printer/pkg/connection"
 	"github.com/adcondev/pos-printer/pkg/graphics"
 	"github.com/adcondev/pos-printer/pkg/profile"
 )
 
-// Printer represents a POS printer device
+// contextWriter is implemented by connectors whose writes can be interrupted by a context
+type contextWriter interface {
+	WriteContext(ctx context.Context, data []byte) (int, error)
+}
+
+// Printer represents a POS printer device.
+//
+// A Printer is safe for concurrent use: every Write reaches the connection whole, and
+// BeginJob/EndJob (or WithJob) give a goroutine exclusive use of the printer for a whole
+// document, so the commands of two jobs never interleave.
+//
+// BeginJob returns a job printer that shares the connection and sends the commands of
+// the job. Writes on the printer itself wait until the active job ends, except real-time
+// commands (DLE), which the printer executes as soon as they arrive. Methods without a
+// context argument use the context of the job on a job printer (see BeginJobContext) and
+// context.Background otherwise.
 type Printer struct {
 	Profile    profile.Escpos
 	Connection connection.Connector
 	Protocol   composer.EscposProtocol
+
+	parent *Printer // printer that owns the shared state, nil unless this is a job printer
+	job    *job     // job of a job printer
+
+	jobOnce sync.Once
+	jobSem  chan struct{} // holds a token from BeginJob to EndJob
+
+	writeMu sync.Mutex   // keeps each Write whole on the connection
+	queryMu sync.Mutex   // serializes request/response exchanges
+	inbox   []byte       // received bytes not yet consumed by a query
+	asb     *asbListener // owns the read side while Automatic Status Back is enabled
+}
+
+// job is the state of the job sent through a job printer
+type job struct {
+	ctx   context.Context
+	name  string
+	ended atomic.Bool
 }
 
+var (
+	// ErrNoJob indicates EndJob on a printer that is not a job printer
+	ErrNoJob = errors.New("no job to end (EndJob must be called on the printer returned by BeginJob)")
+	// ErrJobEnded indicates the use of a job printer after EndJob
+	ErrJobEnded = errors.New("job already ended")
+	// ErrNestedJob indicates BeginJob on a job printer, which would wait for itself
+	ErrNestedJob = errors.New("cannot begin a job inside another job")
+)
+
 // NewPrinter creates a new Printer instance
 func NewPrinter(proto *composer.EscposProtocol, prof *profile.Escpos, conn connection.Connector) (*Printer, error) {
 	if proto == nil {
@@ -58,23 +111,209 @@ func (p *Printer) Initialize() error {
 	return p.Write(init)
 }
 
-// Close closes the connection to the printer
+// Close stops the Automatic Status Back listener, if any, and closes the connection to the printer
 func (p *Printer) Close() error {
-	return p.Connection.Close()
+	r := p.root()
+	r.queryMu.Lock()
+	l := r.asb
+	r.asb = nil
+	r.queryMu.Unlock()
+
+	err := p.Connection.Close()
+	if l != nil {
+		_ = l.stop()
+	}
+	return err
+}
+
+// CloseWhenIdle waits until no job is active and closes the printer like Close. Jobs that
+// start afterwards fail on the closed connection. It gives up when ctx is done.
+func (p *Printer) CloseWhenIdle(ctx context.Context) error {
+	if p.job != nil {
+		return fmt.Errorf("close: %w", ErrNestedJob)
+	}
+	select {
+	case p.jobs() <- struct{}{}:
+	case <-ctx.Done():
+		return fmt.Errorf("close: %w", ctx.Err())
+	}
+	defer func() { <-p.jobs() }()
+	return p.Close()
 }
 
 // Write sends raw bytes directly to the printer
 func (p *Printer) Write(data []byte) error {
-	_, err := p.Connection.Write(data)
+	return p.WriteContext(p.context(), data)
+}
+
+// WriteContext sends raw bytes unless ctx is done. Outside a job printer it first waits
+// until the active job, if any, ends; real-time commands (DLE) are sent right away.
+// Connectors with a WriteContext method (all bidirectional ones) are also interrupted
+// when ctx is done during the write.
+func (p *Printer) WriteContext(ctx context.Context, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	release, err := p.acquire(ctx, data)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	r := p.root()
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	if cw, ok := p.Connection.(contextWriter); ok {
+		_, err = cw.WriteContext(ctx, data)
+	} else {
+		_, err = p.Connection.Write(data)
+	}
 	return err
 }
 
+// ============================================================================
+// Job Control Methods
+// ============================================================================
+
+// BeginJob waits until no other job is active and starts a job named name. It returns the
+// job printer, which sends the commands of the job; until EndJob is called on it, writes on
+// p and other calls to BeginJob wait, so the commands of the job are not interleaved with
+// others. Connectors that record job boundaries (connection.JobMarker) are notified.
+func (p *Printer) BeginJob(name string) (*Printer, error) {
+	return p.BeginJobContext(context.Background(), name)
+}
+
+// BeginJobContext is like BeginJob but gives up waiting for the active job when ctx is done.
+// Methods of the job printer without a context argument use ctx.
+func (p *Printer) BeginJobContext(ctx context.Context, name string) (*Printer, error) {
+	if p.job != nil {
+		return nil, fmt.Errorf("begin job: %w", ErrNestedJob)
+	}
+	select {
+	case p.jobs() <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("begin job: %w", ctx.Err())
+	}
+
+	if m, ok := p.Connection.(connection.JobMarker); ok {
+		if err := m.BeginJob(name); err != nil {
+			<-p.jobs()
+			return nil, fmt.Errorf("begin job: %w", err)
+		}
+	}
+	return &Printer{
+		Profile:    p.Profile,
+		Connection: p.Connection,
+		Protocol:   p.Protocol,
+		parent:     p,
+		job:        &job{ctx: ctx, name: name},
+	}, nil
+}
+
+// EndJob ends the job of a job printer and lets the next job start. It must be called
+// exactly once on every printer returned by BeginJob; the job printer cannot be used afterwards.
+func (p *Printer) EndJob() error {
+	if p.job == nil {
+		return ErrNoJob
+	}
+	if p.job.ended.Swap(true) {
+		return ErrJobEnded
+	}
+	defer func() { <-p.jobs() }()
+
+	if m, ok := p.Connection.(connection.JobMarker); ok {
+		if err := m.EndJob(); err != nil {
+			return fmt.Errorf("end job: %w", err)
+		}
+	}
+	return nil
+}
+
+// WithJob runs fn with a job printer between BeginJob and EndJob, ending the job even if
+// fn fails or panics
+func (p *Printer) WithJob(name string, fn func(job *Printer) error) error {
+	return p.WithJobContext(context.Background(), name, fn)
+}
+
+// WithJobContext runs fn with a job printer between BeginJobContext and EndJob
+func (p *Printer) WithJobContext(ctx context.Context, name string, fn func(job *Printer) error) (err error) {
+	jp, err := p.BeginJobContext(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, jp.EndJob())
+	}()
+	return fn(jp)
+}
+
+// AbortJob discards the data of an interrupted job: CAN clears the page mode buffer and
+// ESC @ the standard mode buffer and every setting. It uses its own ctx because the
+// job's context is usually already done.
+func (p *Printer) AbortJob(ctx context.Context) error {
+	cmd := append(p.Protocol.Print.CancelData(), p.Protocol.InitializePrinter()...)
+	if err := p.WriteContext(ctx, cmd); err != nil {
+		return fmt.Errorf("abort job: %w", err)
+	}
+	return nil
+}
+
+// root returns the printer that owns the connection state shared with its job printers
+func (p *Printer) root() *Printer {
+	if p.parent != nil {
+		return p.parent
+	}
+	return p
+}
+
+// jobs returns the semaphore that admits one job at a time
+func (p *Printer) jobs() chan struct{} {
+	r := p.root()
+	r.jobOnce.Do(func() { r.jobSem = make(chan struct{}, 1) })
+	return r.jobSem
+}
+
+// context returns the context of the job of a job printer, or context.Background
+func (p *Printer) context() context.Context {
+	if p.job == nil {
+		return context.Background()
+	}
+	return p.job.ctx
+}
+
+// acquire waits until data may be sent and returns the function to call once it was sent.
+// Job printers send right away while their job is active; other printers wait for
+// the active job, except for real-time commands, which are meant to overtake it.
+func (p *Printer) acquire(ctx context.Context, data []byte) (func(), error) {
+	if p.job != nil {
+		if p.job.ended.Load() {
+			return nil, fmt.Errorf("job %q: %w", p.job.name, ErrJobEnded)
+		}
+		return func() {}, nil
+	}
+	if len(data) > 0 && data[0] == common.DLE {
+		return func() {}, nil
+	}
+	select {
+	case p.jobs() <- struct{}{}:
+		return func() { <-p.jobs() }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
 // ============================================================================
 // Text Printing Methods
 // ============================================================================
 
 // Print sends text without line feed
 func (p *Printer) Print(text string) error {
+	return p.PrintContext(p.context(), text)
+}
+
+// PrintContext sends text without line feed unless ctx is done
+func (p *Printer) PrintContext(ctx context.Context, text string) error {
 	encText, err := p.Profile.EncodeString(text)
 	if err != nil {
 		return err
@@ -83,11 +322,16 @@ func (p *Printer) Print(text string) error {
 	if err != nil {
 		return err
 	}
-	return p.Write(cmd)
+	return p.WriteContext(ctx, cmd)
 }
 
 // PrintLine sends text with line feed
 func (p *Printer) PrintLine(text string) error {
+	return p.PrintLineContext(p.context(), text)
+}
+
+// PrintLineContext sends text with line feed unless ctx is done
+func (p *Printer) PrintLineContext(ctx context.Context, text string) error {
 	encText, err := p.Profile.EncodeString(text)
 	if err != nil {
 		return err
@@ -96,7 +340,7 @@ func (p *Printer) PrintLine(text string) error {
 	if err != nil {
 		return err
 	}
-	return p.Write(cmd)
+	return p.WriteContext(ctx, cmd)
 }
 
 // FeedLines advances paper by n lines
@@ -169,6 +413,95 @@ func (p *Printer) PartialFeedAndCut(lines byte) error {
 	return p.Write(cmd)
 }
 
+// ============================================================================
+// Cash Drawer Methods
+// ============================================================================
+
+// OpenDrawer sends a kick-out pulse (ESC p) to the drawer connected to pin.
+// onMs and offMs are the pulse ON and OFF times in milliseconds (2-510 ms, rounded up to 2 ms).
+// Printers whose profile has no drawer are skipped with a warning, like Beep, so documents
+// with a pulse still print on them.
+func (p *Printer) OpenDrawer(pin drawer.Pin, onMs, offMs int) error {
+	on, err := drawer.PulseUnits(time.Duration(onMs) * time.Millisecond)
+	if err != nil {
+		return fmt.Errorf("pulse on time: %w", err)
+	}
+	off, err := drawer.PulseUnits(time.Duration(offMs) * time.Millisecond)
+	if err != nil {
+		return fmt.Errorf("pulse off time: %w", err)
+	}
+
+	cmd, err := p.Protocol.Drawer.GeneratePulse(pin, on, off)
+	if err != nil {
+		return err
+	}
+	if !p.Profile.SupportsDrawer {
+		log.Printf("warning: printer profile %q has no cash drawer, pulse skipped", p.Profile.Model)
+		return nil
+	}
+	return p.Write(cmd)
+}
+
+// OpenDrawerRealtime sends a real-time kick-out pulse (DLE DC4 1) to the drawer connected
+// to pin. The printer executes it as soon as it arrives, even while offline or with a full
+// receive buffer. pulseMs is both the ON and OFF time (100-800 ms, rounded up to 100 ms).
+// Profiles without a drawer are skipped with a warning, like OpenDrawer.
+func (p *Printer) OpenDrawerRealtime(pin drawer.Pin, pulseMs int) error {
+	t, err := drawer.RealtimePulseUnits(time.Duration(pulseMs) * time.Millisecond)
+	if err != nil {
+		return err
+	}
+	cmd, err := p.Protocol.Drawer.GenerateRealtimePulse(pin, t)
+	if err != nil {
+		return err
+	}
+	if !p.Profile.SupportsDrawer {
+		log.Printf("warning: printer profile %q has no cash drawer, pulse skipped", p.Profile.Model)
+		return nil
+	}
+	return p.Write(cmd)
+}
+
+// ============================================================================
+// Buzzer Methods
+// ============================================================================
+
+// Beep sounds the buzzer times times, each beep lasting durationMs milliseconds, using
+// the command variant of the profile. Printers whose profile has no buzzer are skipped
+// with a warning so documents with beeps still print on them.
+func (p *Printer) Beep(times, durationMs int) error {
+	// Out-of-range counts are mapped to 0, which every variant rejects
+	n := byte(0)
+	if times > 0 && times <= 0xFF {
+		n = byte(times)
+	}
+	d := time.Duration(durationMs) * time.Millisecond
+
+	var cmd []byte
+	switch p.Profile.Buzzer {
+	case buzzer.VariantESCB:
+		t, err := buzzer.DurationUnits(d)
+		if err != nil {
+			return err
+		}
+		if cmd, err = p.Protocol.Buzzer.Beep(n, t); err != nil {
+			return err
+		}
+	case buzzer.VariantEpson:
+		t, err := buzzer.ToneUnits(d)
+		if err != nil {
+			return err
+		}
+		if cmd, err = p.Protocol.Buzzer.BeeperTone(buzzer.PatternA, n, t); err != nil {
+			return err
+		}
+	default:
+		log.Printf("warning: printer profile %q has no buzzer, beep skipped", p.Profile.Model)
+		return nil
+	}
+	return p.Write(cmd)
+}
+
 // ============================================================================
 // Image Printing Methods
 // ============================================================================
@@ -329,3 +662,208 @@ func (p *Printer) printQRAsImage(data string, opts *graphics.QROptions) error {
 
 	return p.PrintBitmap(bitmap)
 }
+
+// ============================================================================
+// Barcode Methods
+// ============================================================================
+
+// PrintBarcode imprime un código de barras de forma nativa (GS k) si el perfil soporta la
+// simbología; si no, o si los datos o las opciones no son válidos para el comando nativo,
+// lo imprime como imagen. Los errores al enviar el comando nativo se devuelven sin
+// fallback, porque parte del código pudo llegar ya a la impresora. data es el contenido
+// sin los prefijos de code set de CODE128.
+func (p *Printer) PrintBarcode(symbology barcode.Symbology, data []byte, opts *graphics.BarcodeOptions) error {
+	if opts == nil {
+		opts = graphics.DefaultBarcodeOptions()
+	}
+	if err := barcode.ValidateHRIPosition(opts.HRIPosition); err != nil {
+		return err
+	}
+
+	if p.Profile.SupportsSymbology(symbology) {
+		cmd, err := p.barcodeNative(symbology, data, opts)
+		if err == nil {
+			return p.Write(cmd)
+		}
+		log.Printf("Native barcode failed, falling back to image: %v", err)
+	}
+
+	// Fallback a imagen
+	return p.printBarcodeAsImage(symbology, data, opts)
+}
+
+// barcodeNative arma la configuración y el código con GS H, GS f, GS h, GS w y GS k
+// para enviarlos en una sola escritura
+func (p *Printer) barcodeNative(symbology barcode.Symbology, data []byte, opts *graphics.BarcodeOptions) ([]byte, error) {
+	bc := p.Protocol.Barcode
+	if opts.Height < int(barcode.MinHeight) || opts.Height > int(barcode.MaxHeight) ||
+		opts.ModuleWidth < 0 || opts.ModuleWidth > int(barcode.ExtendedMaxWidth) {
+		return nil, fmt.Errorf("barcode size %dx%d is outside the native range", opts.ModuleWidth, opts.Height)
+	}
+
+	position, err := bc.SelectHRICharacterPosition(opts.HRIPosition)
+	if err != nil {
+		return nil, err
+	}
+	font, err := bc.SelectFontForHRI(opts.HRIFont)
+	if err != nil {
+		return nil, err
+	}
+	height, err := bc.SetBarcodeHeight(barcode.Height(opts.Height))
+	if err != nil {
+		return nil, err
+	}
+	width, err := bc.SetBarcodeWidth(barcode.Width(opts.ModuleWidth))
+	if err != nil {
+		return nil, err
+	}
+
+	// CODE128 y GS1-128 sin code set se codifican con los code sets óptimos
+	var code []byte
+	if (symbology == barcode.CODE128 || symbology == barcode.GS1128) && (len(data) == 0 || data[0] != '{') {
+		code, err = bc.PrintCode128(symbology, data)
+	} else {
+		code, err = bc.PrintBarcode(symbology, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Concat(position, font, height, width, code), nil
+}
+
+// printBarcodeAsImage genera el código como imagen, reduciendo el módulo si no cabe en el
+// papel, con el texto HRI dibujado en la imagen
+func (p *Printer) printBarcodeAsImage(symbology barcode.Symbology, data []byte, opts *graphics.BarcodeOptions) error {
+	imgOpts := *opts
+	var bitmap *graphics.MonochromeBitmap
+	for {
+		var err error
+		bitmap, err = graphics.GenerateBarcode(symbology, data, &imgOpts)
+		if err != nil {
+			return fmt.Errorf("generate barcode image: %w", err)
+		}
+		if p.Profile.DotsPerLine <= 0 || bitmap.Width <= p.Profile.DotsPerLine {
+			break
+		}
+		if imgOpts.ModuleWidth == 1 {
+			return fmt.Errorf("barcode needs %d dots, paper has %d", bitmap.Width, p.Profile.DotsPerLine)
+		}
+		imgOpts.ModuleWidth--
+	}
+
+	// El texto HRI ya viene dibujado en la imagen
+	return p.PrintBitmap(bitmap)
+}
+
+// ============================================================================
+// PDF417 Methods
+// ============================================================================
+
+// PDF417Options configura el símbolo PDF417; los valores cero dejan el cálculo automático
+// de la impresora o el valor por defecto de ESC/POS
+type PDF417Options struct {
+	Columns     pdf417.Columns         // Columnas de datos, 1-30 (0 = automático)
+	Rows        pdf417.Rows            // Filas, 3-90 (0 = automático)
+	ModuleWidth pdf417.ModuleWidth     // Ancho del módulo en puntos, 2-8 (0 = 3)
+	RowHeight   pdf417.RowHeight       // Alto de fila en múltiplos del módulo, 2-8 (0 = 3)
+	Level       pdf417.ErrorCorrection // Nivel de corrección, Level0-Level8 (0 = usar Ratio)
+	Ratio       byte                   // Corrección como n × 10% de los datos, 1-40 (0 = 1)
+	Truncated   bool                   // PDF417 truncado, sin indicadores derechos
+}
+
+// PrintPDF417 imprime un PDF417 de forma nativa (GS ( k cn=48); la configuración y los datos
+// se validan y se envían en una sola escritura. En perfiles sin PDF417 nativo (HasPDF417) el
+// símbolo se imprime como imagen con el mismo módulo, alto de fila y nivel de corrección;
+// las columnas, las filas y el modo truncado quedan a cargo del codificador.
+func (p *Printer) PrintPDF417(data []byte, opts *PDF417Options) error {
+	if opts == nil {
+		opts = &PDF417Options{}
+	}
+	pdf := p.Protocol.PDF417
+
+	moduleWidth := opts.ModuleWidth
+	if moduleWidth == 0 {
+		moduleWidth = pdf417.DefaultModuleWidth
+	}
+	rowHeight := opts.RowHeight
+	if rowHeight == 0 {
+		rowHeight = pdf417.DefaultRowHeight
+	}
+	mode, level := pdf417.ByRatio, opts.Ratio
+	if opts.Level != 0 {
+		mode, level = pdf417.ByLevel, byte(opts.Level)
+	} else if level == 0 {
+		level = pdf417.DefaultRatio
+	}
+	option := pdf417.Standard
+	if opts.Truncated {
+		option = pdf417.Truncated
+	}
+
+	columns, err := pdf.SetPDF417Columns(opts.Columns)
+	if err != nil {
+		return err
+	}
+	rows, err := pdf.SetPDF417Rows(opts.Rows)
+	if err != nil {
+		return err
+	}
+	width, err := pdf.SetPDF417ModuleWidth(moduleWidth)
+	if err != nil {
+		return err
+	}
+	height, err := pdf.SetPDF417RowHeight(rowHeight)
+	if err != nil {
+		return err
+	}
+	correction, err := pdf.SetPDF417ErrorCorrectionLevel(mode, level)
+	if err != nil {
+		return err
+	}
+	options, err := pdf.SelectPDF417Options(option)
+	if err != nil {
+		return err
+	}
+	store, err := pdf.StorePDF417Data(data)
+	if err != nil {
+		return err
+	}
+
+	if !p.Profile.HasPDF417 {
+		imageLevel := pdf417ImageLevel(len(data), level)
+		if mode == pdf417.ByLevel {
+			imageLevel = int(level - byte(pdf417.Level0))
+		}
+		return p.printPDF417AsImage(data, imageLevel, int(moduleWidth), int(rowHeight))
+	}
+	return p.Write(slices.Concat(columns, rows, width, height, correction, options, store, pdf.PrintPDF417()))
+}
+
+// printPDF417AsImage genera el PDF417 como imagen, reduciendo el módulo si no cabe en el papel
+func (p *Printer) printPDF417AsImage(data []byte, level, moduleWidth, rowHeight int) error {
+	for {
+		bitmap, err := graphics.GeneratePDF417(data, level, moduleWidth, rowHeight)
+		if err != nil {
+			return fmt.Errorf("generate PDF417 image: %w", err)
+		}
+		if p.Profile.DotsPerLine <= 0 || bitmap.Width <= p.Profile.DotsPerLine {
+			return p.PrintBitmap(bitmap)
+		}
+		if moduleWidth == 1 {
+			return fmt.Errorf("PDF417 needs %d dots, paper has %d", bitmap.Width, p.Profile.DotsPerLine)
+		}
+		moduleWidth--
+	}
+}
+
+// pdf417ImageLevel aproxima el nivel de corrección (0-8) que la impresora elige con ByRatio:
+// el menor cuyos 2^(nivel+1) códigos cubren ratio × 10% de los datos, contando un código por byte
+func pdf417ImageLevel(dataLen int, ratio byte) int {
+	need := (dataLen*int(ratio) + 9) / 10
+	level := 0
+	for level < 8 && 2<<level < need {
+		level++
+	}
+	return level
+}
@@ -0,0 +1,600 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/commands/barcode"
+	"github.com/adcondev/pos-printer/pkg/commands/buzzer"
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+	"github.com/adcondev/pos-printer/pkg/commands/drawer"
+	"github.com/adcondev/pos-printer/pkg/commands/pdf417"
+	"github.com/adcondev/pos-printer/pkg/graphics"
+	service "github.com/adcondev/pos-printer/pkg/printer"
+)
+
+func TestPrinter_OpenDrawer(t *testing.T) {
+	tests := []struct {
+		name     string
+		supports bool
+		pin      drawer.Pin
+		on, off  int
+		want     []byte
+		wantErr  error
+	}{
+		{"default timing", true, drawer.Pin2, 50, 500, []byte{common.ESC, 'p', 0x00, 25, 250}, nil},
+		{"second drawer rounds up", true, drawer.Pin5, 101, 200, []byte{common.ESC, 'p', 0x01, 51, 100}, nil},
+		{"profile without drawer is skipped", false, drawer.Pin2, 50, 500, nil, nil},
+		{"zero on time", true, drawer.Pin2, 0, 500, nil, drawer.ErrPulseTime},
+		{"off time too long", true, drawer.Pin2, 50, 600, nil, drawer.ErrPulseTime},
+		{"invalid timing without drawer", false, drawer.Pin2, 50, 600, nil, drawer.ErrPulseTime},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &testutils.WriteOnlyConnector{}
+			p := newTestPrinter(t, conn)
+			p.Profile.SupportsDrawer = tt.supports
+
+			err := p.OpenDrawer(tt.pin, tt.on, tt.off)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("OpenDrawer() error = %v, want %v", err, tt.wantErr)
+				}
+				testutils.AssertBytes(t, conn.Written(), nil, "nothing should be sent")
+				return
+			}
+			if err != nil {
+				t.Fatalf("OpenDrawer() error = %v", err)
+			}
+			testutils.AssertBytes(t, conn.Written(), tt.want, "OpenDrawer(%d, %d, %d)", tt.pin, tt.on, tt.off)
+		})
+	}
+}
+
+func TestPrinter_OpenDrawerRealtime(t *testing.T) {
+	tests := []struct {
+		name     string
+		supports bool
+		pin      drawer.Pin
+		pulse    int
+		want     []byte
+		wantErr  error
+	}{
+		{"pin 2", true, drawer.Pin2, 100, []byte{common.DLE, common.DC4, 1, 0, 1}, nil},
+		{"ASCII pin 5 rounds up", true, drawer.Pin5ASCII, 250, []byte{common.DLE, common.DC4, 1, 1, 3}, nil},
+		{"profile without drawer is skipped", false, drawer.Pin2, 100, nil, nil},
+		{"pulse too long", true, drawer.Pin2, 900, nil, drawer.ErrRealtimePulse},
+		{"invalid pin", true, 7, 100, nil, drawer.ErrPin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &testutils.WriteOnlyConnector{}
+			p := newTestPrinter(t, conn)
+			p.Profile.SupportsDrawer = tt.supports
+
+			err := p.OpenDrawerRealtime(tt.pin, tt.pulse)
+			testutils.AssertError(t, err, tt.wantErr)
+			testutils.AssertBytes(t, conn.Written(), tt.want, "OpenDrawerRealtime(%d, %d)", tt.pin, tt.pulse)
+		})
+	}
+}
+
+func TestPrinter_Beep(t *testing.T) {
+	tests := []struct {
+		name     string
+		variant  buzzer.Variant
+		times    int
+		duration int
+		want     []byte
+		wantErr  error
+	}{
+		{"ESC B", buzzer.VariantESCB, 2, 120, []byte{common.ESC, 'B', 2, 3}, nil},
+		{"Epson tone", buzzer.VariantEpson, 3, 200, []byte{common.ESC, '(', 'A', 4, 0, 48, '1', 3, 2}, nil},
+		{"no buzzer is skipped", buzzer.NoBuzzer, 2, 120, nil, nil},
+		{"too many ESC B beeps", buzzer.VariantESCB, 10, 100, nil, buzzer.ErrTimes},
+		{"negative count", buzzer.VariantEpson, -1, 100, nil, buzzer.ErrCycles},
+		{"ESC B beep too long", buzzer.VariantESCB, 1, 1000, nil, buzzer.ErrDuration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &testutils.WriteOnlyConnector{}
+			p := newTestPrinter(t, conn)
+			p.Profile.Buzzer = tt.variant
+
+			err := p.Beep(tt.times, tt.duration)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Beep() error = %v, want %v", err, tt.wantErr)
+				}
+				testutils.AssertBytes(t, conn.Written(), nil, "nothing should be sent")
+				return
+			}
+			if err != nil {
+				t.Fatalf("Beep() error = %v", err)
+			}
+			testutils.AssertBytes(t, conn.Written(), tt.want, "Beep(%d, %d)", tt.times, tt.duration)
+		})
+	}
+}
+
+func TestPrinter_PrintBarcode(t *testing.T) {
+	opts := &graphics.BarcodeOptions{
+		Height:      80,
+		ModuleWidth: 2,
+		HRIPosition: barcode.HRIBelow,
+		HRIFont:     barcode.HRIFontA,
+	}
+	native := []byte{
+		common.GS, 'H', 2, common.GS, 'f', 0, common.GS, 'h', 80, common.GS, 'w', 2,
+		common.GS, 'k', 73, 6, '{', 'B', 'A', 'B', '1', '2',
+	}
+
+	tests := []struct {
+		name       string
+		supports   bool
+		symbols    []barcode.Symbology
+		symbology  barcode.Symbology
+		wantNative bool
+		wantErr    error
+	}{
+		{"native", true, nil, barcode.CODE128, true, nil},
+		{"listed symbology", true, []barcode.Symbology{barcode.EAN13, barcode.CODE128}, barcode.CODE128, true, nil},
+		{"profile without barcodes", false, nil, barcode.CODE128, false, nil},
+		{"symbology not listed", true, []barcode.Symbology{barcode.EAN13}, barcode.CODE128, false, nil},
+		{"no image fallback", false, nil, barcode.GS1DataBarOmni, false, graphics.ErrBarcodeSymbology},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &testutils.WriteOnlyConnector{}
+			p := newTestPrinter(t, conn)
+			p.Profile.SupportsBarcode = tt.supports
+			p.Profile.Barcodes = tt.symbols
+
+			err := p.PrintBarcode(tt.symbology, []byte("AB12"), opts)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("PrintBarcode() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PrintBarcode() error = %v", err)
+			}
+
+			got := conn.Written()
+			if tt.wantNative {
+				testutils.AssertBytes(t, got, native, "native CODE128")
+				return
+			}
+			// Raster image (GS v 0) with the HRI text drawn under the bars, not printed as text
+			if !bytes.HasPrefix(got, []byte{common.GS, 'v', '0'}) || bytes.Contains(got, []byte("AB12")) {
+				t.Fatalf("PrintBarcode() fallback wrote % X", got)
+			}
+			if rows := int(got[6]) | int(got[7])<<8; rows <= opts.Height {
+				t.Errorf("PrintBarcode() fallback image has %d rows, want bars and HRI text", rows)
+			}
+		})
+	}
+}
+
+// failingConnector fails every write and counts the attempts
+type failingConnector struct {
+	testutils.WriteOnlyConnector
+	err    error
+	writes int
+}
+
+func (f *failingConnector) Write([]byte) (int, error) {
+	f.writes++
+	return 0, f.err
+}
+
+func TestPrinter_PrintBarcode_WriteError(t *testing.T) {
+	conn := &failingConnector{err: errors.New("connection reset")}
+	p := newTestPrinter(t, conn)
+	p.Profile.SupportsBarcode = true
+
+	// A failed write of the native command is not retried as an image
+	err := p.PrintBarcode(barcode.CODE128, []byte("AB12"), nil)
+	if !errors.Is(err, conn.err) {
+		t.Errorf("PrintBarcode() error = %v, want %v", err, conn.err)
+	}
+	if conn.writes != 1 {
+		t.Errorf("PrintBarcode() made %d writes, want 1", conn.writes)
+	}
+}
+
+func TestPrinter_PrintPDF417(t *testing.T) {
+	pdf := func(fn byte, params ...byte) []byte {
+		return append([]byte{common.GS, '(', 'k', byte(len(params) + 2), 0, 0x30, fn}, params...)
+	}
+	store := append([]byte{common.GS, '(', 'k', 7, 0, 0x30, 80, 0x30}, "AB12"...)
+	printCmd := pdf(81, 0x30)
+
+	tests := []struct {
+		name     string
+		opts     *service.PDF417Options
+		noPDF417 bool
+		want     []byte
+		wantErr  error
+	}{
+		{
+			name: "defaults",
+			opts: nil,
+			want: slices.Concat(pdf(65, 0), pdf(66, 0), pdf(67, 3), pdf(68, 3), pdf(69, 49, 1), pdf(70, 0), store, printCmd),
+		},
+		{
+			name: "fixed layout with level",
+			opts: &service.PDF417Options{
+				Columns: 4, Rows: 10, ModuleWidth: 2, RowHeight: 5, Level: pdf417.Level5, Truncated: true,
+			},
+			want: slices.Concat(pdf(65, 4), pdf(66, 10), pdf(67, 2), pdf(68, 5), pdf(69, 48, 53), pdf(70, 1), store, printCmd),
+		},
+		{
+			name: "ratio",
+			opts: &service.PDF417Options{Ratio: 20},
+			want: slices.Concat(pdf(65, 0), pdf(66, 0), pdf(67, 3), pdf(68, 3), pdf(69, 49, 20), pdf(70, 0), store, printCmd),
+		},
+		{
+			name:    "invalid rows",
+			opts:    &service.PDF417Options{Rows: 2},
+			wantErr: pdf417.ErrRows,
+		},
+		{
+			name:    "invalid level",
+			opts:    &service.PDF417Options{Level: 57},
+			wantErr: pdf417.ErrErrorCorrection,
+		},
+		{
+			name:     "profile without PDF417 prints an image",
+			noPDF417: true,
+			want:     []byte{common.GS, 'v', '0'},
+		},
+		{
+			name:     "profile without PDF417 still validates",
+			opts:     &service.PDF417Options{Rows: 2},
+			noPDF417: true,
+			wantErr:  pdf417.ErrRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &testutils.WriteOnlyConnector{}
+			p := newTestPrinter(t, conn)
+			p.Profile.HasPDF417 = !tt.noPDF417
+
+			err := p.PrintPDF417([]byte("AB12"), tt.opts)
+			if tt.wantErr != nil {
+				testutils.AssertError(t, err, tt.wantErr)
+				if len(conn.Written()) != 0 {
+					t.Errorf("PrintPDF417() wrote % X after an invalid option", conn.Written())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PrintPDF417() error = %v", err)
+			}
+			if tt.noPDF417 {
+				if bytes.Contains(conn.Written(), []byte{common.GS, '(', 'k'}) {
+					t.Error("printer without PDF417 support received GS ( k")
+				}
+				testutils.AssertContains(t, conn.Written(), tt.want, "PrintPDF417")
+				return
+			}
+			testutils.AssertBytes(t, conn.Written(), tt.want, "PrintPDF417")
+		})
+	}
+}
+
+// markingConnector records writes and job boundaries in one log
+type markingConnector struct {
+	testutils.WriteOnlyConnector
+	mu     sync.Mutex
+	events []string
+	endErr error
+}
+
+func (m *markingConnector) Write(data []byte) (int, error) {
+	m.mu.Lock()
+	m.events = append(m.events, "write "+string(data))
+	m.mu.Unlock()
+	return m.WriteOnlyConnector.Write(data)
+}
+
+func (m *markingConnector) BeginJob(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, "begin "+name)
+	return nil
+}
+
+func (m *markingConnector) EndJob() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, "end")
+	return m.endErr
+}
+
+func TestPrinter_WithJob(t *testing.T) {
+	conn := &markingConnector{}
+	p := newTestPrinter(t, conn)
+
+	err := p.WithJob("ticket", func(job *service.Printer) error {
+		return job.Write([]byte("A"))
+	})
+	if err != nil {
+		t.Fatalf("WithJob() error = %v", err)
+	}
+
+	fnErr := errors.New("handler failed")
+	conn.endErr = errors.New("spooler rejected job")
+	err = p.WithJob("refund", func(*service.Printer) error {
+		return fnErr
+	})
+	if !errors.Is(err, fnErr) || !errors.Is(err, conn.endErr) {
+		t.Errorf("WithJob() error = %v, want both the job and the EndJob errors", err)
+	}
+
+	want := []string{"begin ticket", "write A", "end", "begin refund", "end"}
+	if strings.Join(conn.events, "|") != strings.Join(want, "|") {
+		t.Errorf("events = %q, want %q", conn.events, want)
+	}
+
+	// The failed jobs released the printer
+	done := make(chan struct{})
+	go func() {
+		_ = p.WithJob("next", func(*service.Printer) error { return nil })
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("WithJob() blocked after previous jobs ended")
+	}
+}
+
+func TestPrinter_BeginJob_Exclusive(t *testing.T) {
+	conn := &testutils.WriteOnlyConnector{}
+	p := newTestPrinter(t, conn)
+
+	first, err := p.BeginJob("first")
+	if err != nil {
+		t.Fatalf("BeginJob() error = %v", err)
+	}
+
+	started := make(chan struct{})
+	go func() {
+		second, err := p.BeginJob("second")
+		close(started)
+		if err != nil {
+			return
+		}
+		_ = second.Write([]byte("2"))
+		_ = second.EndJob()
+	}()
+
+	select {
+	case <-started:
+		t.Fatal("second BeginJob() did not wait for EndJob")
+	case <-time.After(20 * time.Millisecond):
+	}
+	if err := first.Write([]byte("1")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := first.EndJob(); err != nil {
+		t.Fatalf("EndJob() error = %v", err)
+	}
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("second BeginJob() still blocked after EndJob")
+	}
+	if err := p.WithJob("third", func(*service.Printer) error { return nil }); err != nil {
+		t.Fatalf("WithJob() error = %v", err)
+	}
+	testutils.AssertBytes(t, conn.Written(), []byte("12"))
+}
+
+func TestPrinter_WriteDuringJob(t *testing.T) {
+	conn := &testutils.WriteOnlyConnector{}
+	p := newTestPrinter(t, conn)
+
+	job, err := p.BeginJob("receipt")
+	if err != nil {
+		t.Fatalf("BeginJob() error = %v", err)
+	}
+
+	// Writes outside the job wait until it ends
+	done := make(chan error, 1)
+	go func() { done <- p.Write([]byte("B")) }()
+	select {
+	case err := <-done:
+		t.Fatalf("Write() outside the job returned %v before EndJob", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// Real-time commands overtake the job
+	if err := p.OpenDrawerRealtime(drawer.Pin2, 100); err != nil {
+		t.Fatalf("OpenDrawerRealtime() during job error = %v", err)
+	}
+	realtime := conn.Written()
+	if len(realtime) == 0 || realtime[0] != common.DLE {
+		t.Fatalf("written during job = %#v, want the DLE command", realtime)
+	}
+
+	if err := job.Write([]byte("A")); err != nil {
+		t.Fatalf("job Write() error = %v", err)
+	}
+	if err := job.EndJob(); err != nil {
+		t.Fatalf("EndJob() error = %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Write() after EndJob error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Write() outside the job still blocked after EndJob")
+	}
+	testutils.AssertBytes(t, conn.Written()[len(realtime):], []byte("AB"))
+}
+
+func TestPrinter_EndJob(t *testing.T) {
+	p := newTestPrinter(t, &testutils.WriteOnlyConnector{})
+
+	if err := p.EndJob(); !errors.Is(err, service.ErrNoJob) {
+		t.Errorf("EndJob() without a job error = %v, want ErrNoJob", err)
+	}
+
+	job, err := p.BeginJob("receipt")
+	if err != nil {
+		t.Fatalf("BeginJob() error = %v", err)
+	}
+	if _, err := job.BeginJob("nested"); !errors.Is(err, service.ErrNestedJob) {
+		t.Errorf("BeginJob() on a job printer error = %v, want ErrNestedJob", err)
+	}
+	if err := job.EndJob(); err != nil {
+		t.Fatalf("EndJob() error = %v", err)
+	}
+	if err := job.EndJob(); !errors.Is(err, service.ErrJobEnded) {
+		t.Errorf("second EndJob() error = %v, want ErrJobEnded", err)
+	}
+	if err := job.Write([]byte("A")); !errors.Is(err, service.ErrJobEnded) {
+		t.Errorf("Write() after EndJob error = %v, want ErrJobEnded", err)
+	}
+}
+
+func TestPrinter_WriteContext(t *testing.T) {
+	fake := testutils.NewFakeConnector()
+	p := newTestPrinter(t, fake)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := p.PrintContext(ctx, "Hola"); err != nil {
+		t.Fatalf("PrintContext() error = %v", err)
+	}
+	cancel()
+
+	if err := p.WriteContext(ctx, []byte{'\n'}); !errors.Is(err, context.Canceled) {
+		t.Errorf("WriteContext() error = %v, want context.Canceled", err)
+	}
+	if err := p.PrintLineContext(ctx, "Adiós"); !errors.Is(err, context.Canceled) {
+		t.Errorf("PrintLineContext() error = %v, want context.Canceled", err)
+	}
+	testutils.AssertBytes(t, fake.Written(), []byte("Hola"))
+}
+
+func TestPrinter_BeginJobContext(t *testing.T) {
+	conn := &testutils.WriteOnlyConnector{}
+	p := newTestPrinter(t, conn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job, err := p.BeginJobContext(ctx, "first")
+	if err != nil {
+		t.Fatalf("BeginJobContext() error = %v", err)
+	}
+
+	// Writes of the job printer without a context use the job's context
+	if err := job.Print("A"); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+	cancel()
+	if err := job.Print("B"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Print() in a canceled job error = %v, want context.Canceled", err)
+	}
+
+	// Writes outside the job are not bound to its context
+	done := make(chan error, 1)
+	go func() { done <- p.Print("C") }()
+
+	// Waiting for the active job gives up with the context
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer waitCancel()
+	if _, err := p.BeginJobContext(waitCtx, "second"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("BeginJobContext() while busy error = %v, want context.DeadlineExceeded", err)
+	}
+
+	if err := job.EndJob(); err != nil {
+		t.Fatalf("EndJob() error = %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Print() outside the canceled job error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Print() outside the job still blocked after EndJob")
+	}
+	testutils.AssertBytes(t, conn.Written(), []byte("AC"))
+}
+
+func TestPrinter_CloseWhenIdle(t *testing.T) {
+	fake := testutils.NewFakeConnector()
+	p := newTestPrinter(t, fake)
+
+	job, err := p.BeginJob("receipt")
+	if err != nil {
+		t.Fatalf("BeginJob() error = %v", err)
+	}
+	closed := make(chan error, 1)
+	go func() { closed <- p.CloseWhenIdle(context.Background()) }()
+
+	// The active job keeps the connection open until it ends
+	select {
+	case err := <-closed:
+		t.Fatalf("CloseWhenIdle() returned %v during a job", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	if err := job.Write([]byte("1")); err != nil {
+		t.Fatalf("Write() during job error = %v", err)
+	}
+	if err := job.EndJob(); err != nil {
+		t.Fatalf("EndJob() error = %v", err)
+	}
+
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("CloseWhenIdle() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("CloseWhenIdle() still waiting after EndJob")
+	}
+	if !fake.IsClosed() {
+		t.Error("connection not closed")
+	}
+	testutils.AssertBytes(t, fake.Written(), []byte("1"))
+
+	// Giving up while a job is active leaves the connection open
+	busy := newTestPrinter(t, testutils.NewFakeConnector())
+	if _, err := busy.BeginJob("receipt"); err != nil {
+		t.Fatalf("BeginJob() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := busy.CloseWhenIdle(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("CloseWhenIdle() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestPrinter_AbortJob(t *testing.T) {
+	conn := &testutils.WriteOnlyConnector{}
+	p := newTestPrinter(t, conn)
+
+	if err := p.AbortJob(context.Background()); err != nil {
+		t.Fatalf("AbortJob() error = %v", err)
+	}
+	testutils.AssertBytes(t, conn.Written(), []byte{0x18, common.ESC, '@'})
+}
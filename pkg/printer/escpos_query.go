@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/adcondev/pos-printer/pkg/commands/bitimage"
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+	"github.com/adcondev/pos-printer/pkg/commands/pdf417"
+	"github.com/adcondev/pos-printer/pkg/commands/processid"
+	"github.com/adcondev/pos-printer/pkg/commands/qrcode"
+	"github.com/adcondev/pos-printer/pkg/commands/realtime"
+	"github.com/adcondev/pos-printer/pkg/connection"
+)
+
+// ============================================================================
+// Response Framing
+// ============================================================================
+
+// FrameFunc reports the length of the first complete response at the start of buf,
+// or 0 if more bytes are needed
+type FrameFunc func(buf []byte) int
+
+// BlockFrame frames "Header to NUL" block responses (GS ( k, GS ( L, GS I extended...)
+func BlockFrame(buf []byte) int {
+	return common.BlockLength(buf)
+}
+
+// ByteFrame frames single-byte responses such as real-time status
+func ByteFrame(buf []byte) int {
+	if len(buf) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// responseReadSize is the chunk size used when reading responses
+const responseReadSize = 256
+
+// ============================================================================
+// Bidirectional Methods
+// ============================================================================
+
+// IsBidirectional reports whether the connection can receive responses from the printer
+func (p *Printer) IsBidirectional() bool {
+	_, ok := connection.AsReadWriter(p.Connection)
+	return ok
+}
+
+// Query sends request and waits for the response delimited by frame. Like WriteContext,
+// it waits for the active job unless called on its job printer or request is a real-time
+// command. It fails with connection.ErrNotBidirectional when the connector cannot read.
+func (p *Printer) Query(ctx context.Context, request []byte, frame FrameFunc) ([]byte, error) {
+	rw, ok := connection.AsReadWriter(p.Connection)
+	if !ok {
+		return nil, connection.ErrNotBidirectional
+	}
+	release, err := p.acquire(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	r := p.root()
+	r.queryMu.Lock()
+	defer r.queryMu.Unlock()
+
+	return r.query(ctx, rw, request, frame)
+}
+
+// query sends request and reads its response. Must be called on the root printer with queryMu held.
+func (p *Printer) query(ctx context.Context, rw connection.ReadWriteConnector, request []byte, frame FrameFunc) ([]byte, error) {
+	// Leftovers of an abandoned query would be mistaken for this response
+	p.inbox = p.inbox[:0]
+	if p.asb != nil {
+		p.asb.discard()
+	}
+
+	if err := p.writeContext(ctx, rw, request); err != nil {
+		return nil, fmt.Errorf("send query: %w", err)
+	}
+	return p.readResponse(ctx, rw, frame)
+}
+
+// writeContext sends data on rw, keeping it whole like Write. Must be called on the root printer.
+func (p *Printer) writeContext(ctx context.Context, rw connection.ReadWriteConnector, data []byte) error {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+
+	_, err := rw.WriteContext(ctx, data)
+	return err
+}
+
+// readResponse reads from rw until frame recognizes a complete response.
+// Must be called on the root printer with queryMu held.
+func (p *Printer) readResponse(ctx context.Context, rw connection.ReadWriteConnector, frame FrameFunc) ([]byte, error) {
+	buf := make([]byte, responseReadSize)
+	for {
+		if n := frame(p.inbox); n > 0 {
+			resp := append([]byte(nil), p.inbox[:n]...)
+			p.inbox = append(p.inbox[:0], p.inbox[n:]...)
+			return resp, nil
+		}
+
+		n, err := p.receive(ctx, rw, buf)
+		p.inbox = append(p.inbox, buf[:n]...)
+		if err != nil {
+			return nil, fmt.Errorf("read response: %w", err)
+		}
+	}
+}
+
+// receive reads response bytes from the ASB listener when it is running, or from rw.
+// Must be called on the root printer with queryMu held.
+func (p *Printer) receive(ctx context.Context, rw connection.ReadWriteConnector, buf []byte) (int, error) {
+	if p.asb != nil {
+		return p.asb.receive(ctx, buf)
+	}
+	return rw.ReadContext(ctx, buf)
+}
+
+// ============================================================================
+// Status Queries
+// ============================================================================
+
+// Status polls the real-time status (DLE EOT 1 to 4) and decodes it
+func (p *Printer) Status(ctx context.Context) (realtime.Status, error) {
+	rw, ok := connection.AsReadWriter(p.Connection)
+	if !ok {
+		return realtime.Status{}, connection.ErrNotBidirectional
+	}
+
+	// DLE EOT is a real-time command: it does not wait for the active job
+	r := p.root()
+	r.queryMu.Lock()
+	defer r.queryMu.Unlock()
+
+	var status realtime.Status
+	for n := realtime.PrinterStatus; n <= realtime.PaperSensorStatus; n++ {
+		cmd, err := p.Protocol.RealTime.TransmitStatus(n)
+		if err != nil {
+			return realtime.Status{}, err
+		}
+		resp, err := r.query(ctx, rw, cmd, ByteFrame)
+		if err != nil {
+			return realtime.Status{}, fmt.Errorf("real-time status %d: %w", n, err)
+		}
+		if err := status.Apply(n, resp[0]); err != nil {
+			return realtime.Status{}, err
+		}
+	}
+	return status, nil
+}
+
+// ============================================================================
+// Print Confirmation
+// ============================================================================
+
+// ConfirmPrint sends process ID id after the data already written and waits until the
+// printer answers it, which happens once everything sent before it has been printed.
+// Responses carrying another process ID (late answers to earlier jobs) are skipped.
+func (p *Printer) ConfirmPrint(ctx context.Context, id processid.ID) error {
+	rw, ok := connection.AsReadWriter(p.Connection)
+	if !ok {
+		return connection.ErrNotBidirectional
+	}
+	cmd, err := p.Protocol.ProcessID.TransmitProcessID(id)
+	if err != nil {
+		return err
+	}
+	release, err := p.acquire(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	r := p.root()
+	r.queryMu.Lock()
+	defer r.queryMu.Unlock()
+
+	resp, err := r.query(ctx, rw, cmd, BlockFrame)
+	for {
+		if err != nil {
+			return fmt.Errorf("print confirmation %s: %w", id, err)
+		}
+		if got, perr := processid.ParseResponse(resp); perr == nil && got == id {
+			return nil
+		}
+		resp, err = r.readResponse(ctx, rw, BlockFrame)
+	}
+}
+
+// ============================================================================
+// Symbol and Memory Queries
+// ============================================================================
+
+// QRCodeSize returns the size of the QR Code currently stored in the symbol storage area
+func (p *Printer) QRCodeSize(ctx context.Context) (qrcode.SymbolSize, error) {
+	resp, err := p.Query(ctx, p.Protocol.QRCode.GetQRCodeSize(), BlockFrame)
+	if err != nil {
+		return qrcode.SymbolSize{}, fmt.Errorf("qr code size: %w", err)
+	}
+	return qrcode.ParseSymbolSize(resp)
+}
+
+// PDF417Size returns the size of the PDF417 currently stored in the symbol storage area
+func (p *Printer) PDF417Size(ctx context.Context) (pdf417.SymbolSize, error) {
+	resp, err := p.Query(ctx, p.Protocol.PDF417.GetPDF417Size(), BlockFrame)
+	if err != nil {
+		return pdf417.SymbolSize{}, fmt.Errorf("pdf417 size: %w", err)
+	}
+	return pdf417.ParseSymbolSize(resp)
+}
+
+// NVGraphicsCapacity returns the total size in bytes of the NV graphics area
+func (p *Printer) NVGraphicsCapacity(ctx context.Context) (int, error) {
+	cmd, err := bitimage.NewNVGraphicsCommands().GetNVGraphicsCapacity(bitimage.NVFuncGetCapacityASCII)
+	if err != nil {
+		return 0, err
+	}
+	return p.queryCapacity(ctx, cmd, bitimage.NVCapacityIdentifier)
+}
+
+// NVGraphicsRemainingCapacity returns the unused size in bytes of the NV graphics area
+func (p *Printer) NVGraphicsRemainingCapacity(ctx context.Context) (int, error) {
+	cmd, err := bitimage.NewNVGraphicsCommands().GetNVGraphicsRemainingCapacity(bitimage.NVFuncGetRemainingASCII)
+	if err != nil {
+		return 0, err
+	}
+	return p.queryCapacity(ctx, cmd, bitimage.NVRemainingIdentifier)
+}
+
+// DownloadGraphicsRemainingCapacity returns the unused size in bytes of the download graphics area
+func (p *Printer) DownloadGraphicsRemainingCapacity(ctx context.Context) (int, error) {
+	cmd, err := bitimage.NewDownloadGraphicsCommands().GetDownloadGraphicsRemainingCapacity(bitimage.DLFuncGetRemainingASCII)
+	if err != nil {
+		return 0, err
+	}
+	return p.queryCapacity(ctx, cmd, bitimage.DLRemainingIdentifier)
+}
+
+// NVGraphicsKeyCodes returns the key codes of all NV graphics defined in the printer
+func (p *Printer) NVGraphicsKeyCodes(ctx context.Context) ([]bitimage.KeyCode, error) {
+	cmd := bitimage.NewNVGraphicsCommands().GetNVGraphicsKeyCodeList()
+	return p.queryKeyCodes(ctx, cmd, bitimage.NVKeyCodeListIdentifier)
+}
+
+// DownloadGraphicsKeyCodes returns the key codes of all download graphics defined in the printer
+func (p *Printer) DownloadGraphicsKeyCodes(ctx context.Context) ([]bitimage.KeyCode, error) {
+	cmd := bitimage.NewDownloadGraphicsCommands().GetDownloadGraphicsKeyCodeList()
+	return p.queryKeyCodes(ctx, cmd, bitimage.DLKeyCodeListIdentifier)
+}
+
+// queryCapacity sends a capacity request and decodes the byte count
+func (p *Printer) queryCapacity(ctx context.Context, cmd []byte, identifier byte) (int, error) {
+	resp, err := p.Query(ctx, cmd, BlockFrame)
+	if err != nil {
+		return 0, fmt.Errorf("graphics capacity: %w", err)
+	}
+	return bitimage.ParseCapacity(resp, identifier)
+}
+
+// queryKeyCodes collects a key code list, following the ESC/POS handshaking
+// protocol (ACK per block) when the printer reports more than one block
+func (p *Printer) queryKeyCodes(ctx context.Context, cmd []byte, identifier byte) ([]bitimage.KeyCode, error) {
+	rw, ok := connection.AsReadWriter(p.Connection)
+	if !ok {
+		return nil, connection.ErrNotBidirectional
+	}
+	release, err := p.acquire(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	r := p.root()
+	r.queryMu.Lock()
+	defer r.queryMu.Unlock()
+
+	var all []bitimage.KeyCode
+	request := cmd
+	for {
+		resp, err := r.query(ctx, rw, request, BlockFrame)
+		if err != nil {
+			return nil, fmt.Errorf("key code list: %w", err)
+		}
+
+		codes, more, err := bitimage.ParseKeyCodeList(resp, identifier)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, codes...)
+		if !more {
+			return all, nil
+		}
+
+		// Acknowledge the block to receive the next one
+		request = []byte{common.ACK}
+	}
+}
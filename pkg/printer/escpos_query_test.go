@@ -0,0 +1,187 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/commands/bitimage"
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+	"github.com/adcondev/pos-printer/pkg/commands/processid"
+	"github.com/adcondev/pos-printer/pkg/commands/realtime"
+	"github.com/adcondev/pos-printer/pkg/composer"
+	"github.com/adcondev/pos-printer/pkg/connection"
+	service "github.com/adcondev/pos-printer/pkg/printer"
+	"github.com/adcondev/pos-printer/pkg/profile"
+)
+
+// Interface compliance check
+var _ connection.ReadWriteConnector = (*testutils.FakeConnector)(nil)
+
+func newTestPrinter(t *testing.T, conn connection.Connector) *service.Printer {
+	t.Helper()
+	p, err := service.NewPrinter(composer.NewEscpos(), profile.CreateProfile80mm(), conn)
+	if err != nil {
+		t.Fatalf("NewPrinter: %v", err)
+	}
+	return p
+}
+
+func TestPrinter_Query_NotBidirectional(t *testing.T) {
+	p := newTestPrinter(t, &testutils.WriteOnlyConnector{})
+
+	if p.IsBidirectional() {
+		t.Error("IsBidirectional() = true for a write-only connector")
+	}
+	_, err := p.Query(context.Background(), []byte{0x10, 0x04, 0x01}, service.ByteFrame)
+	if !errors.Is(err, connection.ErrNotBidirectional) {
+		t.Errorf("Query() error = %v, want ErrNotBidirectional", err)
+	}
+}
+
+func TestPrinter_Query_SplitResponse(t *testing.T) {
+	fake := testutils.NewFakeConnector()
+	p := newTestPrinter(t, fake)
+
+	// Response arrives in two reads: "Header to NUL" block with the QR size
+	go func() {
+		fake.Feed([]byte{common.BlockHeader, 0x36, '2', '5'})
+		time.Sleep(10 * time.Millisecond)
+		fake.Feed([]byte{0x1F, '2', '5', 0x1F, '1', 0x1F, '0', 0x00})
+	}()
+
+	size, err := p.QRCodeSize(context.Background())
+	if err != nil {
+		t.Fatalf("QRCodeSize() error = %v", err)
+	}
+	if size.Width != 25 || size.Height != 25 || !size.Printable {
+		t.Errorf("QRCodeSize() = %+v, want 25x25 printable", size)
+	}
+	testutils.AssertBytes(t, fake.Written(), p.Protocol.QRCode.GetQRCodeSize())
+}
+
+func TestPrinter_PDF417Size(t *testing.T) {
+	fake := testutils.NewFakeConnector()
+	p := newTestPrinter(t, fake)
+	fake.Feed([]byte{common.BlockHeader, 0x36, '2', '8', '8', 0x1F, '9', '0', 0x1F, '0', 0x00})
+
+	size, err := p.PDF417Size(context.Background())
+	if err != nil {
+		t.Fatalf("PDF417Size() error = %v", err)
+	}
+	if size.Width != 288 || size.Height != 90 || !size.Printable {
+		t.Errorf("PDF417Size() = %+v, want 288x90 printable", size)
+	}
+	testutils.AssertBytes(t, fake.Written(), p.Protocol.PDF417.GetPDF417Size())
+}
+
+func TestPrinter_Query_Canceled(t *testing.T) {
+	fake := testutils.NewFakeConnector()
+	p := newTestPrinter(t, fake)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := p.Query(ctx, []byte{0x10, 0x04, 0x01}, service.ByteFrame)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Query() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestPrinter_NVGraphicsKeyCodes_Handshake(t *testing.T) {
+	fake := testutils.NewFakeConnector()
+	blocks := [][]byte{
+		{common.BlockHeader, bitimage.NVKeyCodeListIdentifier, common.BlockMoreData, 'A', '1', 0x00},
+		{common.BlockHeader, bitimage.NVKeyCodeListIdentifier, common.BlockNoMoreData, 'B', '2', 0x00},
+	}
+	fake.Responder = func(_ []byte) []byte {
+		if len(blocks) == 0 {
+			return nil
+		}
+		next := blocks[0]
+		blocks = blocks[1:]
+		return next
+	}
+	p := newTestPrinter(t, fake)
+
+	codes, err := p.NVGraphicsKeyCodes(context.Background())
+	if err != nil {
+		t.Fatalf("NVGraphicsKeyCodes() error = %v", err)
+	}
+	want := []bitimage.KeyCode{{KC1: 'A', KC2: '1'}, {KC1: 'B', KC2: '2'}}
+	if len(codes) != len(want) || codes[0] != want[0] || codes[1] != want[1] {
+		t.Errorf("NVGraphicsKeyCodes() = %v, want %v", codes, want)
+	}
+
+	writes := fake.Writes()
+	if len(writes) != 2 {
+		t.Fatalf("writes = %d, want 2", len(writes))
+	}
+	testutils.AssertBytes(t, writes[1], []byte{common.ACK}, "second write should acknowledge the first block")
+}
+
+func TestPrinter_Status(t *testing.T) {
+	fake := testutils.NewFakeConnector()
+	// Recorded answers of a printer with the cover open and paper near its end
+	answers := map[byte]byte{1: 0x1E, 2: 0x16, 3: 0x12, 4: 0x1E}
+	fake.Responder = func(written []byte) []byte {
+		return []byte{answers[written[len(written)-1]]}
+	}
+	p := newTestPrinter(t, fake)
+
+	status, err := p.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	want := realtime.Status{DrawerPinHigh: true, Offline: true, CoverOpen: true, PaperNearEnd: true}
+	if status != want {
+		t.Errorf("Status() = %+v, want %+v", status, want)
+	}
+	if status.Ready() {
+		t.Error("Ready() = true with the cover open")
+	}
+	testutils.AssertBytes(t, fake.Written(), []byte{
+		0x10, 0x04, 0x01, 0x10, 0x04, 0x02, 0x10, 0x04, 0x03, 0x10, 0x04, 0x04,
+	})
+}
+
+func TestPrinter_ConfirmPrint_SkipsOtherProcessIDs(t *testing.T) {
+	fake := testutils.NewFakeConnector()
+	p := newTestPrinter(t, fake)
+
+	// A late answer to an earlier job arrives before the expected one
+	fake.Responder = func(written []byte) []byte {
+		if !bytes.HasPrefix(written, []byte{common.GS, '(', 'H'}) {
+			return nil
+		}
+		return []byte{
+			common.BlockHeader, processid.ResponseIdentifier, '0', '0', '4', '1', 0x00,
+			common.BlockHeader, processid.ResponseIdentifier, '0', '0', '4', '2', 0x00,
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := p.ConfirmPrint(ctx, 42); err != nil {
+		t.Fatalf("ConfirmPrint() error = %v", err)
+	}
+	want, _ := p.Protocol.ProcessID.TransmitProcessID(42)
+	testutils.AssertBytes(t, fake.Written(), want)
+}
+
+func TestPrinter_ConfirmPrint_Timeout(t *testing.T) {
+	fake := testutils.NewFakeConnector()
+	p := newTestPrinter(t, fake)
+
+	fake.Feed([]byte{common.BlockHeader, processid.ResponseIdentifier, '0', '0', '0', '1', 0x00})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	err := p.ConfirmPrint(ctx, 2)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ConfirmPrint() error = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,310 @@
+package profile
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/adcondev/pos-printer/pkg/commands/buzzer"
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+	"github.com/adcondev/pos-printer/pkg/commands/printerid"
+	"github.com/adcondev/pos-printer/pkg/connection"
+)
+
+// DefaultIdentifyTimeout es el tiempo máximo de espera por cada respuesta de GS I;
+// las impresoras no responden las funciones que no soportan
+const DefaultIdentifyTimeout = 500 * time.Millisecond
+
+// drainTimeout es la espera sin datos que da por vacía la entrada antes de cada consulta
+const drainTimeout = 20 * time.Millisecond
+
+// ErrNotIdentified indica que la impresora no respondió a ninguna solicitud de identificación
+var ErrNotIdentified = errors.New("printer did not answer any identification request")
+
+// DetectOptions configura las consultas de Detect e Identify
+type DetectOptions struct {
+	Timeout time.Duration // Espera máxima por cada respuesta (0 = DefaultIdentifyTimeout)
+}
+
+// knownModel asocia un nombre de modelo reportado por GS I con el perfil que le corresponde
+type knownModel struct {
+	match  string // Subcadena del nombre de modelo (sin distinguir mayúsculas)
+	create func() *Escpos
+}
+
+// knownModels se recorre en orden; los nombres más específicos van primero
+var knownModels = []knownModel{
+	{match: "PT-210", create: CreatePt210},
+	{match: "GP-58", create: CreateGP58N},
+	{match: "EC-PM-80250", create: CreateECPM80250},
+	{match: "TM-T20", create: CreateTMT20},
+	{match: "TM-T88", create: CreateTMT88},
+	{match: "TM-M30", create: CreateTMM30},
+	{match: "TM-P20", create: CreateProfile58mm},
+}
+
+// Detect identifica la impresora conectada en conn y devuelve un perfil para ella.
+// Requiere un conector bidireccional. opts puede ser nil.
+func Detect(ctx context.Context, conn connection.Connector, opts *DetectOptions) (*Escpos, error) {
+	id, err := Identify(ctx, conn, opts)
+	if err != nil {
+		return nil, err
+	}
+	return FromIdentification(id), nil
+}
+
+// Identify consulta los IDs (GS I 1-3) y la información extendida de la impresora,
+// con GS I 65-69 o, si la impresora no lo responde, con GS ( I.
+// Las funciones que la impresora no responde quedan vacías. opts puede ser nil.
+func Identify(ctx context.Context, conn connection.Connector, opts *DetectOptions) (printerid.Identification, error) {
+	var id printerid.Identification
+
+	rw, ok := connection.AsReadWriter(conn)
+	if !ok {
+		return id, connection.ErrNotBidirectional
+	}
+	q := query{rw: rw, cmds: printerid.NewCommands(), timeout: DefaultIdentifyTimeout}
+	if opts != nil && opts.Timeout > 0 {
+		q.timeout = opts.Timeout
+	}
+	answered := 0
+
+	// Bloques de información: se prefiere GS I, que entienden también los clones; si no
+	// responde el nombre se prueba GS ( I, y si tampoco, la impresora no soporta las
+	// funciones extendidas y no se espera por las demás
+	blocks := []struct {
+		n    printerid.InfoType
+		dest *string
+	}{
+		{printerid.ModelName, &id.Model},
+		{printerid.Manufacturer, &id.Manufacturer},
+		{printerid.FirmwareVersion, &id.Firmware},
+		{printerid.SerialNumber, &id.SerialNumber},
+		{printerid.Fonts, &id.Fonts},
+	}
+	extended := false
+	for i, b := range blocks {
+		var text string
+		var err error
+		if !extended {
+			text, err = q.info(ctx, b.n)
+			if err != nil && ctx.Err() == nil && i == 0 {
+				extended = true
+			}
+		}
+		if extended {
+			text, err = q.infoBlock(ctx, b.n)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return id, ctx.Err()
+			}
+			if i == 0 {
+				break
+			}
+			continue
+		}
+		*b.dest = strings.TrimSpace(text)
+		answered++
+	}
+
+	// IDs de un byte
+	ids := []printerid.InfoType{printerid.TypeID, printerid.ModelID, printerid.VersionID}
+	for _, n := range ids {
+		cmd, _ := q.cmds.TransmitPrinterID(n)
+		resp, err := q.exchange(ctx, cmd, singleByte)
+		if err != nil {
+			if ctx.Err() != nil {
+				return id, ctx.Err()
+			}
+			continue
+		}
+
+		switch n {
+		case printerid.TypeID:
+			flags, err := printerid.ParseTypeID(resp[0])
+			if err != nil {
+				continue
+			}
+			id.Type = flags
+			id.TypeKnown = true
+		case printerid.ModelID:
+			id.ModelID = resp[0]
+		case printerid.VersionID:
+			id.VersionID = resp[0]
+		}
+		answered++
+	}
+
+	if answered == 0 {
+		return id, ErrNotIdentified
+	}
+	return id, nil
+}
+
+// FromIdentification crea el perfil que corresponde a id. Los modelos desconocidos
+// reciben un perfil genérico armado con lo que reportó la impresora (ver genericProfile).
+func FromIdentification(id printerid.Identification) *Escpos {
+	var p *Escpos
+	name := strings.ToUpper(id.Model)
+	for _, m := range knownModels {
+		if name != "" && strings.Contains(name, m.match) {
+			p = m.create()
+			break
+		}
+	}
+	if p == nil {
+		p = genericProfile(id)
+	}
+
+	if id.Model != "" {
+		p.Model = id.Model
+		if id.Manufacturer != "" && !strings.Contains(name, strings.ToUpper(id.Manufacturer)) {
+			p.Model = fmt.Sprintf("%s %s", id.Manufacturer, id.Model)
+		}
+	}
+	if id.TypeKnown {
+		p.SupportsCutter = id.Type.Cutter
+	}
+	return p
+}
+
+// CreateTMT20 crea un perfil para impresora térmica de 80mm Epson TM-T20
+func CreateTMT20() *Escpos {
+	p := CreateProfile80mm()
+	p.Model = "EPSON TM-T20"
+	p.PrintWidth = 72
+	p.HasPDF417 = true
+	p.Buzzer = buzzer.VariantEpson // Zumbador opcional; sin él la impresora ignora ESC ( A
+	return p
+}
+
+// CreateTMT88 crea un perfil para impresora térmica de 80mm Epson TM-T88 (180 DPI)
+func CreateTMT88() *Escpos {
+	p := CreateProfile80mm()
+	p.Model = "EPSON TM-T88"
+	p.DPI = 180
+	p.DotsPerLine = 512 // 72mm a 180 DPI
+	p.PrintWidth = 72
+	p.HasPDF417 = true
+	p.Buzzer = buzzer.VariantEpson
+	return p
+}
+
+// CreateTMM30 crea un perfil para impresora térmica de 80mm Epson TM-m30
+func CreateTMM30() *Escpos {
+	p := CreateProfile80mm()
+	p.Model = "EPSON TM-m30"
+	p.PrintWidth = 72
+	p.HasPDF417 = true
+	p.Buzzer = buzzer.VariantEpson
+	return p
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+// genericProfile arma el perfil de un modelo desconocido. El cortador (GS I 2) distingue
+// las impresoras de 80mm de las de 58mm, y solo las que responden las funciones extendidas
+// (nombre, firmware o fuentes) tienen un firmware con QR nativo (GS ( k).
+func genericProfile(id printerid.Identification) *Escpos {
+	p := CreateProfile58mm()
+	if id.TypeKnown && id.Type.Cutter {
+		p = CreateProfile80mm()
+	}
+	p.HasQR = id.Model != "" || id.Manufacturer != "" || id.Firmware != "" || id.Fonts != ""
+	return p
+}
+
+// query envía las solicitudes de identificación con un tiempo máximo por respuesta
+type query struct {
+	rw      connection.ReadWriteConnector
+	cmds    *printerid.Commands
+	timeout time.Duration
+}
+
+// infoBlock consulta la información fn con GS ( I
+func (q query) infoBlock(ctx context.Context, fn printerid.InfoType) (string, error) {
+	cmd, err := q.cmds.TransmitPrinterInfo(fn)
+	if err != nil {
+		return "", err
+	}
+	resp, err := q.exchange(ctx, cmd, printerid.InfoBlockFrame(fn))
+	if err != nil {
+		return "", err
+	}
+	// El bloque empieza después de los bytes ajenos que frame haya descartado
+	if i := bytes.Index(resp, []byte{common.BlockHeader, byte(fn)}); i > 0 {
+		resp = resp[i:]
+	}
+	return printerid.ParseInfoBlock(resp, fn)
+}
+
+// info consulta la información n con GS I
+func (q query) info(ctx context.Context, n printerid.InfoType) (string, error) {
+	cmd, err := q.cmds.TransmitPrinterID(n)
+	if err != nil {
+		return "", err
+	}
+	resp, err := q.exchange(ctx, cmd, printerid.InfoFrame)
+	if err != nil {
+		return "", err
+	}
+	return printerid.ParseInfo(resp)
+}
+
+// exchange envía request y espera la respuesta delimitada por frame, con un tiempo máximo por consulta.
+// Antes de enviar descarta lo pendiente de leer, para que una respuesta tardía a la consulta
+// anterior no se tome por la de esta.
+func (q query) exchange(ctx context.Context, request []byte, frame func([]byte) int) ([]byte, error) {
+	buf := make([]byte, 64)
+	if err := drain(ctx, q.rw, buf); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, q.timeout)
+	defer cancel()
+
+	if _, err := q.rw.WriteContext(ctx, request); err != nil {
+		return nil, err
+	}
+
+	var resp []byte
+	for {
+		if n := frame(resp); n > 0 {
+			return resp[:n], nil
+		}
+		n, err := q.rw.ReadContext(ctx, buf)
+		resp = append(resp, buf[:n]...)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// drain lee y descarta bytes hasta que la impresora pase drainTimeout sin enviar nada
+func drain(ctx context.Context, rw connection.ReadWriteConnector, buf []byte) error {
+	for {
+		readCtx, cancel := context.WithTimeout(ctx, drainTimeout)
+		n, err := rw.ReadContext(readCtx, buf)
+		cancel()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if n == 0 || err != nil {
+			return nil
+		}
+	}
+}
+
+// singleByte delimita respuestas de un byte
+func singleByte(buf []byte) int {
+	if len(buf) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,298 @@
+package profile_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/commands/common"
+	"github.com/adcondev/pos-printer/pkg/commands/printerid"
+	"github.com/adcondev/pos-printer/pkg/connection"
+	"github.com/adcondev/pos-printer/pkg/profile"
+)
+
+// detectOptions shortens the wait for the functions the fake printers do not answer
+var detectOptions = &profile.DetectOptions{Timeout: 50 * time.Millisecond}
+
+// answeringPrinter returns a fake connector that answers GS I n with answers[n]
+// and ignores GS ( I, like printers without the extended function set
+func answeringPrinter(answers map[byte][]byte) *testutils.FakeConnector {
+	fake := testutils.NewFakeConnector()
+	fake.ReadTimeout = time.Second
+	fake.Responder = func(written []byte) []byte {
+		if len(written) != 3 {
+			return nil
+		}
+		return answers[written[2]]
+	}
+	return fake
+}
+
+// extendedPrinter returns a fake connector that answers GS ( I fn with a block
+// carrying blocks[fn] and GS I n with ids[n]
+func extendedPrinter(blocks map[byte]string, ids map[byte][]byte) *testutils.FakeConnector {
+	fake := testutils.NewFakeConnector()
+	fake.ReadTimeout = time.Second
+	fake.Responder = func(written []byte) []byte {
+		fn := written[len(written)-1]
+		if len(written) == 6 && written[1] == '(' {
+			text, ok := blocks[fn]
+			if !ok {
+				return nil
+			}
+			return append(append([]byte{common.BlockHeader, fn}, text...), common.NUL)
+		}
+		return ids[fn]
+	}
+	return fake
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name       string
+		answers    map[byte][]byte
+		wantModel  string
+		wantDots   int
+		wantQR     bool
+		wantCutter bool
+		wantDrawer bool
+		wantErr    error
+	}{
+		{
+			name: "Epson TM-T88 with extended information",
+			answers: map[byte][]byte{
+				'B': []byte("_EPSON\x00"),
+				'C': []byte("_TM-T88V\x00"),
+				'A': []byte("_30.02 ESC/POS\x00"),
+				'D': []byte("_J6GF012345\x00"),
+				'E': []byte("_ANK\x00"),
+				2:   {0x02},
+				1:   {0x20},
+				3:   {0x40},
+			},
+			wantModel:  "EPSON TM-T88V",
+			wantDots:   512,
+			wantQR:     true,
+			wantCutter: true,
+			wantDrawer: true,
+		},
+		{
+			name: "known clone reporting only its model name",
+			answers: map[byte][]byte{
+				'C': []byte("_PT-210\x00"),
+			},
+			wantModel: "PT-210",
+			wantDots:  384,
+			wantQR:    true,
+		},
+		{
+			name: "unknown model without cutter falls back to 58mm",
+			answers: map[byte][]byte{
+				2: {0x00},
+				1: {0x33},
+			},
+			wantModel: "Generic 58mm",
+			wantDots:  384,
+		},
+		{
+			name: "unknown model with cutter falls back to 80mm",
+			answers: map[byte][]byte{
+				2: {0x02},
+			},
+			wantModel:  "Generic 80mm",
+			wantDots:   576,
+			wantCutter: true,
+			wantDrawer: true,
+		},
+		{
+			name: "unknown model with extended information and cutter",
+			answers: map[byte][]byte{
+				'C': []byte("_XP-80C\x00"),
+				'E': []byte("_ANK\x00"),
+				2:   {0x02},
+			},
+			wantModel:  "XP-80C",
+			wantDots:   576,
+			wantQR:     true,
+			wantCutter: true,
+			wantDrawer: true,
+		},
+		{
+			name: "unknown model with extended information without cutter",
+			answers: map[byte][]byte{
+				'C': []byte("_MTP-II\x00"),
+				2:   {0x00},
+			},
+			wantModel: "MTP-II",
+			wantDots:  384,
+			wantQR:    true,
+		},
+		{
+			name:    "silent printer",
+			answers: map[byte][]byte{},
+			wantErr: profile.ErrNotIdentified,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := answeringPrinter(tt.answers)
+
+			got, err := profile.Detect(context.Background(), fake, detectOptions)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Detect() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+
+			if got.Model != tt.wantModel {
+				t.Errorf("Model = %q, want %q", got.Model, tt.wantModel)
+			}
+			if got.DotsPerLine != tt.wantDots {
+				t.Errorf("DotsPerLine = %d, want %d", got.DotsPerLine, tt.wantDots)
+			}
+			if got.HasQR != tt.wantQR {
+				t.Errorf("HasQR = %v, want %v", got.HasQR, tt.wantQR)
+			}
+			if got.SupportsCutter != tt.wantCutter {
+				t.Errorf("SupportsCutter = %v, want %v", got.SupportsCutter, tt.wantCutter)
+			}
+			if got.SupportsDrawer != tt.wantDrawer {
+				t.Errorf("SupportsDrawer = %v, want %v", got.SupportsDrawer, tt.wantDrawer)
+			}
+		})
+	}
+}
+
+func TestIdentify_Firmware(t *testing.T) {
+	fake := answeringPrinter(map[byte][]byte{
+		'B': []byte("_EPSON\x00"),
+		'C': []byte("_TM-T20II\x00"),
+		'A': []byte("_1.01 ESC/POS\x00"),
+		2:   {0x03},
+	})
+
+	id, err := profile.Identify(context.Background(), fake, detectOptions)
+	if err != nil {
+		t.Fatalf("Identify() error = %v", err)
+	}
+	want := printerid.Identification{
+		Manufacturer: "EPSON",
+		Model:        "TM-T20II",
+		Firmware:     "1.01 ESC/POS",
+		Type:         printerid.TypeFlags{MultiByte: true, Cutter: true},
+		TypeKnown:    true,
+	}
+	if id != want {
+		t.Errorf("Identify() = %+v, want %+v", id, want)
+	}
+}
+
+func TestIdentify_Extended(t *testing.T) {
+	fake := extendedPrinter(map[byte]string{
+		'B': "EPSON",
+		'C': "TM-T88VI",
+		'A': "40.01 ESC/POS",
+	}, map[byte][]byte{2: {0x02}})
+
+	id, err := profile.Identify(context.Background(), fake, detectOptions)
+	if err != nil {
+		t.Fatalf("Identify() error = %v", err)
+	}
+	want := printerid.Identification{
+		Manufacturer: "EPSON",
+		Model:        "TM-T88VI",
+		Firmware:     "40.01 ESC/POS",
+		Type:         printerid.TypeFlags{Cutter: true},
+		TypeKnown:    true,
+	}
+	if id != want {
+		t.Errorf("Identify() = %+v, want %+v", id, want)
+	}
+
+	// Only the model name is asked with GS I before switching to GS ( I
+	for _, w := range fake.Writes() {
+		if len(w) == 3 && printerid.IsBlockInfo(printerid.InfoType(w[2])) && w[2] != 'C' {
+			t.Errorf("GS I %d sent although the printer answers GS ( I", w[2])
+		}
+	}
+}
+
+func TestIdentify_LateReply(t *testing.T) {
+	fake := extendedPrinter(map[byte]string{'C': "TM-T88VI"}, nil)
+	responder := fake.Responder
+	fake.Responder = func(written []byte) []byte {
+		// The maker arrives after its request timed out, together with the firmware
+		if len(written) == 6 && written[5] == 'A' {
+			return []byte("\x37BEPSON\x00\x37A40.01\x00")
+		}
+		return responder(written)
+	}
+
+	id, err := profile.Identify(context.Background(), fake, detectOptions)
+	if err != nil {
+		t.Fatalf("Identify() error = %v", err)
+	}
+	if id.Model != "TM-T88VI" || id.Manufacturer != "" || id.Firmware != "40.01" {
+		t.Errorf("Identify() = %+v, want model and firmware only", id)
+	}
+}
+
+func TestIdentify_StaleInput(t *testing.T) {
+	fake := answeringPrinter(map[byte][]byte{'C': []byte("_TM-T20II\x00")})
+	// Answer to an earlier, abandoned identification
+	fake.Feed([]byte("_GP-58N\x00"))
+
+	id, err := profile.Identify(context.Background(), fake, detectOptions)
+	if err != nil {
+		t.Fatalf("Identify() error = %v", err)
+	}
+	if id.Model != "TM-T20II" {
+		t.Errorf("Model = %q, want %q", id.Model, "TM-T20II")
+	}
+}
+
+func TestFromIdentification_KnownModels(t *testing.T) {
+	// Native PDF417 is only set for the models where it is confirmed
+	tests := []struct {
+		model  string
+		want   *profile.Escpos
+		pdf417 bool
+	}{
+		{"PT-210", profile.CreatePt210(), false},
+		{"GP-58N", profile.CreateGP58N(), false},
+		{"EC-PM-80250", profile.CreateECPM80250(), false},
+		{"TM-T20III", profile.CreateTMT20(), true},
+		{"TM-T88VI", profile.CreateTMT88(), true},
+		{"TM-m30II", profile.CreateTMM30(), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			got := profile.FromIdentification(printerid.Identification{Model: tt.model})
+			if got.DotsPerLine != tt.want.DotsPerLine || got.HasQR != tt.want.HasQR ||
+				got.CodeTable != tt.want.CodeTable || got.Buzzer != tt.want.Buzzer {
+				t.Errorf("FromIdentification(%q) = %+v, want %+v", tt.model, got, tt.want)
+			}
+			if got.HasPDF417 != tt.pdf417 {
+				t.Errorf("HasPDF417 = %v, want %v", got.HasPDF417, tt.pdf417)
+			}
+			if got.Model != tt.model {
+				t.Errorf("Model = %q, want %q", got.Model, tt.model)
+			}
+		})
+	}
+}
+
+func TestDetect_NotBidirectional(t *testing.T) {
+	_, err := profile.Detect(context.Background(), &testutils.WriteOnlyConnector{}, nil)
+	if !errors.Is(err, connection.ErrNotBidirectional) {
+		t.Errorf("Detect() error = %v, want ErrNotBidirectional", err)
+	}
+}
@@ -1,6 +1,10 @@
 package profile
 
 import (
+	"slices"
+
+	"github.com/adcondev/pos-printer/pkg/commands/barcode"
+	"github.com/adcondev/pos-printer/pkg/commands/buzzer"
 	"github.com/adcondev/pos-printer/pkg/commands/character"
 	"github.com/adcondev/pos-printer/pkg/graphics"
 )
@@ -21,11 +25,18 @@ type Escpos struct {
 	SupportsGraphics bool // Soporta gráficos (imágenes)
 	SupportsBarcode  bool // Soporta códigos de barra nativos
 	HasQR            bool // Soporta códigos QR nativos
+	HasPDF417        bool // Soporta PDF417 nativo (GS ( k cn=48)
 	SupportsCutter   bool // Tiene cortador automático
 	SupportsDrawer   bool // Soporta cajón de dinero
 
+	Barcodes []barcode.Symbology // Simbologías nativas (Función B); vacío = todas si SupportsBarcode
+
+	Buzzer buzzer.Variant // Comando de zumbador que entiende el modelo (buzzer.NoBuzzer si no tiene)
+
 	QRMaxSize byte // Máxima versión soportada
 
+	BufferSize int // Tamaño del buffer de recepción en bytes; los envíos grandes se parten en bloques de este tamaño (0 = sin límite)
+
 	// Code table and encoding configuration
 	CodeTable character.CodeTable
 
@@ -34,6 +45,21 @@ type Escpos struct {
 	Dithering      graphics.DitherMode // Tipo de dithering por defecto
 }
 
+// SupportsSymbology indica si la impresora imprime la simbología de forma nativa (GS k).
+// Las formas de la Función A (0-6) equivalen a las de la Función B (65-71).
+func (p *Escpos) SupportsSymbology(symbology barcode.Symbology) bool {
+	if !p.SupportsBarcode {
+		return false
+	}
+	if len(p.Barcodes) == 0 {
+		return true
+	}
+	if symbology <= barcode.CODABAR {
+		symbology += barcode.UPCAB
+	}
+	return slices.Contains(p.Barcodes, symbology)
+}
+
 // CreatePt210 crea un perfil para impresora térmica de 58mm PT-58N
 func CreatePt210() *Escpos {
 	p := CreateProfile58mm()
@@ -67,8 +93,10 @@ func CreateProfile58mm() *Escpos {
 		SupportsGraphics: true,
 		SupportsBarcode:  true,
 		HasQR:            false, // Muchas impresoras baratas no soportan QR nativo
+		HasPDF417:        false,
 		SupportsCutter:   false,
 		SupportsDrawer:   false,
+		Buzzer:           buzzer.NoBuzzer,
 
 		CodeTable: character.PC850,
 	}
@@ -92,9 +120,11 @@ func CreateProfile80mm() *Escpos {
 
 		SupportsGraphics: true,
 		SupportsBarcode:  true,
-		HasQR:            true, // Las 80mm suelen tener más funciones
+		HasQR:            true,  // Las 80mm suelen tener más funciones
+		HasPDF417:        false, // Solo en modelos confirmados (ver CreateTMT20)
 		SupportsCutter:   true,
 		SupportsDrawer:   true,
+		Buzzer:           buzzer.NoBuzzer,
 
 		// Más juegos de caracteres
 		CodeTable: character.PC850, // CP850
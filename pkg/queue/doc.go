@@ -0,0 +1,8 @@
+// Package queue provides a persistent print job queue.
+//
+// Every job is stored as a JSON file in a spool directory, written atomically, and is
+// worked off by one worker per printer that retries failed jobs with exponential backoff.
+// Jobs go through the states queued, printing, done and failed. When a queue is opened on
+// an existing spool directory, its jobs are recovered: jobs that were printing when the
+// process stopped are queued again.
+package queue
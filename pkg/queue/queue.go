@@ -0,0 +1,565 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adcondev/pos-printer/pkg/document"
+)
+
+// Retry defaults
+const (
+	DefaultMaxAttempts    = 5
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = time.Minute
+)
+
+// jobExt is the extension of the job files in the spool directory
+const jobExt = ".json"
+
+var (
+	// ErrUnknownPrinter indicates a job for a printer without a worker
+	ErrUnknownPrinter = errors.New("unknown printer")
+	// ErrJobNotFound indicates an ID with no job in the queue
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobActive indicates that a queued or printing job cannot be removed
+	ErrJobActive = errors.New("job is still active")
+	// ErrStarted indicates that the queue is already running
+	ErrStarted = errors.New("queue already started")
+	// ErrStopped indicates an attempt interrupted by Stop; the job stays queued
+	ErrStopped = errors.New("queue stopped while printing")
+)
+
+// State is the state of a queued job
+type State string
+
+// Job states
+const (
+	StateQueued   State = "queued"
+	StatePrinting State = "printing"
+	StateDone     State = "done"
+	StateFailed   State = "failed"
+)
+
+// Printer prints documents; *document.Executor implements it
+type Printer interface {
+	ExecuteContext(ctx context.Context, doc *document.Document) error
+}
+
+// Job is a print job and its progress, as stored in the spool directory
+type Job struct {
+	ID          string            `json:"id"`
+	Printer     string            `json:"printer"`
+	State       State             `json:"state"`
+	Attempts    int               `json:"attempts"`
+	LastError   string            `json:"last_error,omitempty"`
+	Err         error             `json:"-"` // Error of the last attempt in this process (not persisted)
+	Created     time.Time         `json:"created"`
+	Updated     time.Time         `json:"updated"`
+	NextAttempt time.Time         `json:"next_attempt,omitempty"` // Earliest retry of a queued job
+	Job         document.PrintJob `json:"job"`
+}
+
+// RetryPolicy decides how often and how fast failed jobs are retried
+type RetryPolicy struct {
+	// MaxAttempts is the number of print attempts before a job fails (0 retries forever)
+	MaxAttempts int
+	// InitialBackoff is the pause before the first retry; it doubles on every attempt
+	InitialBackoff time.Duration
+	// MaxBackoff caps the pause between attempts
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns a policy with sensible defaults
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    DefaultMaxAttempts,
+		InitialBackoff: DefaultInitialBackoff,
+		MaxBackoff:     DefaultMaxBackoff,
+	}
+}
+
+// backoff returns the pause after the given number of failed attempts
+func (r RetryPolicy) backoff(attempts int) time.Duration {
+	d := r.InitialBackoff
+	for i := 1; i < attempts && d < r.MaxBackoff; i++ {
+		d *= 2
+	}
+	if r.MaxBackoff > 0 && d > r.MaxBackoff {
+		d = r.MaxBackoff
+	}
+	return d
+}
+
+// Config holds the settings of a queue
+type Config struct {
+	// Dir is the spool directory; it is created if needed
+	Dir string
+	// Retry is the retry policy of every printer; the zero value means DefaultRetryPolicy
+	Retry RetryPolicy
+	// OnChange, when set, is called after every state change with a copy of the job. Changes
+	// are reported in order and without the queue lock held, so OnChange may call the queue;
+	// it runs on a worker or on the goroutine that changed the job and should return quickly.
+	OnChange func(Job)
+}
+
+// Queue is a persistent print job queue with one worker per printer. It is safe for concurrent use.
+type Queue struct {
+	config Config
+
+	mu         sync.Mutex
+	jobs       map[string]*Job
+	printers   map[string]Printer
+	wake       map[string]chan struct{}
+	seq        int
+	pending    []Job              // changes not yet reported to OnChange
+	delivering bool               // a goroutine is reporting pending changes
+	runCtx     context.Context    // Context of the workers, set by Start
+	cancel     context.CancelFunc // nil until Start
+	wg         sync.WaitGroup
+}
+
+// New opens the spool directory in config and recovers its jobs. Jobs that were printing
+// are queued again. Workers do not run until Start.
+func New(config *Config) (*Queue, error) {
+	if config == nil {
+		return nil, errors.New("queue config cannot be nil")
+	}
+	if config.Dir == "" {
+		return nil, errors.New("queue config needs a spool directory")
+	}
+	if err := os.MkdirAll(config.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create spool directory: %w", err)
+	}
+
+	q := &Queue{
+		config:   *config,
+		jobs:     make(map[string]*Job),
+		printers: make(map[string]Printer),
+		wake:     make(map[string]chan struct{}),
+	}
+	if q.config.Retry == (RetryPolicy{}) {
+		q.config.Retry = DefaultRetryPolicy()
+	}
+	if err := q.recover(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// AddPrinter registers the printer that works off the jobs sent to name. Printers added
+// after Start get their worker immediately.
+func (q *Queue) AddPrinter(name string, p Printer) error {
+	if name == "" {
+		return errors.New("printer name cannot be empty")
+	}
+	if p == nil {
+		return errors.New("printer cannot be nil")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.printers[name]; ok {
+		return fmt.Errorf("printer %q already added", name)
+	}
+	q.printers[name] = p
+	q.wake[name] = make(chan struct{}, 1)
+	if q.cancel != nil {
+		q.startWorker(name)
+	}
+	return nil
+}
+
+// Start launches the workers; they stop when ctx is done or Stop is called
+func (q *Queue) Start(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.cancel != nil {
+		return ErrStarted
+	}
+	ctx, q.cancel = context.WithCancel(ctx)
+	q.runCtx = ctx
+	for name := range q.printers {
+		q.startWorker(name)
+	}
+	return nil
+}
+
+// Stop stops the workers and waits for them. A job being printed is interrupted and
+// queued again, so it is printed again on the next start.
+func (q *Queue) Stop() {
+	q.mu.Lock()
+	cancel := q.cancel
+	q.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	q.wg.Wait()
+}
+
+// Enqueue stores job for printer in the spool directory and wakes the printer's worker
+func (q *Queue) Enqueue(printer string, job document.PrintJob) (Job, error) {
+	defer q.deliver()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.printers[printer]; !ok {
+		return Job{}, fmt.Errorf("%w: %q", ErrUnknownPrinter, printer)
+	}
+
+	now := time.Now()
+	j := &Job{
+		ID:      q.newID(now),
+		Printer: printer,
+		State:   StateQueued,
+		Created: now,
+		Updated: now,
+		Job:     job,
+	}
+	if err := q.save(j); err != nil {
+		return Job{}, err
+	}
+	q.jobs[j.ID] = j
+	q.notify(printer)
+	q.changed(j)
+	return *j, nil
+}
+
+// Get returns the job with the given ID
+func (q *Queue) Get(id string) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	j, ok := q.jobs[id]
+	if !ok {
+		return Job{}, fmt.Errorf("%w: %q", ErrJobNotFound, id)
+	}
+	return *j, nil
+}
+
+// List returns the jobs of printer, or of every printer when printer is empty, oldest first
+func (q *Queue) List(printer string) []Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var jobs []Job
+	for _, j := range q.jobs {
+		if printer == "" || j.Printer == printer {
+			jobs = append(jobs, *j)
+		}
+	}
+	sort.Slice(jobs, func(a, b int) bool { return jobs[a].ID < jobs[b].ID })
+	return jobs
+}
+
+// Retry queues a failed job again with its attempts reset
+func (q *Queue) Retry(id string) error {
+	defer q.deliver()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	j, ok := q.jobs[id]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrJobNotFound, id)
+	}
+	if j.State != StateFailed {
+		return fmt.Errorf("job %q is %s, not failed", id, j.State)
+	}
+	j.Attempts = 0
+	j.NextAttempt = time.Time{}
+	if err := q.update(j, StateQueued, nil); err != nil {
+		return err
+	}
+	q.notify(j.Printer)
+	return nil
+}
+
+// Remove deletes a done or failed job from the queue and the spool directory
+func (q *Queue) Remove(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	j, ok := q.jobs[id]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrJobNotFound, id)
+	}
+	if j.State == StateQueued || j.State == StatePrinting {
+		return fmt.Errorf("%w: %q is %s", ErrJobActive, id, j.State)
+	}
+	if err := os.Remove(q.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove job file: %w", err)
+	}
+	delete(q.jobs, id)
+	return nil
+}
+
+// ============================================================================
+// Worker
+// ============================================================================
+
+// startWorker launches the worker of a printer. Must be called with mu held after Start.
+func (q *Queue) startWorker(name string) {
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		q.work(q.runCtx, name, q.printers[name], q.wake[name])
+	}()
+}
+
+// work prints the due jobs of a printer, oldest first, until ctx is done
+func (q *Queue) work(ctx context.Context, name string, p Printer, wake <-chan struct{}) {
+	for ctx.Err() == nil {
+		j, wait := q.next(name)
+		if j != nil {
+			q.print(ctx, p, j)
+			continue
+		}
+
+		var timer *time.Timer
+		var due <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			due = timer.C
+		}
+		select {
+		case <-ctx.Done():
+		case <-wake:
+		case <-due:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// next marks the oldest due job of printer as printing and returns a copy of it. When no
+// job is due it returns the time until the next retry (0 if there is none).
+func (q *Queue) next(printer string) (*Job, time.Duration) {
+	defer q.deliver()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	var due *Job
+	var wait time.Duration
+	for _, j := range q.jobs {
+		if j.Printer != printer || j.State != StateQueued {
+			continue
+		}
+		if d := j.NextAttempt.Sub(now); d > 0 {
+			if wait == 0 || d < wait {
+				wait = d
+			}
+			continue
+		}
+		if due == nil || j.ID < due.ID {
+			due = j
+		}
+	}
+	if due == nil {
+		return nil, wait
+	}
+
+	if err := q.update(due, StatePrinting, due.Err); err != nil {
+		log.Printf("queue: %v", err)
+		return nil, q.config.Retry.backoff(1)
+	}
+	cp := *due
+	return &cp, 0
+}
+
+// print executes a job and records the outcome
+func (q *Queue) print(ctx context.Context, p Printer, job *Job) {
+	err := p.ExecuteContext(ctx, &job.Job.Data)
+
+	defer q.deliver()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	j, ok := q.jobs[job.ID]
+	if !ok {
+		return
+	}
+
+	var uerr error
+	switch {
+	case err == nil:
+		j.Attempts++
+		j.NextAttempt = time.Time{}
+		uerr = q.update(j, StateDone, nil)
+	case ctx.Err() != nil:
+		// Stopped while printing: the attempt does not count
+		uerr = q.update(j, StateQueued, fmt.Errorf("%w: %w", ErrStopped, err))
+	default:
+		j.Attempts++
+		retry := q.config.Retry
+		if retry.MaxAttempts > 0 && j.Attempts >= retry.MaxAttempts {
+			uerr = q.update(j, StateFailed, err)
+			break
+		}
+		j.NextAttempt = time.Now().Add(retry.backoff(j.Attempts))
+		uerr = q.update(j, StateQueued, err)
+	}
+	if uerr != nil {
+		log.Printf("queue: %v", uerr)
+	}
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+// update changes the state of j, with the error of its last attempt, and persists it.
+// Must be called with mu held.
+func (q *Queue) update(j *Job, state State, lastErr error) error {
+	j.State = state
+	j.Err = lastErr
+	j.LastError = ""
+	if lastErr != nil {
+		j.LastError = lastErr.Error()
+	}
+	j.Updated = time.Now()
+	if err := q.save(j); err != nil {
+		return err
+	}
+	q.changed(j)
+	return nil
+}
+
+// changed records a state change for OnChange. Must be called with mu held; deliver
+// reports it once the lock is released.
+func (q *Queue) changed(j *Job) {
+	if q.config.OnChange != nil {
+		q.pending = append(q.pending, *j)
+	}
+}
+
+// deliver calls OnChange for the pending changes. Must be called without mu held. Only one
+// goroutine delivers at a time, which keeps the changes in order; the others leave their
+// changes to it.
+func (q *Queue) deliver() {
+	q.mu.Lock()
+	if q.delivering {
+		q.mu.Unlock()
+		return
+	}
+	q.delivering = true
+	for len(q.pending) > 0 {
+		changes := q.pending
+		q.pending = nil
+		q.mu.Unlock()
+		for _, j := range changes {
+			q.config.OnChange(j)
+		}
+		q.mu.Lock()
+	}
+	q.delivering = false
+	q.mu.Unlock()
+}
+
+// notify wakes the worker of printer without blocking. Must be called with mu held.
+func (q *Queue) notify(printer string) {
+	select {
+	case q.wake[printer] <- struct{}{}:
+	default:
+	}
+}
+
+// newID returns a unique job ID; IDs sort by creation time. Must be called with mu held.
+func (q *Queue) newID(now time.Time) string {
+	for {
+		q.seq++
+		id := fmt.Sprintf("%s-%04d", now.UTC().Format("20060102-150405.000000"), q.seq%10000)
+		if _, exists := q.jobs[id]; !exists {
+			return id
+		}
+	}
+}
+
+// path returns the spool file of a job
+func (q *Queue) path(id string) string {
+	return filepath.Join(q.config.Dir, id+jobExt)
+}
+
+// save writes j to a temporary file and renames it over the job file, so a crash never
+// leaves a partial job behind
+func (q *Queue) save(j *Job) error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode job %s: %w", j.ID, err)
+	}
+
+	tmp, err := os.CreateTemp(q.config.Dir, ".job-*.tmp")
+	if err != nil {
+		return fmt.Errorf("save job %s: %w", j.ID, err)
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), q.path(j.ID))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("save job %s: %w", j.ID, err)
+	}
+	return nil
+}
+
+// recover loads the jobs of the spool directory, queues again the ones that were printing
+// and removes temporary files left by a crash
+func (q *Queue) recover() error {
+	entries, err := os.ReadDir(q.config.Dir)
+	if err != nil {
+		return fmt.Errorf("read spool directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case entry.IsDir():
+			continue
+		case strings.HasPrefix(name, ".job-") && strings.HasSuffix(name, ".tmp"):
+			_ = os.Remove(filepath.Join(q.config.Dir, name))
+			continue
+		case filepath.Ext(name) != jobExt:
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(q.config.Dir, name))
+		if err != nil {
+			return fmt.Errorf("read job file: %w", err)
+		}
+		var j Job
+		if err := json.Unmarshal(data, &j); err != nil || j.ID+jobExt != name {
+			log.Printf("queue: skipping invalid job file %s", name)
+			continue
+		}
+
+		if j.State == StatePrinting {
+			j.State = StateQueued
+			j.Updated = time.Now()
+			if err := q.save(&j); err != nil {
+				return err
+			}
+		}
+		q.jobs[j.ID] = &j
+	}
+	return nil
+}
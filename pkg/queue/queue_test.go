@@ -0,0 +1,365 @@
+package queue_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/composer"
+	"github.com/adcondev/pos-printer/pkg/document"
+	service "github.com/adcondev/pos-printer/pkg/printer"
+	"github.com/adcondev/pos-printer/pkg/profile"
+	"github.com/adcondev/pos-printer/pkg/queue"
+)
+
+// flakyPrinter fails the first failures executions
+type flakyPrinter struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+}
+
+func (f *flakyPrinter) ExecuteContext(_ context.Context, _ *document.Document) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.calls <= f.failures {
+		return errors.New("printer offline")
+	}
+	return nil
+}
+
+func (f *flakyPrinter) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func fastRetry(attempts int) queue.RetryPolicy {
+	return queue.RetryPolicy{MaxAttempts: attempts, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+}
+
+func newQueue(t *testing.T, dir string, retry queue.RetryPolicy) *queue.Queue {
+	t.Helper()
+	q, err := queue.New(&queue.Config{Dir: dir, Retry: retry})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(q.Stop)
+	return q
+}
+
+func printJob(text string) document.PrintJob {
+	return document.PrintJob{Data: *document.NewBuilder().
+		SetProfile("", 80, "").
+		AddText(text, nil).
+		Build()}
+}
+
+// waitState polls the queue until the job reaches state
+func waitState(t *testing.T, q *queue.Queue, id string, state queue.State) queue.Job {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		job, err := q.Get(id)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if job.State == state {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s is %s, want %s", id, job.State, state)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// readJob reads a job file from the spool directory
+func readJob(t *testing.T, dir, id string) queue.Job {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, id+".json"))
+	if err != nil {
+		t.Fatalf("read job file: %v", err)
+	}
+	var job queue.Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		t.Fatalf("decode job file: %v", err)
+	}
+	return job
+}
+
+func TestQueue_PrintsThroughExecutor(t *testing.T) {
+	dir := t.TempDir()
+	fake := testutils.NewFakeConnector()
+	p, err := service.NewPrinter(composer.NewEscpos(), profile.CreateProfile80mm(), fake)
+	if err != nil {
+		t.Fatalf("NewPrinter: %v", err)
+	}
+
+	q := newQueue(t, dir, fastRetry(3))
+	if err := q.AddPrinter("front", document.NewExecutor(p)); err != nil {
+		t.Fatalf("AddPrinter() error = %v", err)
+	}
+	if err := q.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	job, err := q.Enqueue("front", printJob("Ticket 0042"))
+	if err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	done := waitState(t, q, job.ID, queue.StateDone)
+
+	if done.Attempts != 1 {
+		t.Errorf("Attempts = %d, want 1", done.Attempts)
+	}
+	if !strings.Contains(string(fake.Written()), "Ticket 0042") {
+		t.Errorf("printer received %q", fake.Written())
+	}
+	if stored := readJob(t, dir, job.ID); stored.State != queue.StateDone {
+		t.Errorf("spool file state = %s, want done", stored.State)
+	}
+}
+
+func TestQueue_Retries(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int
+		wantState    queue.State
+		wantAttempts int
+	}{
+		{"succeeds after retries", 2, queue.StateDone, 3},
+		{"fails after max attempts", 10, queue.StateFailed, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			printer := &flakyPrinter{failures: tt.failures}
+			q := newQueue(t, t.TempDir(), fastRetry(3))
+			if err := q.AddPrinter("kitchen", printer); err != nil {
+				t.Fatal(err)
+			}
+			if err := q.Start(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			job, err := q.Enqueue("kitchen", printJob("order"))
+			if err != nil {
+				t.Fatalf("Enqueue() error = %v", err)
+			}
+			got := waitState(t, q, job.ID, tt.wantState)
+
+			if got.Attempts != tt.wantAttempts {
+				t.Errorf("Attempts = %d, want %d", got.Attempts, tt.wantAttempts)
+			}
+			if tt.wantState == queue.StateFailed && got.LastError != "printer offline" {
+				t.Errorf("LastError = %q", got.LastError)
+			}
+			if printer.Calls() != tt.wantAttempts {
+				t.Errorf("printer called %d times, want %d", printer.Calls(), tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestQueue_RetryFailedJob(t *testing.T) {
+	printer := &flakyPrinter{failures: 1}
+	q := newQueue(t, t.TempDir(), fastRetry(1))
+	if err := q.AddPrinter("bar", printer); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := q.Enqueue("bar", printJob("drinks"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitState(t, q, job.ID, queue.StateFailed)
+
+	if err := q.Retry(job.ID); err != nil {
+		t.Fatalf("Retry() error = %v", err)
+	}
+	waitState(t, q, job.ID, queue.StateDone)
+
+	if err := q.Remove(job.ID); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	_, err = q.Get(job.ID)
+	testutils.AssertError(t, err, queue.ErrJobNotFound)
+}
+
+func TestQueue_OnChange(t *testing.T) {
+	var mu sync.Mutex
+	var states []queue.State
+	var q *queue.Queue
+	q, err := queue.New(&queue.Config{
+		Dir:   t.TempDir(),
+		Retry: fastRetry(2),
+		OnChange: func(job queue.Job) {
+			// The queue lock is not held, so the queue can be used from here
+			if _, err := q.Get(job.ID); err != nil {
+				t.Errorf("Get() in OnChange error = %v", err)
+			}
+			mu.Lock()
+			states = append(states, job.State)
+			mu.Unlock()
+		},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(q.Stop)
+	if err := q.AddPrinter("bar", &flakyPrinter{failures: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := q.Enqueue("bar", printJob("drinks"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitState(t, q, job.ID, queue.StateDone)
+
+	want := []queue.State{queue.StateQueued, queue.StatePrinting, queue.StateQueued, queue.StatePrinting, queue.StateDone}
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		got := append([]queue.State(nil), states...)
+		mu.Unlock()
+		if len(got) >= len(want) || time.Now().After(deadline) {
+			if !slices.Equal(got, want) {
+				t.Errorf("OnChange states = %v, want %v", got, want)
+			}
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// blockingPrinter prints until its context is done
+type blockingPrinter struct{}
+
+func (blockingPrinter) ExecuteContext(ctx context.Context, _ *document.Document) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestQueue_StopWhilePrinting(t *testing.T) {
+	q := newQueue(t, t.TempDir(), fastRetry(2))
+	if err := q.AddPrinter("bar", blockingPrinter{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := q.Enqueue("bar", printJob("drinks"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitState(t, q, job.ID, queue.StatePrinting)
+	q.Stop()
+
+	stopped, err := q.Get(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stopped.State != queue.StateQueued || stopped.Attempts != 0 {
+		t.Errorf("job = %+v, want queued without attempts", stopped)
+	}
+	if !errors.Is(stopped.Err, queue.ErrStopped) || !errors.Is(stopped.Err, context.Canceled) {
+		t.Errorf("Err = %v, want ErrStopped wrapping context.Canceled", stopped.Err)
+	}
+}
+
+func TestQueue_Recovery(t *testing.T) {
+	dir := t.TempDir()
+
+	// First process: the jobs are spooled but never printed
+	first := newQueue(t, dir, fastRetry(3))
+	if err := first.AddPrinter("front", &flakyPrinter{}); err != nil {
+		t.Fatal(err)
+	}
+	queued, err := first.Enqueue("front", printJob("queued"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	interrupted, err := first.Enqueue("front", printJob("interrupted"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a crash while printing: the job file says printing and a temporary file is left over
+	stored := readJob(t, dir, interrupted.ID)
+	stored.State = queue.StatePrinting
+	data, _ := json.Marshal(stored)
+	if err := os.WriteFile(filepath.Join(dir, interrupted.ID+".json"), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".job-123.tmp"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Second process
+	second := newQueue(t, dir, fastRetry(3))
+	if got := second.List(""); len(got) != 2 || got[0].ID != queued.ID || got[1].ID != interrupted.ID {
+		t.Fatalf("List() = %+v", got)
+	}
+	if job, _ := second.Get(interrupted.ID); job.State != queue.StateQueued {
+		t.Errorf("recovered job is %s, want queued", job.State)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".job-123.tmp")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("temporary file was not removed: %v", err)
+	}
+
+	printer := &flakyPrinter{}
+	if err := second.AddPrinter("front", printer); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	waitState(t, second, queued.ID, queue.StateDone)
+	waitState(t, second, interrupted.ID, queue.StateDone)
+	if printer.Calls() != 2 {
+		t.Errorf("printer called %d times, want 2", printer.Calls())
+	}
+}
+
+func TestQueue_Errors(t *testing.T) {
+	q := newQueue(t, t.TempDir(), fastRetry(3))
+	if err := q.AddPrinter("front", &flakyPrinter{}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := q.Enqueue("back", printJob("lost"))
+	testutils.AssertError(t, err, queue.ErrUnknownPrinter)
+
+	job, err := q.Enqueue("front", printJob("waiting"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertError(t, q.Remove(job.ID), queue.ErrJobActive)
+
+	if err := q.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertError(t, q.Start(context.Background()), queue.ErrStarted)
+
+	if _, err := queue.New(nil); err == nil {
+		t.Error("New(nil) expected error")
+	}
+}
@@ -0,0 +1,264 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/adcondev/pos-printer/pkg/commands/buzzer"
+	"github.com/adcondev/pos-printer/pkg/connection"
+	"github.com/adcondev/pos-printer/pkg/profile"
+)
+
+// Config file formats
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// Connection types accepted in ConnectionConfig.Type
+const (
+	TypeNetwork = "network"
+	TypeUSB     = "usb"
+	TypeSerial  = "serial"
+	TypeWindows = "windows"
+	TypeFile    = "file"
+	TypeLPD     = "lpd"
+	TypeIPP     = "ipp"
+)
+
+var (
+	// ErrConfig indicates an invalid registry configuration
+	ErrConfig = errors.New("invalid registry config")
+	// ErrUnknownProfile indicates a profile name that is not in Profiles
+	ErrUnknownProfile = errors.New("unknown printer profile")
+	// ErrUnknownBuzzer indicates a buzzer name that is not in Buzzers
+	ErrUnknownBuzzer = errors.New("unknown buzzer")
+)
+
+// Profiles maps the profile names accepted in config files to their constructors
+var Profiles = map[string]func() *profile.Escpos{
+	"58mm":        profile.CreateProfile58mm,
+	"80mm":        profile.CreateProfile80mm,
+	"pt-210":      profile.CreatePt210,
+	"gp-58n":      profile.CreateGP58N,
+	"ec-pm-80250": profile.CreateECPM80250,
+	"tm-t20":      profile.CreateTMT20,
+	"tm-t88":      profile.CreateTMT88,
+	"tm-m30":      profile.CreateTMM30,
+}
+
+// Buzzers maps the buzzer names accepted in config files to the command variant they select
+var Buzzers = map[string]buzzer.Variant{
+	"none":  buzzer.NoBuzzer,
+	"esc-b": buzzer.VariantESCB,
+	"epson": buzzer.VariantEpson,
+}
+
+// Config is the content of a registry file
+type Config struct {
+	Printers []PrinterConfig `json:"printers" yaml:"printers"`
+}
+
+// PrinterConfig describes one registered printer
+type PrinterConfig struct {
+	Name       string           `json:"name" yaml:"name"`
+	Profile    string           `json:"profile" yaml:"profile"`                   // Key of Profiles
+	Buzzer     string           `json:"buzzer,omitempty" yaml:"buzzer,omitempty"` // Key of Buzzers; empty keeps the profile's
+	Connection ConnectionConfig `json:"connection" yaml:"connection"`
+}
+
+// NewProfile creates the profile of the printer, with the configured buzzer if any
+func (p PrinterConfig) NewProfile() (*profile.Escpos, error) {
+	create, ok := Profiles[p.Profile]
+	if !ok {
+		return nil, fmt.Errorf("%w %q (known: %s)", ErrUnknownProfile, p.Profile, strings.Join(keys(Profiles), ", "))
+	}
+	prof := create()
+	if p.Buzzer != "" {
+		variant, ok := Buzzers[p.Buzzer]
+		if !ok {
+			return nil, fmt.Errorf("%w %q (known: %s)", ErrUnknownBuzzer, p.Buzzer, strings.Join(keys(Buzzers), ", "))
+		}
+		prof.Buzzer = variant
+	}
+	return prof, nil
+}
+
+// ConnectionConfig describes how to reach a printer. Only the fields of the selected Type are used.
+type ConnectionConfig struct {
+	Type string `json:"type" yaml:"type"`
+
+	Address  string `json:"address,omitempty" yaml:"address,omitempty"`     // network: host[:port]; lpd: server host[:port]
+	Device   string `json:"device,omitempty" yaml:"device,omitempty"`       // usb, serial: device path
+	BaudRate int    `json:"baud_rate,omitempty" yaml:"baud_rate,omitempty"` // serial (default 9600)
+	Printer  string `json:"printer,omitempty" yaml:"printer,omitempty"`     // windows: spooler printer name
+	Path     string `json:"path,omitempty" yaml:"path,omitempty"`           // file: capture file
+	Queue    string `json:"queue,omitempty" yaml:"queue,omitempty"`         // lpd: queue name
+	URL      string `json:"url,omitempty" yaml:"url,omitempty"`             // ipp: printer URI
+
+	// Reconnect wraps the connector in a ReconnectingConnector with default settings
+	Reconnect bool `json:"reconnect,omitempty" yaml:"reconnect,omitempty"`
+}
+
+// LoadConfig reads a registry file; the format is chosen by extension (.yaml, .yml or JSON otherwise)
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read registry config: %w", err)
+	}
+
+	format := FormatJSON
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = FormatYAML
+	}
+	return ParseConfig(data, format)
+}
+
+// ParseConfig decodes a registry configuration in the given format and validates it
+func ParseConfig(data []byte, format string) (*Config, error) {
+	var cfg Config
+	switch format {
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&cfg); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrConfig, err)
+		}
+	case FormatYAML:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&cfg); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrConfig, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrConfig, format)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks names, profiles and connection types without opening any connection
+func (c *Config) Validate() error {
+	seen := make(map[string]bool, len(c.Printers))
+	for i, p := range c.Printers {
+		if p.Name == "" {
+			return fmt.Errorf("%w: printer %d has no name", ErrConfig, i)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("%w: duplicate printer %q", ErrConfig, p.Name)
+		}
+		seen[p.Name] = true
+
+		if _, err := p.NewProfile(); err != nil {
+			return fmt.Errorf("%w: printer %q: %w", ErrConfig, p.Name, err)
+		}
+		if err := p.Connection.validate(); err != nil {
+			return fmt.Errorf("%w: printer %q: %w", ErrConfig, p.Name, err)
+		}
+	}
+	return nil
+}
+
+// Factory returns a connector factory for the configuration
+func (c ConnectionConfig) Factory() (connection.ConnectorFactory, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+
+	factory := c.open
+	if !c.Reconnect {
+		return factory, nil
+	}
+	return func() (connection.Connector, error) {
+		return connection.NewReconnectingConnector(factory, nil)
+	}, nil
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+// validate checks that the fields required by the connection type are set
+func (c ConnectionConfig) validate() error {
+	var missing string
+	switch c.Type {
+	case TypeNetwork:
+		if c.Address == "" {
+			missing = "address"
+		}
+	case TypeUSB, TypeSerial:
+		if c.Device == "" {
+			missing = "device"
+		}
+	case TypeWindows:
+		if c.Printer == "" {
+			missing = "printer"
+		}
+	case TypeFile:
+		if c.Path == "" {
+			missing = "path"
+		}
+	case TypeLPD:
+		if c.Address == "" || c.Queue == "" {
+			missing = "address and queue"
+		}
+	case TypeIPP:
+		if c.URL == "" {
+			missing = "url"
+		}
+	default:
+		return fmt.Errorf("unknown connection type %q", c.Type)
+	}
+	if missing != "" {
+		return fmt.Errorf("%s connection needs %s", c.Type, missing)
+	}
+	return nil
+}
+
+// open creates the connector described by c
+func (c ConnectionConfig) open() (connection.Connector, error) {
+	switch c.Type {
+	case TypeNetwork:
+		return connection.NewNetworkConnector(connection.DefaultNetworkConfig(c.Address))
+	case TypeUSB:
+		return connection.NewUSBConnector(connection.DefaultUSBConfig(c.Device))
+	case TypeSerial:
+		cfg := connection.DefaultSerialConfig(c.Device)
+		if c.BaudRate > 0 {
+			cfg.BaudRate = c.BaudRate
+		}
+		return connection.NewSerialConnector(cfg)
+	case TypeWindows:
+		return connection.NewWindowsPrintConnector(c.Printer)
+	case TypeFile:
+		return connection.NewFileConnector(&connection.FileConfig{Path: c.Path})
+	case TypeLPD:
+		return connection.NewLPDConnector(connection.DefaultLPDConfig(c.Address, c.Queue))
+	case TypeIPP:
+		return connection.NewIPPConnector(connection.DefaultIPPConfig(c.URL))
+	default:
+		return nil, fmt.Errorf("unknown connection type %q", c.Type)
+	}
+}
+
+// keys returns the names accepted in a config map, in order
+func keys[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
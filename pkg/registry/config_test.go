@@ -0,0 +1,180 @@
+package registry_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/registry"
+)
+
+const yamlConfig = `printers:
+  - name: front-receipt
+    profile: pt-210
+    connection:
+      type: network
+      address: 192.168.1.50:9100
+  - name: kitchen-1
+    profile: 80mm
+    connection:
+      type: lpd
+      address: print-server.local
+      queue: kitchen
+      reconnect: true
+`
+
+const jsonConfig = `{
+  "printers": [
+    {"name": "front-receipt", "profile": "pt-210", "connection": {"type": "network", "address": "192.168.1.50:9100"}},
+    {"name": "kitchen-1", "profile": "80mm", "connection": {"type": "lpd", "address": "print-server.local", "queue": "kitchen", "reconnect": true}}
+  ]
+}`
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"printers.yaml": yamlConfig,
+		"printers.yml":  yamlConfig,
+		"printers.json": jsonConfig,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := registry.LoadConfig(path)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if len(cfg.Printers) != 2 {
+				t.Fatalf("LoadConfig() read %d printers, want 2", len(cfg.Printers))
+			}
+			kitchen := cfg.Printers[1]
+			if kitchen.Connection.Queue != "kitchen" || !kitchen.Connection.Reconnect {
+				t.Errorf("kitchen-1 connection = %+v", kitchen.Connection)
+			}
+
+			reg, err := registry.Load(path)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			defer reg.Close()
+			if got := reg.Names(); len(got) != 2 || got[0] != "front-receipt" || got[1] != "kitchen-1" {
+				t.Errorf("Names() = %v", got)
+			}
+
+			// Nothing is dialed until Get
+			if h, _ := reg.Health("front-receipt"); h.State != registry.StateUnknown {
+				t.Errorf("Health() = %v, want unknown", h.State)
+			}
+		})
+	}
+}
+
+func TestParseConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   string
+	}{
+		{"unknown profile", registry.FormatJSON,
+			`{"printers":[{"name":"a","profile":"tm-x","connection":{"type":"network","address":"h"}}]}`},
+		{"unknown connection type", registry.FormatJSON,
+			`{"printers":[{"name":"a","profile":"80mm","connection":{"type":"bluetooth"}}]}`},
+		{"missing address", registry.FormatJSON,
+			`{"printers":[{"name":"a","profile":"80mm","connection":{"type":"network"}}]}`},
+		{"lpd without queue", registry.FormatJSON,
+			`{"printers":[{"name":"a","profile":"80mm","connection":{"type":"lpd","address":"h"}}]}`},
+		{"duplicate name", registry.FormatJSON,
+			`{"printers":[{"name":"a","profile":"80mm","connection":{"type":"file","path":"a.bin"}},` +
+				`{"name":"a","profile":"58mm","connection":{"type":"file","path":"b.bin"}}]}`},
+		{"missing name", registry.FormatYAML,
+			"printers:\n  - profile: 80mm\n    connection: {type: file, path: a.bin}\n"},
+		{"unknown field", registry.FormatYAML,
+			"printers:\n  - name: a\n    profile: 80mm\n    model: x\n    connection: {type: file, path: a.bin}\n"},
+		{"unknown buzzer", registry.FormatYAML,
+			"printers:\n  - name: a\n    profile: 80mm\n    buzzer: bell\n    connection: {type: file, path: a.bin}\n"},
+		{"unknown format", "toml", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := registry.ParseConfig([]byte(tt.data), tt.format)
+			testutils.AssertError(t, err, registry.ErrConfig)
+		})
+	}
+}
+
+func TestConnectionConfig_Factory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.bin")
+	cfg := registry.ConnectionConfig{Type: registry.TypeFile, Path: path}
+
+	factory, err := cfg.Factory()
+	if err != nil {
+		t.Fatalf("Factory() error = %v", err)
+	}
+	conn, err := factory()
+	if err != nil {
+		t.Fatalf("factory() error = %v", err)
+	}
+	if _, err := conn.Write([]byte{0x1B, '@'}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := conn.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "\x1b@" {
+		t.Errorf("capture = %q, %v", data, err)
+	}
+}
+
+func TestFromConfig_Buzzer(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		profile string
+		buzzer  string
+		want    []byte
+	}{
+		{"configured ESC B", "80mm", "esc-b", []byte{0x1B, 'B', 2, 3}},
+		{"profile default", "tm-t20", "", []byte{0x1B, '(', 'A', 4, 0, 48, '1', 2, 2}},
+		{"configured off", "tm-t88", "none", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.profile+".bin")
+			cfg := &registry.Config{Printers: []registry.PrinterConfig{{
+				Name:       "kitchen",
+				Profile:    tt.profile,
+				Buzzer:     tt.buzzer,
+				Connection: registry.ConnectionConfig{Type: registry.TypeFile, Path: path},
+			}}}
+			reg, err := registry.FromConfig(cfg)
+			if err != nil {
+				t.Fatalf("FromConfig() error = %v", err)
+			}
+			p, err := reg.Get("kitchen")
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if err := p.Beep(2, 120); err != nil {
+				t.Fatalf("Beep() error = %v", err)
+			}
+			if err := reg.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutils.AssertBytes(t, data, tt.want, "Beep(2, 120)")
+		})
+	}
+}
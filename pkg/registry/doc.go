@@ -0,0 +1,21 @@
+// Package registry maps logical printer names, such as "front-receipt" or "kitchen-1", to a
+// profile and a connector factory.
+//
+// Printers are connected lazily on the first Get and reused afterwards. The registry keeps
+// the health of every printer: connection failures, failures reported by callers and the
+// result of real-time status checks. A registry can be built in code with Register or
+// loaded from a JSON or YAML file:
+//
+//	printers:
+//	  - name: front-receipt
+//	    profile: pt-210
+//	    connection:
+//	      type: network
+//	      address: 192.168.1.50:9100
+//	  - name: kitchen-1
+//	    profile: 80mm
+//	    connection:
+//	      type: lpd
+//	      address: print-server.local
+//	      queue: kitchen
+package registry
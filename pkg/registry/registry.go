@@ -0,0 +1,352 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/adcondev/pos-printer/pkg/composer"
+	"github.com/adcondev/pos-printer/pkg/connection"
+	"github.com/adcondev/pos-printer/pkg/printer"
+	"github.com/adcondev/pos-printer/pkg/profile"
+)
+
+var (
+	// ErrNotRegistered indicates a name with no registered printer
+	ErrNotRegistered = errors.New("printer not registered")
+	// ErrAlreadyRegistered indicates that the name is already in use
+	ErrAlreadyRegistered = errors.New("printer already registered")
+	// ErrUnavailable indicates that the printer could not be connected
+	ErrUnavailable = errors.New("printer unavailable")
+	// ErrClosed indicates that the registry was closed
+	ErrClosed = errors.New("registry closed")
+)
+
+// State is the health state of a registered printer
+type State int
+
+const (
+	// StateUnknown means the printer has not been connected yet
+	StateUnknown State = iota
+	// StateHealthy means the printer is connected and its last check, if any, succeeded
+	StateHealthy
+	// StateUnhealthy means the last connection attempt, check or reported job failed
+	StateUnhealthy
+)
+
+// String returns the name of the state
+func (s State) String() string {
+	switch s {
+	case StateUnknown:
+		return "unknown"
+	case StateHealthy:
+		return "healthy"
+	case StateUnhealthy:
+		return "unhealthy"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// Health is the last known health of a registered printer
+type Health struct {
+	State   State
+	Err     error     // Cause of StateUnhealthy
+	Updated time.Time // Zero while StateUnknown
+}
+
+// Registry maps printer names to profiles and connector factories. It is safe for concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	printers map[string]*entry
+	closed   bool
+}
+
+// entry is a registered printer
+type entry struct {
+	profile profile.Escpos
+	factory connection.ConnectorFactory
+
+	connMu  sync.Mutex // serializes connection attempts
+	mu      sync.Mutex // guards printer and health
+	printer *service.Printer
+	health  Health
+}
+
+// New creates an empty registry
+func New() *Registry {
+	return &Registry{printers: make(map[string]*entry)}
+}
+
+// FromConfig creates a registry with the printers of cfg
+func FromConfig(cfg *Config) (*Registry, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("%w: config cannot be nil", ErrConfig)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	r := New()
+	for _, p := range cfg.Printers {
+		factory, err := p.Connection.Factory()
+		if err != nil {
+			return nil, fmt.Errorf("%w: printer %q: %w", ErrConfig, p.Name, err)
+		}
+		prof, err := p.NewProfile()
+		if err != nil {
+			return nil, fmt.Errorf("%w: printer %q: %w", ErrConfig, p.Name, err)
+		}
+		if err := r.Register(p.Name, prof, factory); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Load creates a registry from a JSON or YAML file (see LoadConfig)
+func Load(path string) (*Registry, error) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return FromConfig(cfg)
+}
+
+// Register adds a printer; it is connected by factory on the first Get
+func (r *Registry) Register(name string, prof *profile.Escpos, factory connection.ConnectorFactory) error {
+	if name == "" {
+		return errors.New("printer name cannot be empty")
+	}
+	if prof == nil {
+		return errors.New("profile cannot be nil")
+	}
+	if factory == nil {
+		return errors.New("connector factory cannot be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return ErrClosed
+	}
+	if _, ok := r.printers[name]; ok {
+		return fmt.Errorf("%w: %q", ErrAlreadyRegistered, name)
+	}
+	r.printers[name] = &entry{profile: *prof, factory: factory}
+	return nil
+}
+
+// Has reports whether name is registered
+func (r *Registry) Has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.printers[name]
+	return ok
+}
+
+// Names returns the registered names in order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.printers))
+	for name := range r.printers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Profile returns a copy of the profile registered for name without connecting the printer
+func (r *Registry) Profile(name string) (profile.Escpos, error) {
+	e, err := r.lookup(name)
+	if err != nil {
+		return profile.Escpos{}, err
+	}
+	return e.profile, nil
+}
+
+// Get returns the printer registered as name, connecting it on first use or after a
+// reported failure. Every call returns the same *service.Printer while it stays connected.
+func (r *Registry) Get(name string) (*service.Printer, error) {
+	e, err := r.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if p := e.current(); p != nil {
+		return p, nil
+	}
+
+	// Only one caller dials; the others wait and reuse its printer
+	e.connMu.Lock()
+	defer e.connMu.Unlock()
+	if p := e.current(); p != nil {
+		return p, nil
+	}
+
+	p, err := e.connect()
+	if err != nil {
+		e.setHealth(StateUnhealthy, err)
+		return nil, fmt.Errorf("%w: %q: %w", ErrUnavailable, name, err)
+	}
+
+	// Close may have run while dialing; it would not see this printer. Holding the read
+	// lock until the printer is stored makes a later Close wait for it and close it.
+	r.mu.RLock()
+	if r.closed {
+		r.mu.RUnlock()
+		_ = p.Close()
+		return nil, ErrClosed
+	}
+	e.mu.Lock()
+	e.printer = p
+	e.mu.Unlock()
+	r.mu.RUnlock()
+
+	e.setHealth(StateHealthy, nil)
+	return p, nil
+}
+
+// Health returns the last known health of the printer
+func (r *Registry) Health(name string) (Health, error) {
+	e, err := r.lookup(name)
+	if err != nil {
+		return Health{}, err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.health, nil
+}
+
+// Check connects the printer if needed and, when the connection is bidirectional, queries its
+// real-time status. A printer that is offline or out of paper is reported as unhealthy.
+func (r *Registry) Check(ctx context.Context, name string) (Health, error) {
+	p, err := r.Get(name)
+	if err != nil {
+		return r.Health(name)
+	}
+
+	e, err := r.lookup(name)
+	if err != nil {
+		return Health{}, err
+	}
+	if !p.IsBidirectional() {
+		e.setHealth(StateHealthy, nil)
+		return r.Health(name)
+	}
+
+	status, err := p.Status(ctx)
+	switch {
+	case err != nil:
+		e.setHealth(StateUnhealthy, err)
+	case !status.Ready():
+		e.setHealth(StateUnhealthy, fmt.Errorf("printer not ready (%+v)", status))
+	default:
+		e.setHealth(StateHealthy, nil)
+	}
+	return r.Health(name)
+}
+
+// ReportFailure marks the printer as unhealthy after a failed job and drops its connection,
+// so the next Get reconnects. Goroutines may still hold the old printer, so it is closed in
+// the background once no job is active on it (see service.Printer.CloseWhenIdle).
+func (r *Registry) ReportFailure(name string, cause error) {
+	e, err := r.lookup(name)
+	if err != nil {
+		return
+	}
+	if cause == nil {
+		cause = errors.New("failure reported")
+	}
+
+	e.mu.Lock()
+	p := e.printer
+	e.printer = nil
+	e.mu.Unlock()
+	e.setHealth(StateUnhealthy, cause)
+
+	if p != nil {
+		go func() { _ = p.CloseWhenIdle(context.Background()) }()
+	}
+}
+
+// Close closes every connected printer; the registry cannot be used afterwards
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	entries := make([]*entry, 0, len(r.printers))
+	for _, e := range r.printers {
+		entries = append(entries, e)
+	}
+	r.mu.Unlock()
+
+	var errs []error
+	for _, e := range entries {
+		e.mu.Lock()
+		p := e.printer
+		e.printer = nil
+		e.mu.Unlock()
+		if p != nil {
+			errs = append(errs, p.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+// lookup returns the entry registered as name
+func (r *Registry) lookup(name string) (*entry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		return nil, ErrClosed
+	}
+	e, ok := r.printers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNotRegistered, name)
+	}
+	return e, nil
+}
+
+// current returns the connected printer, if any
+func (e *entry) current() *service.Printer {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.printer
+}
+
+// connect opens a connection and wraps it in a printer with a copy of the profile
+func (e *entry) connect() (*service.Printer, error) {
+	conn, err := e.factory()
+	if err != nil {
+		return nil, err
+	}
+	prof := e.profile
+	p, err := service.NewPrinter(composer.NewEscpos(), &prof, conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// setHealth records a new health state
+func (e *entry) setHealth(state State, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.health = Health{State: state, Err: err, Updated: time.Now()}
+}
@@ -0,0 +1,294 @@
+package registry_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/connection"
+	service "github.com/adcondev/pos-printer/pkg/printer"
+	"github.com/adcondev/pos-printer/pkg/profile"
+	"github.com/adcondev/pos-printer/pkg/registry"
+)
+
+// countingFactory returns a factory of fake connectors and the number of times it was called
+func countingFactory(fail error) (connection.ConnectorFactory, *atomic.Int32, *[]*testutils.FakeConnector) {
+	var calls atomic.Int32
+	var mu sync.Mutex
+	var conns []*testutils.FakeConnector
+	factory := func() (connection.Connector, error) {
+		calls.Add(1)
+		if fail != nil {
+			return nil, fail
+		}
+		c := testutils.NewFakeConnector()
+		mu.Lock()
+		conns = append(conns, c)
+		mu.Unlock()
+		return c, nil
+	}
+	return factory, &calls, &conns
+}
+
+func TestRegistry_LazyConnectionAndReuse(t *testing.T) {
+	reg := registry.New()
+	factory, calls, _ := countingFactory(nil)
+	if err := reg.Register("front-receipt", profile.CreatePt210(), factory); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	if calls.Load() != 0 {
+		t.Fatalf("factory called %d times before Get, want 0", calls.Load())
+	}
+	h, err := reg.Health("front-receipt")
+	if err != nil || h.State != registry.StateUnknown {
+		t.Fatalf("Health() = %v, %v, want unknown", h.State, err)
+	}
+
+	const workers = 16
+	printers := make([]*service.Printer, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			p, err := reg.Get("front-receipt")
+			if err != nil {
+				t.Errorf("Get() error = %v", err)
+			}
+			printers[i] = p
+		}(i)
+	}
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("factory called %d times, want 1", calls.Load())
+	}
+	for i, p := range printers {
+		if p != printers[0] {
+			t.Fatalf("Get() #%d returned a different printer", i)
+		}
+	}
+	if printers[0].Profile.Model != "58mm PT-210" {
+		t.Errorf("Profile.Model = %q, want the registered profile", printers[0].Profile.Model)
+	}
+	if h, _ := reg.Health("front-receipt"); h.State != registry.StateHealthy {
+		t.Errorf("Health() = %v, want healthy", h.State)
+	}
+}
+
+func TestRegistry_Errors(t *testing.T) {
+	reg := registry.New()
+	factory, _, _ := countingFactory(nil)
+	if err := reg.Register("kitchen-1", profile.CreateProfile80mm(), factory); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	err := reg.Register("kitchen-1", profile.CreateProfile80mm(), factory)
+	testutils.AssertError(t, err, registry.ErrAlreadyRegistered)
+
+	_, err = reg.Get("kitchen-2")
+	testutils.AssertError(t, err, registry.ErrNotRegistered)
+
+	if err := reg.Register("", profile.CreateProfile80mm(), factory); err == nil {
+		t.Error("Register() with empty name expected error")
+	}
+	if err := reg.Register("bar", nil, factory); err == nil {
+		t.Error("Register() with nil profile expected error")
+	}
+	if err := reg.Register("bar", profile.CreateProfile80mm(), nil); err == nil {
+		t.Error("Register() with nil factory expected error")
+	}
+
+	if got := reg.Names(); len(got) != 1 || got[0] != "kitchen-1" {
+		t.Errorf("Names() = %v, want [kitchen-1]", got)
+	}
+}
+
+func TestRegistry_UnavailableAndReportFailure(t *testing.T) {
+	reg := registry.New()
+	refused := errors.New("connection refused")
+	bad, calls, _ := countingFactory(refused)
+	if err := reg.Register("bar", profile.CreateProfile80mm(), bad); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	// Failed connections are retried on every Get
+	for i := 0; i < 2; i++ {
+		_, err := reg.Get("bar")
+		testutils.AssertError(t, err, registry.ErrUnavailable)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("factory called %d times, want 2", calls.Load())
+	}
+	h, _ := reg.Health("bar")
+	if h.State != registry.StateUnhealthy || !errors.Is(h.Err, refused) {
+		t.Errorf("Health() = %+v, want unhealthy with the dial error", h)
+	}
+
+	good, calls, conns := countingFactory(nil)
+	if err := reg.Register("front", profile.CreateProfile80mm(), good); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	first, err := reg.Get("front")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	// A reported failure closes the connection and the next Get reconnects
+	reg.ReportFailure("front", errors.New("paper jam"))
+	if h, _ := reg.Health("front"); h.State != registry.StateUnhealthy {
+		t.Errorf("Health() after ReportFailure = %v, want unhealthy", h.State)
+	}
+	waitClosed(t, (*conns)[0], "ReportFailure")
+	second, err := reg.Get("front")
+	if err != nil {
+		t.Fatalf("Get() after ReportFailure error = %v", err)
+	}
+	if second == first || calls.Load() != 2 {
+		t.Errorf("Get() after ReportFailure reused the old printer (%d dials)", calls.Load())
+	}
+	if h, _ := reg.Health("front"); h.State != registry.StateHealthy {
+		t.Errorf("Health() after reconnect = %v, want healthy", h.State)
+	}
+}
+
+func TestRegistry_ReportFailureDuringJob(t *testing.T) {
+	reg := registry.New()
+	factory, _, conns := countingFactory(nil)
+	if err := reg.Register("front", profile.CreateProfile80mm(), factory); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	p, err := reg.Get("front")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	// Another goroutine reports a failure while this one is printing
+	job, err := p.BeginJob("receipt")
+	if err != nil {
+		t.Fatalf("BeginJob() error = %v", err)
+	}
+	reg.ReportFailure("front", errors.New("paper jam"))
+	time.Sleep(20 * time.Millisecond)
+	if (*conns)[0].IsClosed() {
+		t.Fatal("ReportFailure closed the connection of an active job")
+	}
+	if err := job.Write([]byte("total")); err != nil {
+		t.Errorf("Write() after ReportFailure error = %v", err)
+	}
+	if err := job.EndJob(); err != nil {
+		t.Fatalf("EndJob() error = %v", err)
+	}
+	waitClosed(t, (*conns)[0], "EndJob")
+}
+
+func TestRegistry_Check(t *testing.T) {
+	tests := []struct {
+		name        string
+		paperStatus byte
+		want        registry.State
+	}{
+		{"ready", 0x12, registry.StateHealthy},
+		{"paper end", 0x72, registry.StateUnhealthy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := registry.New()
+			factory := func() (connection.Connector, error) {
+				fake := testutils.NewFakeConnector()
+				fake.Responder = func(written []byte) []byte {
+					if len(written) == 3 && written[0] == 0x10 && written[1] == 0x04 {
+						if written[2] == 4 {
+							return []byte{tt.paperStatus}
+						}
+						return []byte{0x12}
+					}
+					return nil
+				}
+				return fake, nil
+			}
+			if err := reg.Register("front", profile.CreateProfile80mm(), factory); err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			h, err := reg.Check(ctx, "front")
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if h.State != tt.want {
+				t.Errorf("Check() = %v (%v), want %v", h.State, h.Err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistry_GetDuringClose(t *testing.T) {
+	reg := registry.New()
+	dialing := make(chan struct{})
+	release := make(chan struct{})
+	fake := testutils.NewFakeConnector()
+	factory := func() (connection.Connector, error) {
+		close(dialing)
+		<-release
+		return fake, nil
+	}
+	if err := reg.Register("front", profile.CreateProfile80mm(), factory); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	got := make(chan error, 1)
+	go func() {
+		_, err := reg.Get("front")
+		got <- err
+	}()
+	<-dialing
+	if err := reg.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	close(release)
+
+	testutils.AssertError(t, <-got, registry.ErrClosed)
+	if !fake.IsClosed() {
+		t.Error("connection dialed during Close was leaked")
+	}
+}
+
+func TestRegistry_Close(t *testing.T) {
+	reg := registry.New()
+	factory, _, conns := countingFactory(nil)
+	if err := reg.Register("front", profile.CreateProfile80mm(), factory); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if _, err := reg.Get("front"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if err := reg.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if !(*conns)[0].IsClosed() {
+		t.Error("Close did not close the connection")
+	}
+	_, err := reg.Get("front")
+	testutils.AssertError(t, err, registry.ErrClosed)
+}
+
+// waitClosed fails the test unless c is closed within a second
+func waitClosed(t *testing.T, c *testutils.FakeConnector, after string) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !c.IsClosed() {
+		if time.Now().After(deadline) {
+			t.Fatalf("connection not closed after %s", after)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,18 @@
+// Package server provides an HTTP API that prints, validates and previews documents on the
+// printers of a registry. Print jobs go through a persistent queue, so a ticket sent while
+// its printer is offline is printed once the printer comes back.
+//
+// Endpoints:
+//
+//	POST /v1/printers/{name}/jobs     queue a document.PrintJob (202 with the job)
+//	GET  /v1/printers/{name}/jobs     list the jobs of a printer
+//	GET  /v1/jobs/{id}                job status
+//	GET  /v1/printers                 printers and their health
+//	GET  /v1/printers/{name}          printer health; ?check=true queries the printer
+//	POST /v1/documents/validate       validate a document.PrintJob without printing
+//	POST /v1/documents/preview        compiled ESC/POS bytes of a document.PrintJob
+//	GET  /healthz                     liveness, without authentication
+//
+// validate and preview accept ?printer=name to use the profile of a registered printer.
+// Errors are returned as {"error": {"code": "...", "message": "..."}}.
+package server
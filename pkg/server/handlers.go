@@ -0,0 +1,347 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/adcondev/pos-printer/pkg/composer"
+	"github.com/adcondev/pos-printer/pkg/document"
+	"github.com/adcondev/pos-printer/pkg/printer"
+	"github.com/adcondev/pos-printer/pkg/profile"
+	"github.com/adcondev/pos-printer/pkg/queue"
+	"github.com/adcondev/pos-printer/pkg/registry"
+)
+
+// Error codes of the JSON error responses
+const (
+	codeNotFound        = "not_found"
+	codeUnauthorized    = "unauthorized"
+	codeInvalidJSON     = "invalid_json"
+	codeBodyTooLarge    = "body_too_large"
+	codeInvalidDocument = "invalid_document"
+	codeInternal        = "internal"
+)
+
+// errorResponse is the body of every error response
+type errorResponse struct {
+	Error apiError `json:"error"`
+}
+
+// apiError describes a failed request
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// jobResponse is a queued job without its document
+type jobResponse struct {
+	ID          string      `json:"id"`
+	Printer     string      `json:"printer"`
+	State       queue.State `json:"state"`
+	Attempts    int         `json:"attempts"`
+	LastError   string      `json:"last_error,omitempty"`
+	Created     time.Time   `json:"created"`
+	Updated     time.Time   `json:"updated"`
+	NextAttempt *time.Time  `json:"next_attempt,omitempty"`
+}
+
+// printerResponse is the status of a registered printer
+type printerResponse struct {
+	Name    string     `json:"name"`
+	Model   string     `json:"model"`
+	State   string     `json:"state"`
+	Error   string     `json:"error,omitempty"`
+	Updated *time.Time `json:"updated,omitempty"`
+	Pending int        `json:"pending_jobs"` // Queued and printing jobs
+}
+
+// validateResponse is the body of a successful validation
+type validateResponse struct {
+	Valid bool `json:"valid"`
+	Size  int  `json:"size"` // Size of the compiled document in bytes
+}
+
+// previewResponse is the JSON body of a preview
+type previewResponse struct {
+	Size   int    `json:"size"`
+	Escpos []byte `json:"escpos"` // Base64 in JSON
+}
+
+// discardConnector swallows the output of preview printers
+type discardConnector struct{}
+
+func (discardConnector) Write(p []byte) (int, error) { return len(p), nil }
+
+func (discardConnector) Close() error { return nil }
+
+// previewResolver resolves one name to a preview printer, so documents compile exactly as
+// they would print on the registered printer
+type previewResolver struct {
+	name    string
+	printer *service.Printer
+}
+
+func (r previewResolver) Has(name string) bool { return name == r.name }
+
+func (r previewResolver) Get(string) (*service.Printer, error) { return r.printer, nil }
+
+// ============================================================================
+// Handlers
+// ============================================================================
+
+func (s *Server) handleHealthz(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleListPrinters(w http.ResponseWriter, _ *http.Request) {
+	printers := make([]printerResponse, 0)
+	for _, name := range s.registry.Names() {
+		p, err := s.printerStatus(name, nil)
+		if err != nil {
+			continue // Removed while listing
+		}
+		printers = append(printers, p)
+	}
+	writeJSON(w, http.StatusOK, map[string][]printerResponse{"printers": printers})
+}
+
+func (s *Server) handleGetPrinter(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	var health *registry.Health
+	if check, _ := strconv.ParseBool(r.URL.Query().Get("check")); check {
+		h, err := s.registry.Check(r.Context(), name)
+		if err != nil {
+			writeFailure(w, err)
+			return
+		}
+		health = &h
+	}
+
+	p, err := s.printerStatus(name, health)
+	if err != nil {
+		writeFailure(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+func (s *Server) handleSubmitJob(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if !s.registry.Has(name) {
+		writeError(w, http.StatusNotFound, codeNotFound, fmt.Sprintf("printer %q not registered", name))
+		return
+	}
+
+	job, ok := s.readJob(w, r)
+	if !ok {
+		return
+	}
+	// The printer is chosen by the URL; profile.model routes the document to it
+	job.Data.Profile.Model = name
+	if _, err := s.compile(name, &job.Data); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, codeInvalidDocument, err.Error())
+		return
+	}
+
+	queued, err := s.queue.Enqueue(name, *job)
+	if err != nil {
+		writeFailure(w, err)
+		return
+	}
+	w.Header().Set("Location", "/v1/jobs/"+queued.ID)
+	writeJSON(w, http.StatusAccepted, newJobResponse(queued))
+}
+
+func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if !s.registry.Has(name) {
+		writeError(w, http.StatusNotFound, codeNotFound, fmt.Sprintf("printer %q not registered", name))
+		return
+	}
+
+	jobs := make([]jobResponse, 0)
+	for _, job := range s.queue.List(name) {
+		jobs = append(jobs, newJobResponse(job))
+	}
+	writeJSON(w, http.StatusOK, map[string][]jobResponse{"jobs": jobs})
+}
+
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	job, err := s.queue.Get(r.PathValue("id"))
+	if err != nil {
+		writeFailure(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newJobResponse(job))
+}
+
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	data, ok := s.compileRequest(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, validateResponse{Valid: true, Size: len(data)})
+}
+
+func (s *Server) handlePreview(w http.ResponseWriter, r *http.Request) {
+	data, ok := s.compileRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if r.URL.Query().Get("format") == "raw" || r.Header.Get("Accept") == "application/octet-stream" {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(data)
+		return
+	}
+	writeJSON(w, http.StatusOK, previewResponse{Size: len(data), Escpos: data})
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+// readJob decodes the document.PrintJob of a request body, writing the error response on failure
+func (s *Server) readJob(w http.ResponseWriter, r *http.Request) (*document.PrintJob, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.config.MaxBodyBytes)
+
+	var job document.PrintJob
+	if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, codeBodyTooLarge,
+				fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+			return nil, false
+		}
+		writeError(w, http.StatusBadRequest, codeInvalidJSON, err.Error())
+		return nil, false
+	}
+	if len(job.Data.Commands) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, codeInvalidDocument, "document must contain at least one command")
+		return nil, false
+	}
+	return &job, true
+}
+
+// compileRequest decodes and compiles the document of a validate or preview request
+func (s *Server) compileRequest(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	name := r.URL.Query().Get("printer")
+	if name != "" && !s.registry.Has(name) {
+		writeError(w, http.StatusNotFound, codeNotFound, fmt.Sprintf("printer %q not registered", name))
+		return nil, false
+	}
+
+	job, ok := s.readJob(w, r)
+	if !ok {
+		return nil, false
+	}
+	if name != "" {
+		job.Data.Profile.Model = name
+	}
+
+	data, err := s.compile(name, &job.Data)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, codeInvalidDocument, err.Error())
+		return nil, false
+	}
+	return data, true
+}
+
+// compile renders doc without printing it: with the profile of the printer registered as
+// name or, when name is empty, with an 80mm profile adjusted by the document
+func (s *Server) compile(name string, doc *document.Document) ([]byte, error) {
+	prof := profile.CreateProfile80mm()
+	if name != "" {
+		registered, err := s.registry.Profile(name)
+		if err != nil {
+			return nil, err
+		}
+		prof = &registered
+	}
+
+	p, err := service.NewPrinter(composer.NewEscpos(), prof, discardConnector{})
+	if err != nil {
+		return nil, err
+	}
+	executor := document.NewExecutor(p)
+	if name != "" {
+		executor.SetResolver(previewResolver{name: name, printer: p})
+	}
+	return executor.Compile(doc)
+}
+
+// printerStatus returns the status of a printer, using health if given
+func (s *Server) printerStatus(name string, health *registry.Health) (printerResponse, error) {
+	prof, err := s.registry.Profile(name)
+	if err != nil {
+		return printerResponse{}, err
+	}
+	if health == nil {
+		h, err := s.registry.Health(name)
+		if err != nil {
+			return printerResponse{}, err
+		}
+		health = &h
+	}
+
+	p := printerResponse{Name: name, Model: prof.Model, State: health.State.String()}
+	if health.Err != nil {
+		p.Error = health.Err.Error()
+	}
+	if !health.Updated.IsZero() {
+		p.Updated = &health.Updated
+	}
+	for _, job := range s.queue.List(name) {
+		if job.State == queue.StateQueued || job.State == queue.StatePrinting {
+			p.Pending++
+		}
+	}
+	return p, nil
+}
+
+// newJobResponse converts a queued job to its response
+func newJobResponse(job queue.Job) jobResponse {
+	resp := jobResponse{
+		ID:        job.ID,
+		Printer:   job.Printer,
+		State:     job.State,
+		Attempts:  job.Attempts,
+		LastError: job.LastError,
+		Created:   job.Created,
+		Updated:   job.Updated,
+	}
+	if !job.NextAttempt.IsZero() {
+		resp.NextAttempt = &job.NextAttempt
+	}
+	return resp
+}
+
+// writeFailure maps package errors to error responses
+func writeFailure(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, registry.ErrNotRegistered), errors.Is(err, queue.ErrJobNotFound),
+		errors.Is(err, queue.ErrUnknownPrinter):
+		writeError(w, http.StatusNotFound, codeNotFound, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
+	}
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, errorResponse{Error: apiError{Code: code, Message: message}})
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
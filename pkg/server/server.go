@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/adcondev/pos-printer/pkg/document"
+	"github.com/adcondev/pos-printer/pkg/queue"
+	"github.com/adcondev/pos-printer/pkg/registry"
+)
+
+// DefaultMaxBodyBytes is the default limit of request bodies
+const DefaultMaxBodyBytes = 1 << 20
+
+// APIKeyHeader is the header that carries the API key; "Authorization: Bearer <key>" also works
+const APIKeyHeader = "X-API-Key"
+
+// Config holds the settings of a server
+type Config struct {
+	// Registry holds the printers jobs can be sent to
+	Registry *registry.Registry
+	// SpoolDir is the directory of the job queue
+	SpoolDir string
+	// Retry is the retry policy of the job queue; the zero value means queue.DefaultRetryPolicy
+	Retry queue.RetryPolicy
+	// APIKeys are the accepted API keys; when empty, authentication is disabled
+	APIKeys []string
+	// MaxBodyBytes limits request bodies; 0 means DefaultMaxBodyBytes
+	MaxBodyBytes int64
+}
+
+// Server is the HTTP print server. It implements http.Handler.
+type Server struct {
+	config   Config
+	registry *registry.Registry
+	queue    *queue.Queue
+	executor *document.Executor
+	mux      *http.ServeMux
+	reports  sync.WaitGroup // failure reports handed off by jobChanged
+}
+
+// New creates a server and recovers the jobs of its spool directory. The queue does not
+// print until Start.
+func New(config *Config) (*Server, error) {
+	if config == nil {
+		return nil, errors.New("server config cannot be nil")
+	}
+	if config.Registry == nil {
+		return nil, errors.New("server config needs a registry")
+	}
+
+	s := &Server{
+		config:   *config,
+		registry: config.Registry,
+	}
+	if s.config.MaxBodyBytes <= 0 {
+		s.config.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+
+	// Jobs name their printer in profile.model, so the executor prints them on the
+	// registered printer with its registered profile
+	s.executor = document.NewExecutor(nil)
+	s.executor.SetResolver(s.registry)
+
+	q, err := queue.New(&queue.Config{
+		Dir:      config.SpoolDir,
+		Retry:    config.Retry,
+		OnChange: s.jobChanged,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range s.registry.Names() {
+		if err := q.AddPrinter(name, s.executor); err != nil {
+			return nil, err
+		}
+	}
+	s.queue = q
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("GET /healthz", s.handleHealthz)
+	s.mux.Handle("GET /v1/printers", s.auth(s.handleListPrinters))
+	s.mux.Handle("GET /v1/printers/{name}", s.auth(s.handleGetPrinter))
+	s.mux.Handle("POST /v1/printers/{name}/jobs", s.auth(s.handleSubmitJob))
+	s.mux.Handle("GET /v1/printers/{name}/jobs", s.auth(s.handleListJobs))
+	s.mux.Handle("GET /v1/jobs/{id}", s.auth(s.handleGetJob))
+	s.mux.Handle("POST /v1/documents/validate", s.auth(s.handleValidate))
+	s.mux.Handle("POST /v1/documents/preview", s.auth(s.handlePreview))
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		writeError(w, http.StatusNotFound, codeNotFound, "no such endpoint")
+	})
+	return s, nil
+}
+
+// Start starts printing queued jobs until ctx is done or Stop is called
+func (s *Server) Start(ctx context.Context) error {
+	return s.queue.Start(ctx)
+}
+
+// Stop stops the queue workers; unfinished jobs stay in the spool directory
+func (s *Server) Stop() {
+	s.queue.Stop()
+	s.reports.Wait()
+}
+
+// Queue returns the job queue of the server
+func (s *Server) Queue() *queue.Queue {
+	return s.queue
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// auth rejects requests without a valid API key when keys are configured
+func (s *Server) auth(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(s.config.APIKeys) > 0 && !s.validKey(requestKey(r)) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="pos-printer"`)
+			writeError(w, http.StatusUnauthorized, codeUnauthorized, "missing or invalid API key")
+			return
+		}
+		next(w, r)
+	})
+}
+
+// validKey compares key with every configured key in constant time
+func (s *Server) validKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	valid := false
+	for _, k := range s.config.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
+
+// requestKey returns the API key of a request
+func requestKey(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key
+	}
+	if auth := r.Header.Get("Authorization"); len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
+
+// jobChanged keeps the registry health in line with failed print attempts. It runs on a
+// queue worker, so the report, which drops and closes the connection, is handed to its
+// own goroutine instead of holding up the queue. Attempts interrupted by stopping the
+// queue say nothing about the printer and are not reported.
+func (s *Server) jobChanged(job queue.Job) {
+	if job.Err == nil || errors.Is(job.Err, queue.ErrStopped) {
+		return
+	}
+	if job.State == queue.StateQueued || job.State == queue.StateFailed {
+		cause := fmt.Errorf("job %s: %w", job.ID, job.Err)
+		s.reports.Add(1)
+		go func() {
+			defer s.reports.Done()
+			s.registry.ReportFailure(job.Printer, cause)
+		}()
+	}
+}
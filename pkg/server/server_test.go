@@ -0,0 +1,398 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adcondev/pos-printer/internal/testutils"
+	"github.com/adcondev/pos-printer/pkg/connection"
+	"github.com/adcondev/pos-printer/pkg/document"
+	"github.com/adcondev/pos-printer/pkg/profile"
+	"github.com/adcondev/pos-printer/pkg/queue"
+	"github.com/adcondev/pos-printer/pkg/registry"
+	"github.com/adcondev/pos-printer/pkg/server"
+)
+
+const apiKey = "secret"
+
+const ticketJob = `{"data": {"profile": {"paper_width": 80}, "commands": [
+	{"type": "text", "data": {"content": "Ticket 0042"}},
+	{"type": "cut", "data": {"mode": "partial"}}
+]}}`
+
+type testServer struct {
+	*httptest.Server
+	fake *testutils.FakeConnector
+}
+
+// newTestServer serves a registry with "front", backed by a fake connector, and "offline",
+// which cannot connect
+func newTestServer(t *testing.T, maxBody int64) *testServer {
+	t.Helper()
+	fake := testutils.NewFakeConnector()
+	fake.Responder = func(written []byte) []byte {
+		// Real-time status: online, paper present
+		if len(written) == 3 && written[0] == 0x10 && written[1] == 0x04 {
+			return []byte{0x12}
+		}
+		return nil
+	}
+
+	reg := registry.New()
+	if err := reg.Register("front", profile.CreateProfile80mm(), func() (connection.Connector, error) {
+		return fake, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Register("offline", profile.CreateProfile58mm(), func() (connection.Connector, error) {
+		return nil, errors.New("connection refused")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := server.New(&server.Config{
+		Registry:     reg,
+		SpoolDir:     t.TempDir(),
+		Retry:        queue.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+		APIKeys:      []string{apiKey},
+		MaxBodyBytes: maxBody,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer(srv)
+	t.Cleanup(func() {
+		ts.Close()
+		srv.Stop()
+		_ = reg.Close()
+	})
+	return &testServer{Server: ts, fake: fake}
+}
+
+// do sends an authenticated request and decodes the JSON response into out
+func (ts *testServer) do(t *testing.T, method, path, body string, out any) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(server.APIKeyHeader, apiKey)
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("decode %s %s response: %v", method, path, err)
+		}
+	}
+	return resp
+}
+
+type jobBody struct {
+	ID        string `json:"id"`
+	State     string `json:"state"`
+	Attempts  int    `json:"attempts"`
+	LastError string `json:"last_error"`
+}
+
+type errorBody struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// waitJob polls the job endpoint until the job reaches state
+func (ts *testServer) waitJob(t *testing.T, id, state string) jobBody {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		var job jobBody
+		ts.do(t, http.MethodGet, "/v1/jobs/"+id, "", &job)
+		if job.State == state {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s is %s, want %s", id, job.State, state)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestServer_SubmitJob(t *testing.T) {
+	ts := newTestServer(t, 0)
+
+	var job jobBody
+	resp := ts.do(t, http.MethodPost, "/v1/printers/front/jobs", ticketJob, &job)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202", resp.StatusCode)
+	}
+	if loc := resp.Header.Get("Location"); loc != "/v1/jobs/"+job.ID {
+		t.Errorf("Location = %q", loc)
+	}
+
+	ts.waitJob(t, job.ID, "done")
+	if !bytes.Contains(ts.fake.Written(), []byte("Ticket 0042")) {
+		t.Errorf("printer received %q", ts.fake.Written())
+	}
+
+	var list struct{ Jobs []jobBody }
+	ts.do(t, http.MethodGet, "/v1/printers/front/jobs", "", &list)
+	if len(list.Jobs) != 1 || list.Jobs[0].ID != job.ID {
+		t.Errorf("jobs = %+v", list.Jobs)
+	}
+}
+
+func TestServer_OfflinePrinter(t *testing.T) {
+	ts := newTestServer(t, 0)
+
+	var job jobBody
+	ts.do(t, http.MethodPost, "/v1/printers/offline/jobs", ticketJob, &job)
+	failed := ts.waitJob(t, job.ID, "failed")
+	if failed.Attempts != 2 || !strings.Contains(failed.LastError, "connection refused") {
+		t.Errorf("job = %+v", failed)
+	}
+
+	var printer struct {
+		Name  string `json:"name"`
+		State string `json:"state"`
+		Error string `json:"error"`
+	}
+	ts.do(t, http.MethodGet, "/v1/printers/offline", "", &printer)
+	if printer.State != "unhealthy" || printer.Error == "" {
+		t.Errorf("printer = %+v", printer)
+	}
+}
+
+func TestServer_FailedJobReportsPrinter(t *testing.T) {
+	ts := newTestServer(t, 0)
+	ts.fake.WriteErr = errors.New("paper jam")
+
+	var job jobBody
+	ts.do(t, http.MethodPost, "/v1/printers/front/jobs", ticketJob, &job)
+	ts.waitJob(t, job.ID, "failed")
+
+	// The failure is reported to the registry in the background
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		var printer struct {
+			State string `json:"state"`
+			Error string `json:"error"`
+		}
+		ts.do(t, http.MethodGet, "/v1/printers/front", "", &printer)
+		if printer.State == "unhealthy" && strings.Contains(printer.Error, job.ID) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("printer = %+v, want unhealthy because of job %s", printer, job.ID)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// stallConnector accepts plain writes and blocks context writes until the context is done
+type stallConnector struct {
+	started chan struct{}
+}
+
+func (c *stallConnector) Write(data []byte) (int, error) { return len(data), nil }
+func (c *stallConnector) Close() error                   { return nil }
+
+func (c *stallConnector) WriteContext(ctx context.Context, _ []byte) (int, error) {
+	select {
+	case c.started <- struct{}{}:
+	default:
+	}
+	<-ctx.Done()
+	return 0, ctx.Err()
+}
+
+func TestServer_StoppedJobDoesNotReportPrinter(t *testing.T) {
+	conn := &stallConnector{started: make(chan struct{}, 1)}
+	reg := registry.New()
+	if err := reg.Register("front", profile.CreateProfile80mm(), func() (connection.Connector, error) {
+		return conn, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = reg.Close() })
+
+	srv, err := server.New(&server.Config{Registry: reg, SpoolDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var ticket document.PrintJob
+	if err := json.Unmarshal([]byte(ticketJob), &ticket); err != nil {
+		t.Fatal(err)
+	}
+	ticket.Data.Profile.Model = "front"
+	job, err := srv.Queue().Enqueue("front", ticket)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-conn.started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("job was not printed")
+	}
+	srv.Stop()
+
+	stopped, err := srv.Queue().Get(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(stopped.Err, queue.ErrStopped) {
+		t.Fatalf("job Err = %v, want ErrStopped", stopped.Err)
+	}
+	if health, _ := reg.Health("front"); health.State != registry.StateHealthy {
+		t.Errorf("health = %+v, want healthy", health)
+	}
+}
+
+func TestServer_Printers(t *testing.T) {
+	ts := newTestServer(t, 0)
+
+	var list struct {
+		Printers []struct {
+			Name  string `json:"name"`
+			Model string `json:"model"`
+			State string `json:"state"`
+		} `json:"printers"`
+	}
+	ts.do(t, http.MethodGet, "/v1/printers", "", &list)
+	if len(list.Printers) != 2 || list.Printers[0].Name != "front" || list.Printers[0].State != "unknown" {
+		t.Fatalf("printers = %+v", list.Printers)
+	}
+
+	var front struct{ State string }
+	ts.do(t, http.MethodGet, "/v1/printers/front?check=true", "", &front)
+	if front.State != "healthy" {
+		t.Errorf("checked state = %q, want healthy", front.State)
+	}
+}
+
+func TestServer_ValidateAndPreview(t *testing.T) {
+	ts := newTestServer(t, 0)
+
+	var valid struct {
+		Valid bool `json:"valid"`
+		Size  int  `json:"size"`
+	}
+	ts.do(t, http.MethodPost, "/v1/documents/validate?printer=front", ticketJob, &valid)
+	if !valid.Valid || valid.Size == 0 {
+		t.Errorf("validate = %+v", valid)
+	}
+
+	var preview struct {
+		Size   int    `json:"size"`
+		Escpos []byte `json:"escpos"`
+	}
+	ts.do(t, http.MethodPost, "/v1/documents/preview", ticketJob, &preview)
+	if preview.Size != len(preview.Escpos) || !bytes.HasPrefix(preview.Escpos, []byte{0x1B, '@'}) {
+		t.Errorf("preview = %d bytes, % X", preview.Size, preview.Escpos)
+	}
+
+	resp, err := http.Post(ts.URL+"/v1/documents/preview?format=raw", "application/json", strings.NewReader(ticketJob))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unauthenticated preview status = %d, want 401", resp.StatusCode)
+	}
+
+	// Nothing reaches the printer
+	if len(ts.fake.Written()) != 0 {
+		t.Errorf("printer received %d bytes", len(ts.fake.Written()))
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	ts := newTestServer(t, 256)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{"unknown printer", http.MethodPost, "/v1/printers/back/jobs", ticketJob, http.StatusNotFound, "not_found"},
+		{"unknown job", http.MethodGet, "/v1/jobs/missing", "", http.StatusNotFound, "not_found"},
+		{"invalid json", http.MethodPost, "/v1/printers/front/jobs", `{"data":`, http.StatusBadRequest, "invalid_json"},
+		{"no commands", http.MethodPost, "/v1/documents/validate", `{"data":{"commands":[]}}`,
+			http.StatusUnprocessableEntity, "invalid_document"},
+		{"unknown command", http.MethodPost, "/v1/documents/validate", `{"data":{"commands":[{"type":"hologram"}]}}`,
+			http.StatusUnprocessableEntity, "invalid_document"},
+		{"body too large", http.MethodPost, "/v1/printers/front/jobs",
+			`{"data":{"commands":[{"type":"text","data":{"content":"` + strings.Repeat("x", 256) + `"}}]}}`,
+			http.StatusRequestEntityTooLarge, "body_too_large"},
+		{"unknown endpoint", http.MethodGet, "/v2/printers", "", http.StatusNotFound, "not_found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body errorBody
+			resp := ts.do(t, tt.method, tt.path, tt.body, &body)
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if body.Error.Code != tt.wantCode || body.Error.Message == "" {
+				t.Errorf("error = %+v, want code %s", body.Error, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestServer_Auth(t *testing.T) {
+	ts := newTestServer(t, 0)
+
+	tests := []struct {
+		name       string
+		path       string
+		header     string
+		value      string
+		wantStatus int
+	}{
+		{"no key", "/v1/printers", "", "", http.StatusUnauthorized},
+		{"wrong key", "/v1/printers", server.APIKeyHeader, "guess", http.StatusUnauthorized},
+		{"api key header", "/v1/printers", server.APIKeyHeader, apiKey, http.StatusOK},
+		{"bearer token", "/v1/printers", "Authorization", "Bearer " + apiKey, http.StatusOK},
+		{"healthz is public", "/healthz", "", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, ts.URL+tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			resp, err := ts.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}